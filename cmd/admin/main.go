@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	deliveryhttp "usermanagement/internal/delivery/http"
+	"usermanagement/internal/infra/config"
+	"usermanagement/internal/infra/lock"
+	"usermanagement/internal/infra/logger"
+	"usermanagement/internal/infra/persistence/migrations"
+	"usermanagement/internal/infra/persistence/postgres"
+	"usermanagement/internal/infra/persistence/sqlite"
+	"usermanagement/internal/infra/secrets"
+)
+
+const usage = `usage: admin [--config file] <command> [args]
+
+Commands act on the storage the server is configured with, including
+USER_STORAGE=mongo; memory storage lives in the server process and cannot
+be reached.
+
+commands:
+  db init     apply pending migrations (same as migrate up)
+  db verify   compare the live schema against expectations (postgres only)
+  debug sign <ttl>
+              print an X-Debug-Dump header value valid for ttl (e.g. 15m)
+  user get <id|email>
+              show a user, read directly from the database
+  user list [limit] [offset]
+              list users, newest first (default limit 50)
+  user delete <id>
+              delete a user directly from the database
+  user set-role <id> <admin|editor|viewer>
+              change a user's role, e.g. to bootstrap the first admin
+  user rewrite-domain <old-domain> <new-domain> [--apply]
+              move every user's email to a new domain, auditing the change and
+              notifying each user; previews unless --apply (postgres users only)
+`
+
+func main() {
+	configPath := flag.String("config", "", "YAML or TOML file with settings missing from the environment")
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+
+	if err := run(*configPath, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func run(configPath string, args []string) error {
+	if len(args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		return fmt.Errorf("missing command")
+	}
+
+	secretsCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if _, err := secrets.ResolveAWSEnv(secretsCtx); err != nil {
+		return fmt.Errorf("failed to resolve secrets: %w", err)
+	}
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	switch args[0] + " " + args[1] {
+	case "db init":
+		return dbInit(cfg)
+	case "db verify":
+		return dbVerify(cfg)
+	case "debug sign":
+		return debugSign(cfg, args[2:])
+	case "user get":
+		return userGet(cfg, args[2:])
+	case "user list":
+		return userList(cfg, args[2:])
+	case "user delete":
+		return userDelete(cfg, args[2:])
+	case "user set-role":
+		return userSetRole(cfg, args[2:])
+	case "user rewrite-domain":
+		return userRewriteDomain(cfg, args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		return fmt.Errorf("unknown command %q", args[0]+" "+args[1])
+	}
+}
+
+// requirePostgres fails commands that only work on Postgres when the server
+// keeps its data elsewhere, rather than letting them act on a database the
+// server never reads.
+func requirePostgres(cfg *config.Config, command string) error {
+	if cfg.Storage != "postgres" {
+		return fmt.Errorf("%s needs STORAGE=postgres, the server is configured with STORAGE=%s", command, cfg.Storage)
+	}
+	return nil
+}
+
+// withPool connects to the configured database and runs fn against it.
+func withPool(cfg *config.Config, fn func(ctx context.Context, pool *pgxpool.Pool) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	pool, err := postgres.Connect(ctx, cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	return fn(ctx, pool)
+}
+
+// withLock runs fn while holding the named distributed lock on the primary
+// database, failing fast when another process holds it. fn's context is
+// cancelled if the lease is lost, so whatever it runs on that context stops
+// before another holder starts.
+func withLock(ctx context.Context, cfg *config.Config, name string, fn func(ctx context.Context, token int64) error) error {
+	if err := requirePostgres(cfg, name); err != nil {
+		return err
+	}
+
+	log, err := logger.New(cfg.Environment)
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	defer log.Sync()
+
+	connectCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	pool, err := postgres.Connect(connectCtx, cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	// The lease is renewed for as long as fn runs, past the connect deadline.
+	err = lock.NewLocker(pool, log).WithLock(ctx, name, 30*time.Second, fn)
+	if errors.Is(err, lock.ErrHeld) {
+		return fmt.Errorf("%s is already running elsewhere: %w", name, err)
+	}
+	return err
+}
+
+// forEachDatabase runs fn against the primary database and every user shard.
+func forEachDatabase(cfg *config.Config, command string, fn func(ctx context.Context, name string, pool *pgxpool.Pool) error) error {
+	if err := requirePostgres(cfg, command); err != nil {
+		return err
+	}
+
+	dbs := map[string]config.DatabaseConfig{"primary": cfg.Database}
+	names := []string{"primary"}
+	for i, shard := range cfg.Database.Shards() {
+		name := fmt.Sprintf("shard %d", i)
+		dbs[name] = shard
+		names = append(names, name)
+	}
+
+	for _, name := range names {
+		dbCfg := *cfg
+		dbCfg.Database = dbs[name]
+		err := withPool(&dbCfg, func(ctx context.Context, pool *pgxpool.Pool) error {
+			return fn(ctx, name, pool)
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func dbInit(cfg *config.Config) error {
+	if cfg.Storage == "sqlite" {
+		// Opening the file applies pending migrations, as the server does
+		// on start.
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		db, err := sqlite.Open(ctx, cfg.SQLitePath)
+		if err != nil {
+			return err
+		}
+		db.Close()
+
+		fmt.Printf("%s: schema initialized\n", cfg.SQLitePath)
+		return nil
+	}
+
+	return forEachDatabase(cfg, "db init", func(ctx context.Context, name string, pool *pgxpool.Pool) error {
+		applied, err := migrations.Up(ctx, pool)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s: schema initialized (%d migrations applied)\n", name, len(applied))
+		return nil
+	})
+}
+
+func dbVerify(cfg *config.Config) error {
+	return forEachDatabase(cfg, "db verify", func(ctx context.Context, name string, pool *pgxpool.Pool) error {
+		report, err := postgres.VerifySchema(ctx, pool)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s: %s\n", name, report)
+		if !report.OK() {
+			return fmt.Errorf("schema verification failed")
+		}
+		return nil
+	})
+}
+
+func debugSign(cfg *config.Config, args []string) error {
+	if cfg.DebugDumpSecret == "" {
+		return fmt.Errorf("DEBUG_DUMP_SECRET is not configured")
+	}
+	ttl := 15 * time.Minute
+	if len(args) > 0 {
+		d, err := time.ParseDuration(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid ttl: %w", err)
+		}
+		ttl = d
+	}
+
+	fmt.Printf("%s: %s\n", deliveryhttp.DebugDumpHeader, deliveryhttp.SignDebugDump(cfg.DebugDumpSecret, time.Now().Add(ttl)))
+	return nil
+}
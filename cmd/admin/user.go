@@ -0,0 +1,429 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	osuser "os/user"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"usermanagement/internal/domain/user"
+	"usermanagement/internal/infra/config"
+	"usermanagement/internal/infra/logger"
+	"usermanagement/internal/infra/persistence/mongo"
+	"usermanagement/internal/infra/persistence/postgres"
+	"usermanagement/internal/infra/persistence/sharded"
+	"usermanagement/internal/infra/persistence/sqlite"
+)
+
+// userStore is the user repository the server builds, with what records
+// the changes made through it on the database holding each user.
+type userStore struct {
+	user.UserRepository
+	// databases are the primary, or every shard in shard order; none
+	// when users are kept in MongoDB or SQLite.
+	databases []userDatabase
+	shards    *sharded.UserRepository
+}
+
+// userDatabase is one database holding users. Its audit log, outbox and
+// fences join the transactions of tx, as do its users.
+type userDatabase struct {
+	tx     *postgres.TxManager
+	audit  *postgres.AuditLog
+	events *postgres.OutboxStore
+	fences *postgres.Fences
+}
+
+func newUserDatabase(pool *pgxpool.Pool, log *logger.Logger) userDatabase {
+	return userDatabase{
+		tx:     postgres.NewTxManager(pool, pool, log),
+		audit:  postgres.NewAuditLog(pool),
+		events: postgres.NewOutboxStore(pool),
+		fences: postgres.NewFences(pool),
+	}
+}
+
+// databaseFor returns the database holding the user with the given ID.
+func (s *userStore) databaseFor(id uuid.UUID) userDatabase {
+	if s.shards == nil {
+		return s.databases[0]
+	}
+	return s.databases[s.shards.ShardIndex(id)]
+}
+
+// withUserRepository builds the user repository the way the server does,
+// on MongoDB, the SQLite file or Postgres including shards, and runs fn
+// against it. The circuit breaker is left out: these commands exist for
+// when the service is already unhealthy.
+//
+// Only connecting is bounded by a timeout; fn runs on ctx for as long as it
+// takes, since it may walk every user.
+func withUserRepository(ctx context.Context, cfg *config.Config, fn func(ctx context.Context, repo *userStore) error) error {
+	log, err := logger.New(cfg.Environment)
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	defer log.Sync()
+
+	connectCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if cfg.UserStorage == "mongo" {
+		db, err := mongo.Connect(connectCtx, cfg.Mongo)
+		if err != nil {
+			return err
+		}
+		defer db.Client().Disconnect(context.Background())
+
+		return fn(ctx, &userStore{UserRepository: mongo.NewUserRepository(db, log)})
+	}
+
+	switch cfg.Storage {
+	case "sqlite":
+		db, err := sqlite.Open(connectCtx, cfg.SQLitePath)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		return fn(ctx, &userStore{UserRepository: sqlite.NewUserRepository(db, log)})
+	case "memory":
+		return fmt.Errorf("STORAGE=memory keeps users in the server process, out of reach of the admin tool")
+	}
+
+	pool, err := postgres.Connect(connectCtx, cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	store := &userStore{
+		UserRepository: postgres.NewUserRepository(pool, log),
+		databases:      []userDatabase{newUserDatabase(pool, log)},
+	}
+	if shardCfgs := cfg.Database.Shards(); len(shardCfgs) > 0 {
+		shards := make([]user.UserRepository, 0, len(shardCfgs))
+		store.databases = store.databases[:0]
+		for i, shardCfg := range shardCfgs {
+			shardPool, err := postgres.Connect(connectCtx, shardCfg)
+			if err != nil {
+				return fmt.Errorf("shard %d: %w", i, err)
+			}
+			defer shardPool.Close()
+
+			shards = append(shards, postgres.NewUserRepository(shardPool, log))
+			store.databases = append(store.databases, newUserDatabase(shardPool, log))
+		}
+
+		if store.shards, err = sharded.NewUserRepository(shards); err != nil {
+			return err
+		}
+		store.UserRepository = store.shards
+	}
+
+	return fn(ctx, store)
+}
+
+// userGet looks a user up by ID, or by email when the argument contains "@".
+func userGet(cfg *config.Config, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: admin user get <id|email>")
+	}
+
+	return withUserRepository(context.Background(), cfg, func(ctx context.Context, repo *userStore) error {
+		var u *user.User
+		if strings.Contains(args[0], "@") {
+			found, err := repo.FindByEmail(ctx, args[0])
+			if err != nil {
+				return err
+			}
+			u = found
+		} else {
+			id, err := uuid.Parse(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid user id: %w", err)
+			}
+			found, err := repo.FindByID(ctx, id)
+			if err != nil {
+				return err
+			}
+			u = found
+		}
+
+		printUsers([]*user.User{u})
+		return nil
+	})
+}
+
+func userList(cfg *config.Config, args []string) error {
+	limit, offset := 50, 0
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid limit %q", args[0])
+		}
+		limit = n
+	}
+	if len(args) > 1 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid offset %q", args[1])
+		}
+		offset = n
+	}
+
+	return withUserRepository(context.Background(), cfg, func(ctx context.Context, repo *userStore) error {
+		users, err := repo.FindAll(ctx, limit, offset)
+		if err != nil {
+			return err
+		}
+
+		printUsers(users)
+		return nil
+	})
+}
+
+func userDelete(cfg *config.Config, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: admin user delete <id>")
+	}
+	id, err := uuid.Parse(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid user id: %w", err)
+	}
+
+	return withUserRepository(context.Background(), cfg, func(ctx context.Context, repo *userStore) error {
+		if err := repo.Delete(ctx, id, 0); err != nil {
+			return err
+		}
+
+		fmt.Printf("user %s deleted\n", id)
+		return nil
+	})
+}
+
+func userSetRole(cfg *config.Config, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: admin user set-role <id> <admin|editor|viewer>")
+	}
+	id, err := uuid.Parse(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid user id: %w", err)
+	}
+	role, err := user.ParseRole(args[1])
+	if err != nil {
+		return err
+	}
+
+	return withUserRepository(context.Background(), cfg, func(ctx context.Context, repo *userStore) error {
+		u, err := repo.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := u.ChangeRole(role); err != nil {
+			return err
+		}
+		if err := repo.Update(ctx, u); err != nil {
+			return err
+		}
+
+		fmt.Printf("user %s is now %s\n", id, role)
+		return nil
+	})
+}
+
+// userRewriteDomain moves every user from one email domain to another, e.g.
+// after a company rename. It only previews the changes unless --apply is
+// given; users whose new address is already taken are reported and skipped.
+// Each user moved gets an audit entry and a user.email_changed event, from
+// which they are notified at both addresses.
+func userRewriteDomain(cfg *config.Config, args []string) error {
+	apply := len(args) == 3 && args[2] == "--apply"
+	if len(args) != 2 && !apply {
+		return fmt.Errorf("usage: admin user rewrite-domain <old-domain> <new-domain> [--apply]")
+	}
+	// Each change commits with its audit entry and event in one
+	// transaction, which only Postgres storage records.
+	if err := requirePostgres(cfg, "user rewrite-domain"); err != nil {
+		return err
+	}
+	if cfg.UserStorage == "mongo" {
+		return fmt.Errorf("user rewrite-domain cannot audit or notify users kept in MongoDB (USER_STORAGE=mongo)")
+	}
+	oldDomain := strings.ToLower(strings.TrimPrefix(args[0], "@"))
+	newDomain := strings.ToLower(strings.TrimPrefix(args[1], "@"))
+	if oldDomain == "" || newDomain == "" || oldDomain == newDomain {
+		return fmt.Errorf("old and new domains must be set and differ")
+	}
+
+	// Interrupting stops the rewrite between users and releases the lock.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if !apply {
+		return rewriteDomain(ctx, cfg, oldDomain, newDomain, false, 0)
+	}
+	// Two runs applying at once would report each other's changes as
+	// conflicts, so only one replica or operator may apply at a time.
+	return withLock(ctx, cfg, rewriteDomainLock, func(ctx context.Context, token int64) error {
+		return rewriteDomain(ctx, cfg, oldDomain, newDomain, true, token)
+	})
+}
+
+// rewriteDomainLock is the lock applying domain rewrites run under.
+const rewriteDomainLock = "admin/user-rewrite-domain"
+
+// rewriteDomain moves users from oldDomain to newDomain, or only reports what
+// would change unless apply is set. It stops once ctx is done, e.g. when the
+// lock it runs under is lost. token is that lock's fencing token: every change
+// commits only if no newer holder of the lock has written to the user's
+// database, which stops a run that kept going after its lease lapsed.
+func rewriteDomain(ctx context.Context, cfg *config.Config, oldDomain, newDomain string, apply bool, token int64) error {
+	return withUserRepository(ctx, cfg, func(ctx context.Context, repo *userStore) error {
+		// Collect first, then rewrite, so updated users don't shift the
+		// listing being walked.
+		var matches []*user.User
+		var after *user.Keyset
+		for {
+			page, err := repo.FindAfter(ctx, after, 500)
+			if err != nil {
+				return err
+			}
+			for _, u := range page {
+				if strings.HasSuffix(u.Email(), "@"+oldDomain) {
+					matches = append(matches, u)
+				}
+			}
+			if len(page) < 500 {
+				break
+			}
+			last := page[len(page)-1]
+			after = &user.Keyset{CreatedAt: last.CreatedAt(), ID: last.ID()}
+		}
+
+		actor := operator()
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tOLD EMAIL\tNEW EMAIL\tRESULT")
+		var changed, conflicts, failed int
+		for _, u := range matches {
+			if err := ctx.Err(); err != nil {
+				tw.Flush()
+				return fmt.Errorf("stopped after %d of %d users: %w", changed+conflicts+failed, len(matches), err)
+			}
+			oldEmail := u.Email()
+			newEmail := strings.TrimSuffix(oldEmail, oldDomain) + newDomain
+
+			result, err := rewriteEmail(ctx, repo, u, newEmail, apply, token, postgres.AuditEntry{
+				Actor:     actor,
+				Action:    "user.rewrite_domain",
+				SubjectID: u.ID(),
+				Details: map[string]any{
+					"old_email":  oldEmail,
+					"new_email":  newEmail,
+					"lock_token": token,
+				},
+			})
+			if errors.Is(err, postgres.ErrFenced) {
+				tw.Flush()
+				return fmt.Errorf("stopped after %d of %d users, %s was taken over: %w", changed+conflicts+failed, len(matches), rewriteDomainLock, err)
+			}
+			switch {
+			case errors.Is(err, user.ErrEmailExists):
+				result = "conflict: address taken"
+				conflicts++
+			case err != nil:
+				result = "error: " + err.Error()
+				failed++
+			default:
+				changed++
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", u.ID(), oldEmail, newEmail, result)
+		}
+		tw.Flush()
+
+		verb := "would change"
+		if apply {
+			verb = "changed"
+		}
+		fmt.Printf("\n%d users %s, %d conflicts, %d errors\n", changed, verb, conflicts, failed)
+		if !apply && changed > 0 {
+			fmt.Println("dry run: re-run with --apply to write the changes")
+		}
+		if conflicts+failed > 0 {
+			return fmt.Errorf("%d users were not migrated", conflicts+failed)
+		}
+		return nil
+	})
+}
+
+// rewriteEmail moves u to newEmail, or only checks that it could when apply
+// is false. The unique constraint still catches addresses taken meanwhile.
+// The change commits together with entry and the event notifying u, and only
+// while token passes the fence of rewriteDomainLock.
+func rewriteEmail(ctx context.Context, repo *userStore, u *user.User, newEmail string, apply bool, token int64, entry postgres.AuditEntry) (string, error) {
+	if _, err := repo.FindByEmail(ctx, newEmail); err == nil {
+		return "", user.ErrEmailExists
+	} else if !errors.Is(err, user.ErrUserNotFound) {
+		return "", err
+	}
+
+	change := emailChange{ID: u.ID(), OldEmail: u.Email(), NewEmail: newEmail}
+	if err := u.UpdateEmail(newEmail); err != nil {
+		return "", err
+	}
+	if !apply {
+		return "ok", nil
+	}
+
+	db := repo.databaseFor(u.ID())
+	err := db.tx.Do(ctx, func(ctx context.Context) error {
+		if err := db.fences.Check(ctx, rewriteDomainLock, token); err != nil {
+			return err
+		}
+		if err := repo.Update(ctx, u); err != nil {
+			return err
+		}
+		if err := db.audit.Record(ctx, entry); err != nil {
+			return err
+		}
+		return db.events.Append(ctx, user.TopicEmailChanged, u.ID(), change)
+	})
+	if err != nil {
+		return "", err
+	}
+	return "updated", nil
+}
+
+// emailChange is the payload of user.email_changed events.
+type emailChange struct {
+	ID       uuid.UUID `json:"id"`
+	OldEmail string    `json:"old_email"`
+	NewEmail string    `json:"new_email"`
+}
+
+// operator names who runs the admin tool in audit entries.
+func operator() string {
+	if u, err := osuser.Current(); err == nil {
+		return "admin:" + u.Username
+	}
+	return "admin"
+}
+
+func printUsers(users []*user.User) {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tEMAIL\tROLE\tCREATED")
+	for _, u := range users {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", u.ID(), u.Name(), u.Email(), u.Role(), u.CreatedAt().Format(time.RFC3339))
+	}
+	tw.Flush()
+}
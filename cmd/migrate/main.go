@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"usermanagement/internal/infra/config"
+	"usermanagement/internal/infra/persistence/migrations"
+	"usermanagement/internal/infra/persistence/postgres"
+	"usermanagement/internal/infra/secrets"
+)
+
+const usage = `usage: migrate [--config file] <command>
+
+commands:
+  up       apply pending migrations to the primary database and every shard
+  status   list migrations and whether each database has applied them
+`
+
+func main() {
+	configPath := flag.String("config", "", "YAML or TOML file with settings missing from the environment")
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+
+	if err := run(*configPath, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func run(configPath string, args []string) error {
+	if len(args) != 1 {
+		fmt.Fprint(os.Stderr, usage)
+		return fmt.Errorf("missing command")
+	}
+
+	secretsCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if _, err := secrets.ResolveAWSEnv(secretsCtx); err != nil {
+		return fmt.Errorf("failed to resolve secrets: %w", err)
+	}
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	switch args[0] {
+	case "up":
+		return forEachDatabase(cfg, up)
+	case "status":
+		return forEachDatabase(cfg, status)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+// forEachDatabase runs fn against the primary database and every user shard,
+// in that order, stopping at the first failure.
+func forEachDatabase(cfg *config.Config, fn func(ctx context.Context, name string, pool *pgxpool.Pool) error) error {
+	dbs := append([]config.DatabaseConfig{cfg.Database}, cfg.Database.Shards()...)
+	for i, db := range dbs {
+		name := "primary"
+		if i > 0 {
+			name = fmt.Sprintf("shard %d", i-1)
+		}
+
+		if err := withPool(db, func(ctx context.Context, pool *pgxpool.Pool) error {
+			return fn(ctx, name, pool)
+		}); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func withPool(db config.DatabaseConfig, fn func(ctx context.Context, pool *pgxpool.Pool) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	pool, err := postgres.Connect(ctx, db)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	return fn(ctx, pool)
+}
+
+func up(ctx context.Context, name string, pool *pgxpool.Pool) error {
+	applied, err := migrations.Up(ctx, pool)
+	for _, m := range applied {
+		fmt.Printf("%s: applied %04d_%s\n", name, m.Version, m.Name)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(applied) == 0 {
+		fmt.Printf("%s: up to date\n", name)
+	}
+	return nil
+}
+
+func status(ctx context.Context, name string, pool *pgxpool.Pool) error {
+	statuses, err := migrations.List(ctx, pool)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s:\n", name)
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  VERSION\tNAME\tAPPLIED")
+	for _, s := range statuses {
+		applied := "pending"
+		if s.AppliedAt != nil {
+			applied = s.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(tw, "  %04d\t%s\t%s\n", s.Version, s.Name, applied)
+	}
+	return tw.Flush()
+}
@@ -2,28 +2,75 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
+	"net"
 	"net/http"
 	stdhttp "net/http" // alias standard library
 
 	"os"
 	"os/signal"
+	"slices"
 	"syscall"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 
+	"usermanagement/internal/application/apikey"
+	appauth "usermanagement/internal/application/auth"
+	"usermanagement/internal/application/comment"
+	"usermanagement/internal/application/pagination"
+	"usermanagement/internal/application/post"
+	"usermanagement/internal/application/usecase"
 	"usermanagement/internal/application/user"
+	"usermanagement/internal/application/validation"
+	"usermanagement/internal/application/webhook"
+	domaincomment "usermanagement/internal/domain/comment"
+	domainpost "usermanagement/internal/domain/post"
+	domainuser "usermanagement/internal/domain/user"
+	"usermanagement/internal/infra/auth"
+	"usermanagement/internal/infra/breaker"
+	"usermanagement/internal/infra/cache"
+	"usermanagement/internal/infra/captcha"
 	"usermanagement/internal/infra/config"
+	"usermanagement/internal/infra/deadline"
+	"usermanagement/internal/infra/health"
+	"usermanagement/internal/infra/idgen"
 	"usermanagement/internal/infra/logger"
+	"usermanagement/internal/infra/messaging"
+	"usermanagement/internal/infra/metrics"
+	"usermanagement/internal/infra/moderation"
+	"usermanagement/internal/infra/outbox"
+	"usermanagement/internal/infra/persistence/circuit"
+	"usermanagement/internal/infra/persistence/migrations"
 	"usermanagement/internal/infra/persistence/postgres"
+	"usermanagement/internal/infra/persistence/rediscache"
+	"usermanagement/internal/infra/publicid"
+	"usermanagement/internal/infra/ratelimit"
+	"usermanagement/internal/infra/secrets"
+	webhooksender "usermanagement/internal/infra/webhook"
 
+	deliverygrpc "usermanagement/internal/delivery/grpc"
 	deliveryhttp "usermanagement/internal/delivery/http" // alias your package
 )
 
 func main() {
+	configPath := flag.String("config", "", "read settings missing from the environment from this YAML or TOML file (default config.yaml or config.toml, if present)")
+	flag.Parse()
+
+	// Resolve secretsmanager:// and ssm:// references before reading config
+	secretsCtx, cancelSecrets := context.WithTimeout(context.Background(), 30*time.Second)
+	resolver, err := secrets.ResolveAWSEnv(secretsCtx)
+	cancelSecrets()
+	if err != nil {
+		panic("failed to resolve secrets: " + err.Error())
+	}
+
 	// Load configuration
-	cfg, err := config.Load()
+	cfg, err := config.Load(*configPath)
 	if err != nil {
 		panic("failed to load config: " + err.Error())
 	}
@@ -38,37 +85,375 @@ func main() {
 	log.Info("starting user management service",
 		zap.String("environment", cfg.Environment),
 		zap.String("port", cfg.HTTPPort),
+		zap.String("grpc_port", cfg.GRPCPort),
+		zap.String("config_file", cfg.File),
 	)
 
-	// Database connection
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	// Background work is stopped when main returns
+	bgCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
 
-	pool, err := pgxpool.New(ctx, cfg.DatabaseURL())
+	// Allowed CORS origins follow configuration reloads
+	origins, err := deliveryhttp.NewOriginPolicy(cfg.CORSAllowedOrigins)
 	if err != nil {
-		log.Fatal("failed to connect to database", zap.Error(err))
+		log.Fatal("invalid CORS_ALLOWED_ORIGINS", zap.Error(err))
+	}
+	reloadOrigins := func(keys []string) {
+		if !slices.Contains(keys, "CORS_ALLOWED_ORIGINS") {
+			return
+		}
+		if err := origins.Set(config.CORSOrigins()); err != nil {
+			log.Error("rejected CORS origins reload, keeping previous origins", zap.Error(err))
+			return
+		}
+		log.Info("CORS origins reloaded")
 	}
-	defer pool.Close()
 
-	// Test database connection
-	if err := pool.Ping(ctx); err != nil {
-		log.Fatal("failed to ping database", zap.Error(err))
+	// Secret rotation: re-read the DB password from the (refreshed) environment
+	// for every new connection.
+	refreshSecrets := resolver != nil && cfg.SecretsRefreshInterval > 0
+	if _, fromFile := cfg.FileSources["DB_PASSWORD"]; refreshSecrets || fromFile {
+		cfg.Database.PasswordSource = func() string { return os.Getenv("DB_PASSWORD") }
+	}
+	if refreshSecrets {
+		go resolver.Watch(bgCtx, cfg.SecretsRefreshInterval,
+			func(keys []string) {
+				log.Info("secrets rotated", zap.Strings("keys", keys))
+				reloadOrigins(keys)
+			},
+			func(err error) { log.Error("failed to refresh secrets", zap.Error(err)) },
+		)
+	}
+	if len(cfg.FileSources) > 0 {
+		go func() {
+			err := config.WatchFiles(bgCtx, cfg.FileSources,
+				func(keys []string) {
+					log.Info("config files reloaded", zap.Strings("keys", keys))
+					reloadOrigins(keys)
+				},
+				func(err error) { log.Warn("failed to reload config file", zap.Error(err)) },
+			)
+			if err != nil {
+				log.Error("config file watcher stopped", zap.Error(err))
+			}
+		}()
 	}
-	log.Info("connected to database")
+
+	// Storage, waiting for the database to come up on cold starts
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.StartupTimeout)
+	defer cancel()
+
+	var store *storage
+	switch cfg.Storage {
+	case "memory":
+		log.Warn("using in-memory storage; data is lost on restart")
+		store = openMemory()
+	case "sqlite":
+		store = openSQLite(ctx, cfg, log)
+	default:
+		store = openPostgres(ctx, cfg, log)
+	}
+	if cfg.UserStorage == "mongo" {
+		useMongoUsers(ctx, store, cfg, log)
+	}
+	defer store.close()
 
 	// Dependency Injection
 	// Infra
-	userRepo := postgres.NewUserRepository(pool, log)
+	userRepo := store.users
+	postRepo := store.posts
+	commentRepo := store.comments
+	txManager := store.tx
+	readinessChecks := store.checks
+
+	if cfg.Breaker.Enabled {
+		dbBreaker := breaker.New("postgres", breaker.Settings{
+			FailureThreshold: cfg.Breaker.FailureThreshold,
+			OpenTimeout:      cfg.Breaker.OpenTimeout,
+			IsFailure:        circuit.IsFailure,
+		}, log)
+		userRepo = circuit.NewUserRepository(userRepo, dbBreaker)
+	}
+
+	// Outside the breaker, so cached users are still served while it is open.
+	if cfg.Cache.Enabled {
+		redisOpts, err := redis.ParseURL(cfg.Cache.RedisURL)
+		if err != nil {
+			log.Fatal("invalid REDIS_URL", zap.Error(err))
+		}
+		redisClient := redis.NewClient(redisOpts)
+		defer redisClient.Close()
+		userRepo = rediscache.NewUserRepository(userRepo, redisClient, cfg.Cache.UserTTL, budget(cfg.Budgets.Cache), log)
+		log.Info("user cache enabled", zap.String("redis", redisOpts.Addr))
+	}
+
+	if cfg.BusinessMetricsInterval > 0 {
+		store.onLeader("business_metrics", func(ctx context.Context) {
+			metrics.RefreshBusinessGauges(ctx, cfg.BusinessMetricsInterval,
+				func(ctx context.Context) (metrics.BusinessStats, error) { return businessStats(ctx, store) },
+				func(err error) { log.Warn("failed to refresh business metrics", zap.Error(err)) },
+			)
+		})
+	}
+
+	// User events, relayed from the outbox of every user store
+	var publisher outbox.Publisher
+	if cfg.Outbox.Publisher == "kafka" {
+		kafkaPublisher, err := messaging.NewKafkaPublisher(messaging.Settings{
+			Brokers: cfg.Messaging.Brokers,
+			Topic:   cfg.Messaging.Topic,
+			Format:  cfg.Messaging.Format,
+		})
+		if err != nil {
+			log.Fatal("invalid messaging configuration", zap.Error(err))
+		}
+		defer kafkaPublisher.Close()
+		publisher = kafkaPublisher
+	} else if publisher, err = outbox.NewPublisher(cfg.Outbox.Publisher, cfg.Outbox.URL, log); err != nil {
+		log.Fatal("invalid OUTBOX_PUBLISHER", zap.Error(err))
+	}
+	// Webhook deliveries are queued before the broker is tried, so a broker
+	// outage only delays them; queueing an event twice is a no-op.
+	if cfg.Webhooks.Enabled && store.webhookQueue != nil {
+		publishers := []outbox.Publisher{webhooksender.NewEnqueuer(store.webhookQueue)}
+		if publisher != nil {
+			publishers = append(publishers, publisher)
+		}
+		publisher = outbox.Fanout(publishers...)
+
+		sender := webhooksender.NewSender(store.webhookQueue, webhooksender.Settings{
+			Interval:             cfg.Outbox.Interval,
+			BatchSize:            cfg.Outbox.BatchSize,
+			MaxAttempts:          cfg.Webhooks.MaxAttempts,
+			MaxBackoff:           cfg.Webhooks.MaxBackoff,
+			Timeout:              cfg.Webhooks.Timeout,
+			Retention:            cfg.Webhooks.Retention,
+			AllowPrivateNetworks: cfg.Webhooks.AllowPrivateNetworks,
+		}, log)
+		store.onLeader("webhook_sender", sender.Run)
+	}
+	if publisher != nil {
+		for i, events := range store.outboxes {
+			relay := outbox.NewRelay(events, publisher, outbox.Settings{
+				Interval:   cfg.Outbox.Interval,
+				BatchSize:  cfg.Outbox.BatchSize,
+				MaxBackoff: cfg.Outbox.MaxBackoff,
+				Retention:  cfg.Outbox.Retention,
+			}, log)
+			store.onLeader(fmt.Sprintf("outbox_relay_%d", i), relay.Run)
+		}
+	} else if cfg.Outbox.Retention > 0 {
+		// Nothing consumes the events, so they only need to go.
+		for i, events := range store.outboxes {
+			events := events
+			store.onLeader(fmt.Sprintf("outbox_discard_%d", i), func(ctx context.Context) {
+				outbox.Discard(ctx, events, cfg.Outbox.Retention, log)
+			})
+		}
+	}
+
+	// Application (Use Cases), instrumented with per-use-case metrics
+	validator, err := validation.New()
+	if err != nil {
+		log.Fatal("failed to create validator", zap.Error(err))
+	}
+	if err := user.RegisterValidators(validator, user.ValidationRules{
+		BlockedEmailDomains:    cfg.BlockedEmailDomains,
+		DisposableEmailDomains: cfg.Signup.DisposableEmailDomains,
+	}); err != nil {
+		log.Fatal("failed to register validators", zap.Error(err))
+	}
+
+	// Further policies, e.g. an external moderation API, join the chain.
+	contentPolicy := moderation.Reported(moderation.Chain(
+		moderation.NewWordList(cfg.ContentRejectWords, cfg.ContentFlagWords),
+	), log)
+
+	ids, err := idgen.New(cfg.IDVersion)
+	if err != nil {
+		log.Fatal("invalid ID_VERSION", zap.Error(err))
+	}
+
+	tokens, err := auth.NewJWTIssuer(cfg.Auth.JWTSecret, cfg.Auth.JWTIssuer, cfg.Auth.AccessTokenTTL, cfg.Auth.RefreshTokenTTL)
+	if err != nil {
+		log.Fatal("invalid JWT configuration", zap.Error(err))
+	}
+
+	hasher, err := auth.NewBcryptHasher(cfg.Auth.BcryptCost)
+	if err != nil {
+		log.Fatal("invalid BCRYPT_COST", zap.Error(err))
+	}
+
+	createUser := user.NewCreateUserUseCase(userRepo, ids, hasher, contentPolicy, validator)
+	createUC := metrics.UseCase[user.CreateUserInput, *user.UserOutput]("create_user", createUser)
+	captchaVerifier, err := captcha.New(cfg.Signup.CaptchaProvider, cfg.Signup.CaptchaSecret, budget(cfg.Budgets.External))
+	if err != nil {
+		log.Fatal("invalid CAPTCHA configuration", zap.Error(err))
+	}
+	if cfg.Signup.CaptchaProvider == "none" {
+		log.Warn("signup CAPTCHA is disabled")
+	}
+	// Counted in process memory: the effective limit scales with the
+	// number of replicas.
+	signupLimiter := ratelimit.NewWindow(cfg.Signup.IPLimit, cfg.Signup.IPWindow)
+	// Activation tokens reach the new user through the outbox; the
+	// configuration refuses activation where nothing relays from it.
+	var (
+		activations      domainuser.ActivationRepository
+		signupActivation *user.SignupActivation
+		activateUC       usecase.UseCase[user.ActivateUserInput, *user.UserOutput]
+	)
+	if cfg.Signup.Activation && store.activations != nil {
+		activations = store.activations
+		signupActivation = &user.SignupActivation{Activations: activations, Tx: txManager, TTL: cfg.Signup.ActivationTTL}
+		activateUC = metrics.UseCase[user.ActivateUserInput, *user.UserOutput]("activate_user", user.NewActivateUserUseCase(activations, userRepo, validator))
+	}
+	signupUC := metrics.UseCase[user.SignupInput, *user.UserOutput]("signup", user.NewSignupUseCase(createUser, captchaVerifier, signupLimiter, validator, signupActivation))
+	createOrGetUC := metrics.UseCase[user.CreateUserInput, *user.CreateOrGetUserOutput]("create_or_get_user", user.NewCreateOrGetUserUseCase(createUser, userRepo, hasher))
+	getUC := metrics.UseCase[uuid.UUID, *user.UserOutput]("get_user", user.NewGetUserUseCase(userRepo))
+	bulkCreateUC := metrics.UseCase[user.BulkCreateUsersInput, *user.BulkCreateUsersOutput]("bulk_create_users", user.NewBulkCreateUsersUseCase(userRepo, ids, hasher, contentPolicy, validator))
+	listUC := metrics.UseCase[pagination.Params, *pagination.Page[user.UserOutput]]("list_users", user.NewListUsersUseCase(userRepo))
+	searchUC := metrics.UseCase[user.SearchUsersInput, *pagination.Page[user.UserOutput]]("search_users", user.NewSearchUsersUseCase(userRepo, validator))
+	updateUC := metrics.UseCase[user.UpdateUserInput, *user.UserOutput]("update_user", user.NewUpdateUserUseCase(userRepo, txManager, contentPolicy, validator))
+	deleteUser := user.NewDeleteUserUseCase(userRepo, txManager)
+	deleteUC := metrics.Command[user.DeleteUserInput]("delete_user", deleteUser)
+	loginUC := metrics.UseCase[user.LoginUserInput, *appauth.Tokens]("login_user", user.NewLoginUserUseCase(userRepo, activations, tokens, hasher, validator))
+	refreshUC := metrics.UseCase[user.RefreshTokenInput, *appauth.Tokens]("refresh_token", user.NewRefreshTokenUseCase(userRepo, tokens, validator))
+	createPostUC := metrics.UseCase[post.CreatePostInput, *post.PostOutput]("create_post", post.NewCreatePostUseCase(postRepo, ids, contentPolicy, validator))
+	getPostUC := metrics.UseCase[uuid.UUID, *post.PostOutput]("get_post", post.NewGetPostUseCase(postRepo))
+	listPostsUC := metrics.UseCase[pagination.Params, *pagination.Page[post.PostOutput]]("list_posts", post.NewListPostsUseCase(postRepo))
+	updatePostUC := metrics.UseCase[post.UpdatePostInput, *post.PostOutput]("update_post", post.NewUpdatePostUseCase(postRepo, txManager, contentPolicy, validator))
+	deletePost := post.NewDeletePostUseCase(postRepo)
+	deletePostUC := metrics.Command[uuid.UUID]("delete_post", deletePost)
+	createCommentUC := metrics.UseCase[comment.CreateCommentInput, *comment.CommentOutput]("create_comment", comment.NewCreateCommentUseCase(commentRepo, postRepo, ids, contentPolicy, validator))
+	listCommentsUC := metrics.UseCase[comment.ListCommentsInput, *pagination.Page[comment.CommentOutput]]("list_comments", comment.NewListCommentsUseCase(commentRepo, postRepo, validator))
+	moderateCommentUC := metrics.UseCase[comment.ModerateCommentInput, *comment.CommentOutput]("moderate_comment", comment.NewModerateCommentUseCase(commentRepo, postRepo, validator))
+	deleteComment := comment.NewDeleteCommentUseCase(commentRepo, postRepo)
+	deleteCommentUC := metrics.Command[comment.DeleteCommentInput]("delete_comment", deleteComment)
+	createWebhookUC := metrics.UseCase[webhook.CreateWebhookInput, *webhook.CreatedWebhookOutput]("create_webhook", webhook.NewCreateWebhookUseCase(store.webhooks, ids, validator))
+	listWebhooksUC := metrics.UseCase[pagination.Params, *pagination.Page[webhook.WebhookOutput]]("list_webhooks", webhook.NewListWebhooksUseCase(store.webhooks))
+	deleteWebhook := webhook.NewDeleteWebhookUseCase(store.webhooks)
+	deleteWebhookUC := metrics.Command[uuid.UUID]("delete_webhook", deleteWebhook)
+	listDeliveriesUC := metrics.UseCase[webhook.ListDeliveriesInput, *pagination.Page[webhook.DeliveryOutput]]("list_webhook_deliveries", webhook.NewListDeliveriesUseCase(store.webhooks, store.deliveries))
+	createAPIKeyUC := metrics.UseCase[apikey.CreateAPIKeyInput, *apikey.CreatedAPIKeyOutput]("create_api_key", apikey.NewCreateAPIKeyUseCase(store.apiKeys, ids, validator))
+	listAPIKeysUC := metrics.UseCase[pagination.Params, *pagination.Page[apikey.APIKeyOutput]]("list_api_keys", apikey.NewListAPIKeysUseCase(store.apiKeys))
+	revokeAPIKey := apikey.NewRevokeAPIKeyUseCase(store.apiKeys)
+	revokeAPIKeyUC := metrics.Command[uuid.UUID]("revoke_api_key", revokeAPIKey)
+	apiKeys := apikey.NewVerifier(store.apiKeys, userRepo)
+
+	// Anonymous reads are cached; writes purge what they change by tag.
+	if cfg.PublicCache.TTL > 0 {
+		settings := cache.Settings{
+			TTL:        cfg.PublicCache.TTL,
+			Stale:      cfg.PublicCache.Stale,
+			MaxEntries: cfg.PublicCache.MaxEntries,
+		}
+		postCache := cache.New[*post.PostOutput]("post", settings)
+		postListCache := cache.New[*pagination.Page[post.PostOutput]]("post_list", settings)
+		commentListCache := cache.New[*pagination.Page[comment.CommentOutput]]("comment_list", settings)
+
+		postTag := func(id uuid.UUID) string { return "post:" + id.String() }
+		commentsTag := func(postID uuid.UUID) string { return "comments:" + postID.String() }
 
-	// Application (Use Cases)
-	createUC := user.NewCreateUserUseCase(userRepo)
-	getUC := user.NewGetUserUseCase(userRepo)
-	updateUC := user.NewUpdateUserUseCase(userRepo)
-	deleteUC := user.NewDeleteUserUseCase(userRepo)
+		getPostUC = cache.UseCase(postCache, func(id uuid.UUID) (string, []string) {
+			return id.String(), []string{postTag(id)}
+		}, getPostUC)
+		listPostsUC = cache.UseCase(postListCache, func(p pagination.Params) (string, []string) {
+			return fmt.Sprintf("%d:%d:%s", p.Limit, p.Offset, p.Cursor), []string{"posts"}
+		}, listPostsUC)
+		listCommentsUC = cache.UseCase(commentListCache, func(in comment.ListCommentsInput) (string, []string) {
+			return fmt.Sprintf("%s:%s:%d:%d", in.PostID, in.Status, in.Page.Limit, in.Page.Offset), []string{commentsTag(in.PostID)}
+		}, listCommentsUC)
+
+		updatePostUC = cache.Purging(updatePostUC, func(in post.UpdatePostInput, _ *post.PostOutput) []string {
+			return []string{postTag(in.ID), "posts"}
+		}, postCache, postListCache)
+		deletePostUC = cache.PurgingCommand(deletePostUC, func(id uuid.UUID) []string {
+			return []string{postTag(id), "posts", commentsTag(id)}
+		}, postCache, postListCache, commentListCache)
+		createCommentUC = cache.Purging(createCommentUC, func(in comment.CreateCommentInput, _ *comment.CommentOutput) []string {
+			return []string{commentsTag(in.PostID)}
+		}, commentListCache)
+		moderateCommentUC = cache.Purging(moderateCommentUC, func(in comment.ModerateCommentInput, _ *comment.CommentOutput) []string {
+			return []string{commentsTag(in.PostID)}
+		}, commentListCache)
+		deleteCommentUC = cache.PurgingCommand(deleteCommentUC, func(in comment.DeleteCommentInput) []string {
+			return []string{commentsTag(in.PostID)}
+		}, commentListCache)
+	}
+
+	// Destructive use cases only preview their changes under ?dry_run=true.
+	deleteUC = usecase.DryRunCommand(deleteUC, deleteUser)
+	deletePostUC = usecase.DryRunCommand(deletePostUC, deletePost)
+	deleteCommentUC = usecase.DryRunCommand(deleteCommentUC, deleteComment)
+	deleteWebhookUC = usecase.DryRunCommand(deleteWebhookUC, deleteWebhook)
+	revokeAPIKeyUC = usecase.DryRunCommand(revokeAPIKeyUC, revokeAPIKey)
 
 	// Delivery
-	handler := deliveryhttp.NewUserHandler(createUC, getUC, updateUC, deleteUC, log)
-	router := deliveryhttp.NewRouter(handler, log)
+	var publicIDs deliveryhttp.PublicIDs
+	if publicIDs.Users, err = publicid.New(cfg.PublicIDs.Mode, cfg.PublicIDs.Secret, "user"); err != nil {
+		log.Fatal("invalid public ID configuration", zap.Error(err))
+	}
+	if publicIDs.Posts, err = publicid.New(cfg.PublicIDs.Mode, cfg.PublicIDs.Secret, "post"); err != nil {
+		log.Fatal("invalid public ID configuration", zap.Error(err))
+	}
+	handler := deliveryhttp.NewUserHandler(createUC, createOrGetUC, bulkCreateUC, getUC, listUC, searchUC, updateUC, deleteUC, publicIDs, log)
+	postHandler := deliveryhttp.NewPostHandler(createPostUC, getPostUC, listPostsUC, updatePostUC, deletePostUC, publicIDs, log)
+	commentHandler := deliveryhttp.NewCommentHandler(createCommentUC, listCommentsUC, moderateCommentUC, deleteCommentUC, publicIDs, log)
+	authHandler := deliveryhttp.NewAuthHandler(loginUC, refreshUC, log)
+	signupHandler := deliveryhttp.NewSignupHandler(signupUC, activateUC, publicIDs, log)
+	var webhookHandler *deliveryhttp.WebhookHandler
+	if cfg.Webhooks.Enabled {
+		webhookHandler = deliveryhttp.NewWebhookHandler(createWebhookUC, listWebhooksUC, deleteWebhookUC, listDeliveriesUC, publicIDs, log)
+	}
+	apiKeyHandler := deliveryhttp.NewAPIKeyHandler(createAPIKeyUC, listAPIKeysUC, revokeAPIKeyUC, publicIDs, log)
+	faultRules, err := deliveryhttp.ParseFaultRules(cfg.FaultInjectionRules)
+	if err != nil {
+		log.Fatal("invalid fault injection rules", zap.Error(err))
+	}
+	clientProfiles, err := deliveryhttp.ParseClientProfiles(cfg.ClientProfiles)
+	if err != nil {
+		log.Fatal("invalid CLIENT_PROFILES", zap.Error(err))
+	}
+	trustedProxies, err := deliveryhttp.ParseTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		log.Fatal("invalid TRUSTED_PROXIES", zap.Error(err))
+	}
+
+	// Retries are refused while the first request may still be running,
+	// so the lock outlasts the request timeout.
+	var idempotencyOpts *deliveryhttp.IdempotencyOptions
+	if cfg.IdempotencyTTL > 0 {
+		idempotencyOpts = &deliveryhttp.IdempotencyOptions{
+			Store: store.idempotency,
+			TTL:   cfg.IdempotencyTTL,
+			Lock:  max(2*cfg.RequestTimeout, time.Minute),
+		}
+		if store.idempotencyTable != nil {
+			store.onLeader("idempotency_cleanup", func(ctx context.Context) {
+				store.idempotencyTable.Run(ctx, time.Hour)
+			})
+		}
+	}
+	store.runJobs(bgCtx, log)
+
+	deprecations := deliveryhttp.NewDeprecationRegistry(log)
+	inFlight := deliveryhttp.NewInFlightTracker()
+	readiness := health.NewChecker(log, readinessChecks...)
+	router := deliveryhttp.NewRouter(handler, postHandler, commentHandler, authHandler, signupHandler, webhookHandler, apiKeyHandler, tokens, apiKeys, deprecations, deliveryhttp.RouterOptions{
+		FaultRules: faultRules,
+		DebugDump: deliveryhttp.DebugDumpOptions{
+			Global: cfg.DebugDump,
+			Secret: cfg.DebugDumpSecret,
+		},
+		Origins:        origins,
+		RequestTimeout: cfg.RequestTimeout,
+		InFlight:       inFlight,
+		Readiness:      readiness,
+		ClientProfiles: clientProfiles,
+		Idempotency:    idempotencyOpts,
+		TrustedProxies: trustedProxies,
+	}, log)
 
 	// HTTP Server
 	srv := &stdhttp.Server{
@@ -79,6 +464,13 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	// gRPC Server, serving the same user use cases as the HTTP API
+	grpcSrv := deliverygrpc.NewServer(deliverygrpc.NewUserServer(createUC, getUC, listUC, updateUC, deleteUC, log), tokens, log)
+	grpcListener, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+	if err != nil {
+		log.Fatal("could not listen on", zap.String("addr", ":"+cfg.GRPCPort), zap.Error(err))
+	}
+
 	// Graceful shutdown
 	done := make(chan bool, 1)
 	quit := make(chan os.Signal, 1)
@@ -86,18 +478,53 @@ func main() {
 
 	go func() {
 		<-quit
-		log.Info("server is shutting down...")
+		log.Info("server is shutting down...", zap.Int64("in_flight", inFlight.Count()))
 
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		// Report not ready and keep serving while load balancers deregister us
+		readiness.Drain()
+		if cfg.ShutdownPreStopDelay > 0 {
+			log.Info("waiting for load balancer deregistration", zap.Duration("delay", cfg.ShutdownPreStopDelay))
+			time.Sleep(cfg.ShutdownPreStopDelay)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
 		defer cancel()
 
+		// Drain gRPC calls alongside HTTP requests, under the same deadline
+		grpcStopped := make(chan struct{})
+		go func() {
+			grpcSrv.GracefulStop()
+			close(grpcStopped)
+		}()
+
 		srv.SetKeepAlivesEnabled(false)
 		if err := srv.Shutdown(ctx); err != nil {
-			log.Fatal("could not gracefully shutdown the server", zap.Error(err))
+			log.Error("in-flight requests did not drain in time",
+				zap.Int64("in_flight", inFlight.Count()),
+				zap.Error(err),
+			)
+			srv.Close()
+		}
+
+		select {
+		case <-grpcStopped:
+		case <-ctx.Done():
+			log.Error("in-flight gRPC calls did not drain in time")
+			grpcSrv.Stop()
 		}
+
+		// Stop background work (metrics refresh, watchers) once traffic is gone
+		stopBackground()
 		close(done)
 	}()
 
+	go func() {
+		log.Info("gRPC server is ready to handle requests", zap.String("addr", grpcListener.Addr().String()))
+		if err := grpcSrv.Serve(grpcListener); err != nil {
+			log.Fatal("gRPC server stopped", zap.Error(err))
+		}
+	}()
+
 	log.Info("server is ready to handle requests", zap.String("addr", srv.Addr))
 
 	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -106,4 +533,85 @@ func main() {
 
 	<-done
 	log.Info("server stopped")
-}
\ No newline at end of file
+}
+
+// database returns the pool as seen by the repositories: statements are
+// bounded by the database budget and carry request ID comments when
+// configured.
+func database(cfg *config.Config, pool *pgxpool.Pool) postgres.DB {
+	db := postgres.WithBudget(pool, budget(cfg.Budgets.Database))
+	if cfg.Database.QueryComments {
+		return postgres.WithRequestComments(db)
+	}
+	return db
+}
+
+// budget converts a configured dependency budget.
+func budget(b config.DependencyBudget) deadline.Budget {
+	return deadline.Budget{Share: b.Share, Floor: b.Floor}
+}
+
+// dbCheck builds the readiness check for one database pool.
+func dbCheck(cfg *config.Config, name string, pool *pgxpool.Pool) health.Check {
+	return health.Check{
+		Name:             name,
+		Probe:            pool.Ping,
+		Timeout:          cfg.Readiness.DBTimeout,
+		FailureThreshold: cfg.Readiness.DBFailureThreshold,
+	}
+}
+
+// businessStats sums the user counts across every user store and counts
+// published posts and comments awaiting moderation.
+func businessStats(ctx context.Context, store *storage) (metrics.BusinessStats, error) {
+	var stats metrics.BusinessStats
+	since := time.Now().Add(-24 * time.Hour)
+	for _, users := range store.userStores {
+		total, created, err := users.CountUsers(ctx, since)
+		if err != nil {
+			return metrics.BusinessStats{}, err
+		}
+		stats.TotalUsers += total
+		stats.UsersCreated24h += created
+	}
+
+	published, err := store.posts.CountByStatus(ctx, domainpost.StatusPublished)
+	if err != nil {
+		return metrics.BusinessStats{}, err
+	}
+	pending, err := store.comments.CountByStatus(ctx, domaincomment.StatusPending)
+	if err != nil {
+		return metrics.BusinessStats{}, err
+	}
+	stats.PublishedPosts = published
+	stats.PendingComments = pending
+	return stats, nil
+}
+
+// applyMigrations brings the database schema up to date, stopping startup
+// when a migration fails.
+func applyMigrations(pool *pgxpool.Pool, log *logger.Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	applied, err := migrations.Up(ctx, pool)
+	for _, m := range applied {
+		log.Info("applied migration", zap.Int64("version", m.Version), zap.String("name", m.Name))
+	}
+	if err != nil {
+		log.Fatal("failed to migrate database", zap.Error(err))
+	}
+}
+
+// verifySchema stops startup when the database schema has drifted.
+func verifySchema(ctx context.Context, pool *pgxpool.Pool, log *logger.Logger) {
+	report, err := postgres.VerifySchema(ctx, pool)
+	if err != nil {
+		log.Fatal("failed to verify database schema", zap.Error(err))
+	}
+	if !report.OK() {
+		log.Fatal("database schema does not match expectations; run `migrate up` or fix the drift",
+			zap.Strings("problems", report.Problems),
+		)
+	}
+}
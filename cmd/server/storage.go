@@ -0,0 +1,315 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+
+	domainapikey "usermanagement/internal/domain/apikey"
+	domaincomment "usermanagement/internal/domain/comment"
+	domainpost "usermanagement/internal/domain/post"
+	"usermanagement/internal/domain/transaction"
+	domainuser "usermanagement/internal/domain/user"
+	domainwebhook "usermanagement/internal/domain/webhook"
+	"usermanagement/internal/infra/config"
+	"usermanagement/internal/infra/health"
+	"usermanagement/internal/infra/idempotency"
+	"usermanagement/internal/infra/leader"
+	"usermanagement/internal/infra/lock"
+	"usermanagement/internal/infra/logger"
+	"usermanagement/internal/infra/persistence/memory"
+	"usermanagement/internal/infra/persistence/mongo"
+	"usermanagement/internal/infra/persistence/postgres"
+	"usermanagement/internal/infra/persistence/sharded"
+	"usermanagement/internal/infra/persistence/sqlite"
+)
+
+// storage holds the repositories the use cases run on.
+type storage struct {
+	users      domainuser.UserRepository
+	posts      domainpost.PostRepository
+	comments   domaincomment.CommentRepository
+	tx         transaction.UnitOfWork
+	webhooks   domainwebhook.SubscriptionRepository
+	deliveries domainwebhook.DeliveryRepository
+	apiKeys    domainapikey.Repository
+	// activations hold back signups until they are activated; memory and
+	// sqlite storage have none, since no event could carry the token.
+	activations domainuser.ActivationRepository
+	// webhookQueue holds pending webhook deliveries; memory and sqlite
+	// storage have none.
+	webhookQueue *postgres.WebhookRepository
+	// idempotency keeps the responses replayed to retried writes;
+	// idempotencyTable is the same store when it needs cleaning up.
+	idempotency      idempotency.Store
+	idempotencyTable *idempotency.PostgresStore
+	// userStores hold the users: the primary, every shard, or MongoDB.
+	userStores []userCounter
+	// outboxes hold the user events of every user store; memory and
+	// sqlite storage have none.
+	outboxes []*postgres.OutboxStore
+	checks   []health.Check
+	// election picks the replica that runs the background jobs; memory and
+	// sqlite storage serve a single replica and have none.
+	election leader.Lock
+	jobs     []job
+	// close releases connections once the server has stopped.
+	close func()
+}
+
+// job is a background job that runs on the leader only.
+type job struct {
+	name string
+	run  func(ctx context.Context)
+}
+
+// onLeader registers a background job for runJobs.
+func (s *storage) onLeader(name string, run func(ctx context.Context)) {
+	s.jobs = append(s.jobs, job{name: name, run: run})
+}
+
+// runJobs runs every registered job in the background on exactly one replica
+// at a time, the one holding the leader lock, and stops them all when
+// leadership is lost. Without an election the jobs simply run.
+//
+// All jobs share one election so the leader holds a single lock, and with it
+// at most one database connection, however many jobs there are.
+func (s *storage) runJobs(ctx context.Context, log *logger.Logger) {
+	if len(s.jobs) == 0 {
+		return
+	}
+	names := make([]string, len(s.jobs))
+	for i, j := range s.jobs {
+		names[i] = j.name
+	}
+	log.Info("starting background jobs", zap.Strings("jobs", names))
+
+	jobs := s.jobs
+	runAll := func(ctx context.Context) {
+		var wg sync.WaitGroup
+		for _, j := range jobs {
+			wg.Add(1)
+			go func(j job) {
+				defer wg.Done()
+				j.run(ctx)
+			}(j)
+		}
+		wg.Wait()
+	}
+	if s.election == nil {
+		go runAll(ctx)
+		return
+	}
+	go leader.NewElector(s.election, "background_jobs", log).Run(ctx, runAll)
+}
+
+// leaderLock returns the lock replicas on the primary database compete for:
+// an advisory lock on a connection of its own, or a lease when the database
+// sits behind PgBouncer in transaction pooling mode, where session locks do
+// not hold.
+func leaderLock(cfg *config.Config, db postgres.DB, log *logger.Logger) leader.Lock {
+	const name = "background_jobs"
+	if cfg.Database.SimpleProtocol {
+		return leader.NewLeaseLock(lock.NewLocker(db, log), name, 30*time.Second, log)
+	}
+	return leader.NewAdvisoryLock(func(ctx context.Context) (*pgx.Conn, error) {
+		return postgres.Dial(ctx, cfg.Database)
+	}, name, log)
+}
+
+// userCounter reports the business counts of one user store.
+type userCounter interface {
+	CountUsers(ctx context.Context, since time.Time) (total, created int64, err error)
+}
+
+// openMemory returns empty in-memory storage.
+func openMemory() *storage {
+	users := memory.NewUserRepository()
+	comments := memory.NewCommentRepository()
+	webhooks := memory.NewWebhookRepository()
+	return &storage{
+		users:       users,
+		posts:       memory.NewPostRepository(comments),
+		comments:    comments,
+		tx:          memory.NewUnitOfWork(),
+		webhooks:    webhooks,
+		deliveries:  webhooks,
+		apiKeys:     memory.NewAPIKeyRepository(),
+		idempotency: idempotency.NewMemoryStore(),
+		userStores:  []userCounter{users},
+		close:       func() {},
+	}
+}
+
+// openSQLite opens the database file of cfg, creating it with its schema
+// on first start.
+func openSQLite(ctx context.Context, cfg *config.Config, log *logger.Logger) *storage {
+	db, err := sqlite.Open(ctx, cfg.SQLitePath)
+	if err != nil {
+		log.Fatal("failed to open database", zap.String("path", cfg.SQLitePath), zap.Error(err))
+	}
+
+	log.Info("opened database", zap.String("path", cfg.SQLitePath))
+
+	users := sqlite.NewUserRepository(db, log)
+	webhooks := sqlite.NewWebhookRepository(db, log)
+	return &storage{
+		users:      users,
+		posts:      sqlite.NewPostRepository(db, log),
+		comments:   sqlite.NewCommentRepository(db, log),
+		tx:         sqlite.NewTxManager(db, log),
+		webhooks:   webhooks,
+		deliveries: webhooks,
+		apiKeys:    sqlite.NewAPIKeyRepository(db, log),
+		// Idempotency keys only need to outlive client retries.
+		idempotency: idempotency.NewMemoryStore(),
+		userStores:  []userCounter{users},
+		checks: []health.Check{{
+			Name:             "sqlite",
+			Probe:            db.PingContext,
+			Timeout:          cfg.Readiness.DBTimeout,
+			FailureThreshold: cfg.Readiness.DBFailureThreshold,
+		}},
+		close: func() { db.Close() },
+	}
+}
+
+// openPostgres connects to the primary database and any user shards,
+// waiting for them to come up on cold starts.
+func openPostgres(ctx context.Context, cfg *config.Config, log *logger.Logger) *storage {
+	pool, err := health.WaitFor(ctx, "postgres", log, func(ctx context.Context) (*pgxpool.Pool, error) {
+		return postgres.Connect(ctx, cfg.Database)
+	})
+	if err != nil {
+		log.Fatal("failed to connect to database", zap.Error(err))
+	}
+	pools := []*pgxpool.Pool{pool}
+
+	if cfg.Database.AutoMigrate {
+		applyMigrations(pool, log)
+	}
+	if cfg.Database.VerifySchema {
+		verifySchema(ctx, pool, log)
+	}
+
+	log.Info("connected to database")
+
+	// Repositories on the primary share one DB value so they join the
+	// transactions of the unit of work.
+	primaryDB := database(cfg, pool)
+	primaryRepo := postgres.NewUserRepository(primaryDB, log)
+	webhookRepo := postgres.NewWebhookRepository(primaryDB, log)
+	idempotencyStore := idempotency.NewPostgresStore(primaryDB, log)
+	s := &storage{
+		users: primaryRepo,
+		// Posts always live on the primary database, even when users are
+		// sharded.
+		posts:    postgres.NewPostRepository(primaryDB, log),
+		comments: postgres.NewCommentRepository(primaryDB, log),
+		tx:       postgres.NewTxManager(pool, primaryDB, log),
+		// Webhooks live on the primary database too; every shard's
+		// outbox feeds the one queue.
+		webhooks:     webhookRepo,
+		deliveries:   webhookRepo,
+		webhookQueue: webhookRepo,
+		// So do API keys and idempotency keys, whichever shard the
+		// request touches.
+		apiKeys:          postgres.NewAPIKeyRepository(primaryDB, log),
+		activations:      postgres.NewActivationRepository(primaryDB, log),
+		idempotency:      idempotencyStore,
+		idempotencyTable: idempotencyStore,
+		userStores:       []userCounter{primaryRepo},
+		outboxes:         []*postgres.OutboxStore{postgres.NewOutboxStore(primaryDB)},
+		checks:           []health.Check{dbCheck(cfg, "postgres", pool)},
+		election:         leaderLock(cfg, primaryDB, log),
+		close: func() {
+			for _, p := range pools {
+				p.Close()
+			}
+		},
+	}
+
+	// Users kept in MongoDB need no shards.
+	if shardCfgs := cfg.Database.Shards(); len(shardCfgs) > 0 && cfg.UserStorage != "mongo" {
+		shards := make([]domainuser.UserRepository, 0, len(shardCfgs))
+		s.userStores = s.userStores[:0]
+		// The primary's outbox stays: activation events are written there.
+		for i, shardCfg := range shardCfgs {
+			shardPool, err := health.WaitFor(ctx, fmt.Sprintf("postgres_shard_%d", i), log, func(ctx context.Context) (*pgxpool.Pool, error) {
+				return postgres.Connect(ctx, shardCfg)
+			})
+			if err != nil {
+				log.Fatal("failed to connect to user shard", zap.Int("shard", i), zap.Error(err))
+			}
+			pools = append(pools, shardPool)
+
+			if cfg.Database.AutoMigrate {
+				applyMigrations(shardPool, log)
+			}
+			if cfg.Database.VerifySchema {
+				verifySchema(ctx, shardPool, log)
+			}
+			s.checks = append(s.checks, dbCheck(cfg, fmt.Sprintf("postgres_shard_%d", i), shardPool))
+			shardDB := database(cfg, shardPool)
+			shardRepo := postgres.NewUserRepository(shardDB, log)
+			shards = append(shards, shardRepo)
+			s.userStores = append(s.userStores, shardRepo)
+			s.outboxes = append(s.outboxes, postgres.NewOutboxStore(shardDB))
+		}
+
+		s.users, err = sharded.NewUserRepository(shards)
+		if err != nil {
+			log.Fatal("failed to set up user shards", zap.Error(err))
+		}
+		log.Info("user sharding enabled", zap.Int("shards", len(shards)))
+	}
+
+	return s
+}
+
+// useMongoUsers moves the users of s to MongoDB, waiting for it to come up
+// on cold starts.
+//
+// User writes to MongoDB emit no events, which is why the configuration
+// refuses mongo with a publisher or webhooks, and they run outside the unit
+// of work of s; updates still only apply to the version they were read at.
+func useMongoUsers(ctx context.Context, s *storage, cfg *config.Config, log *logger.Logger) {
+	db, err := health.WaitFor(ctx, "mongo", log, func(ctx context.Context) (*mongodriver.Database, error) {
+		return mongo.Connect(ctx, cfg.Mongo)
+	})
+	if err != nil {
+		log.Fatal("failed to connect to MongoDB", zap.Error(err))
+	}
+
+	users := mongo.NewUserRepository(db, log)
+	if err := users.EnsureIndexes(ctx); err != nil {
+		log.Fatal("failed to prepare MongoDB", zap.Error(err))
+	}
+
+	log.Info("connected to MongoDB", zap.String("database", cfg.Mongo.Database))
+
+	s.users = users
+	s.userStores = []userCounter{users}
+	// The outboxes of s only carry user events then: signup activation,
+	// which writes to the primary's, is refused with mongo.
+	s.outboxes = nil
+	s.checks = append(s.checks, health.Check{
+		Name: "mongo",
+		Probe: func(ctx context.Context) error {
+			return db.Client().Ping(ctx, nil)
+		},
+		Timeout:          cfg.Readiness.DBTimeout,
+		FailureThreshold: cfg.Readiness.DBFailureThreshold,
+	})
+	closeStorage := s.close
+	s.close = func() {
+		closeStorage()
+		db.Client().Disconnect(context.Background())
+	}
+}
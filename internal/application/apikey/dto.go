@@ -0,0 +1,45 @@
+package apikey
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"usermanagement/internal/domain/apikey"
+)
+
+// CreateAPIKeyInput represents data needed to issue an API key.
+type CreateAPIKeyInput struct {
+	Name   string         `json:"name" validate:"required,notblank,max=100"`
+	Scopes []apikey.Scope `json:"scopes" validate:"required,min=1,dive,oneof=users:read users:write posts:read posts:write comments:read comments:write webhooks:read webhooks:write"`
+}
+
+// APIKeyOutput represents API key data returned to clients. The key itself
+// is only returned when it is issued; Prefix tells keys apart afterwards.
+type APIKeyOutput struct {
+	ID        uuid.UUID      `json:"id"`
+	Name      string         `json:"name"`
+	Prefix    string         `json:"prefix"`
+	Scopes    []apikey.Scope `json:"scopes"`
+	CreatedAt time.Time      `json:"created_at"`
+	RevokedAt *time.Time     `json:"revoked_at,omitempty"`
+}
+
+// CreatedAPIKeyOutput is returned once, when a key is issued.
+type CreatedAPIKeyOutput struct {
+	APIKeyOutput
+	// Key authenticates requests sent with "Authorization: ApiKey <key>".
+	Key string `json:"key"`
+}
+
+// MapFromDomain converts domain entity to output DTO.
+func MapFromDomain(k *apikey.Key) APIKeyOutput {
+	return APIKeyOutput{
+		ID:        k.ID(),
+		Name:      k.Name(),
+		Prefix:    k.Prefix(),
+		Scopes:    k.Scopes(),
+		CreatedAt: k.CreatedAt(),
+		RevokedAt: k.RevokedAt(),
+	}
+}
@@ -0,0 +1,45 @@
+package apikey
+
+import (
+	"context"
+	"fmt"
+
+	"usermanagement/internal/application/pagination"
+	"usermanagement/internal/domain/apikey"
+)
+
+// ListAPIKeysUseCase implements the list API keys use case.
+type ListAPIKeysUseCase struct {
+	keys apikey.Repository
+}
+
+// NewListAPIKeysUseCase creates a new instance.
+func NewListAPIKeysUseCase(keys apikey.Repository) *ListAPIKeysUseCase {
+	return &ListAPIKeysUseCase{keys: keys}
+}
+
+// Execute returns a page of the calling admin's keys, revoked ones
+// included, newest first.
+func (uc *ListAPIKeysUseCase) Execute(ctx context.Context, params pagination.Params) (*pagination.Page[APIKeyOutput], error) {
+	caller, err := requireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	params = params.Normalize()
+	keys, err := uc.keys.FindByOwner(ctx, caller.UserID, params.Limit, params.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+
+	total, err := uc.keys.CountByOwner(ctx, caller.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count API keys: %w", err)
+	}
+
+	outputs := make([]APIKeyOutput, len(keys))
+	for i, k := range keys {
+		outputs[i] = MapFromDomain(k)
+	}
+	return pagination.NewPage(outputs, params, total), nil
+}
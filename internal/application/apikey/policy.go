@@ -0,0 +1,45 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"usermanagement/internal/application/auth"
+	"usermanagement/internal/domain/apikey"
+)
+
+// requireAdmin returns the caller when they may manage API keys: admins
+// signed in as themselves. Keys cannot manage keys, so a leaked key cannot
+// mint more.
+func requireAdmin(ctx context.Context) (auth.Caller, error) {
+	caller, ok := auth.CallerFrom(ctx)
+	if !ok || !caller.IsAdmin() || caller.ViaAPIKey() {
+		return auth.Caller{}, auth.ErrForbidden
+	}
+	return caller, nil
+}
+
+// findOwned loads one of the caller's keys. Other admins' keys are
+// reported as not found.
+func findOwned(ctx context.Context, keys apikey.Repository, id uuid.UUID) (*apikey.Key, error) {
+	caller, err := requireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	k, err := keys.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, apikey.ErrKeyNotFound) {
+			return nil, apikey.ErrKeyNotFound
+		}
+		return nil, fmt.Errorf("failed to find API key: %w", err)
+	}
+
+	if k.OwnerID() != caller.UserID {
+		return nil, apikey.ErrKeyNotFound
+	}
+	return k, nil
+}
@@ -0,0 +1,53 @@
+package apikey
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"usermanagement/internal/application/usecase"
+	"usermanagement/internal/domain/apikey"
+)
+
+// RevokeAPIKeyUseCase implements the revoke API key use case.
+type RevokeAPIKeyUseCase struct {
+	keys apikey.Repository
+}
+
+// NewRevokeAPIKeyUseCase creates a new instance.
+func NewRevokeAPIKeyUseCase(keys apikey.Repository) *RevokeAPIKeyUseCase {
+	return &RevokeAPIKeyUseCase{keys: keys}
+}
+
+// Execute revokes one of the caller's keys; requests made with it are
+// rejected from then on. Revoked keys stay listed. Revoking a key twice is
+// a no-op.
+func (uc *RevokeAPIKeyUseCase) Execute(ctx context.Context, id uuid.UUID) error {
+	k, err := findOwned(ctx, uc.keys, id)
+	if err != nil {
+		return err
+	}
+	if k.Revoked() {
+		return nil
+	}
+
+	k.Revoke()
+	if err := uc.keys.Revoke(ctx, k); err != nil {
+		return fmt.Errorf("failed to revoke API key: %w", err)
+	}
+
+	return nil
+}
+
+// Preview reports what Execute would revoke, without revoking it.
+func (uc *RevokeAPIKeyUseCase) Preview(ctx context.Context, id uuid.UUID) ([]usecase.Change, error) {
+	k, err := findOwned(ctx, uc.keys, id)
+	if err != nil {
+		return nil, err
+	}
+	if k.Revoked() {
+		return nil, nil
+	}
+	return []usecase.Change{{Action: "revoke", Resource: "api_key", ID: k.ID().String()}}, nil
+}
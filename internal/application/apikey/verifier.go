@@ -0,0 +1,61 @@
+package apikey
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+
+	"usermanagement/internal/application/auth"
+	"usermanagement/internal/domain/apikey"
+	"usermanagement/internal/domain/user"
+)
+
+// Verifier implements auth.APIKeyVerifier on the stored keys.
+type Verifier struct {
+	keys  apikey.Repository
+	users user.UserRepository
+}
+
+// NewVerifier creates a verifier of the keys in keys, issued by users in
+// users.
+func NewVerifier(keys apikey.Repository, users user.UserRepository) *Verifier {
+	return &Verifier{keys: keys, users: users}
+}
+
+// VerifyAPIKey returns the caller a key acts as: its owner, with the role
+// they hold now, limited to the key's scopes. Keys of deleted users stop
+// working.
+func (v *Verifier) VerifyAPIKey(ctx context.Context, key string) (auth.Caller, error) {
+	prefix, secret, ok := strings.Cut(strings.TrimPrefix(key, keyPrefix), "_")
+	if !ok || !strings.HasPrefix(key, keyPrefix) {
+		return auth.Caller{}, auth.ErrInvalidToken
+	}
+
+	k, err := v.keys.FindByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, apikey.ErrKeyNotFound) {
+			return auth.Caller{}, auth.ErrInvalidToken
+		}
+		return auth.Caller{}, fmt.Errorf("failed to find API key: %w", err)
+	}
+	if subtle.ConstantTimeCompare(hashSecret(secret), k.Hash()) != 1 || k.Revoked() {
+		return auth.Caller{}, auth.ErrInvalidToken
+	}
+
+	owner, err := v.users.FindByID(ctx, k.OwnerID())
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return auth.Caller{}, auth.ErrInvalidToken
+		}
+		return auth.Caller{}, fmt.Errorf("failed to find API key owner: %w", err)
+	}
+
+	return auth.Caller{
+		UserID: owner.ID(),
+		Role:   owner.Role(),
+		KeyID:  k.ID(),
+		Scopes: k.Scopes(),
+	}, nil
+}
@@ -0,0 +1,91 @@
+// Package auth holds the application-level authentication contracts: the
+// token and API key ports and the request context carrying the
+// authenticated caller.
+package auth
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"usermanagement/internal/domain/apikey"
+	"usermanagement/internal/domain/errcode"
+	"usermanagement/internal/domain/user"
+)
+
+// Authentication errors.
+var (
+	ErrInvalidCredentials = errcode.New(errcode.InvalidCredentials, "invalid email or password")
+	ErrInvalidToken       = errcode.New(errcode.Unauthorized, "invalid or expired token")
+	ErrForbidden          = errcode.New(errcode.Forbidden, "insufficient permissions")
+)
+
+// Caller identifies the authenticated user making a request.
+type Caller struct {
+	UserID uuid.UUID
+	Role   user.Role
+	// KeyID and Scopes are set when the caller authenticated with an API
+	// key, which acts for its owner within its scopes only.
+	KeyID  uuid.UUID
+	Scopes []apikey.Scope
+}
+
+// ViaAPIKey reports whether the caller authenticated with an API key.
+func (c Caller) ViaAPIKey() bool {
+	return c.KeyID != uuid.Nil
+}
+
+// Allows reports whether the caller may use scope. Callers with user
+// tokens are not limited by scopes.
+func (c Caller) Allows(scope apikey.Scope) bool {
+	return !c.ViaAPIKey() || slices.Contains(c.Scopes, scope)
+}
+
+// IsAdmin reports whether the caller holds the admin role.
+func (c Caller) IsAdmin() bool {
+	return c.Role == user.RoleAdmin
+}
+
+// Tokens is a freshly issued access/refresh token pair.
+type Tokens struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	// ExpiresIn is the access token lifetime in seconds.
+	ExpiresIn int `json:"expires_in"`
+}
+
+// TokenIssuer issues and verifies tokens for authenticated users.
+// Implementations must return ErrInvalidToken for any token they reject.
+type TokenIssuer interface {
+	// Issue creates a new token pair for the user, embedding their role in
+	// the access token.
+	Issue(userID uuid.UUID, role user.Role) (*Tokens, error)
+
+	// VerifyAccess returns the caller an access token was issued to.
+	VerifyAccess(token string) (Caller, error)
+
+	// VerifyRefresh returns the user a refresh token was issued to.
+	VerifyRefresh(token string) (uuid.UUID, error)
+}
+
+// APIKeyVerifier authenticates requests made with API keys.
+// Implementations must return ErrInvalidToken for any key they reject.
+type APIKeyVerifier interface {
+	// VerifyAPIKey returns the caller a key acts as.
+	VerifyAPIKey(ctx context.Context, key string) (Caller, error)
+}
+
+type callerKey struct{}
+
+// WithCaller returns a context carrying the authenticated caller.
+func WithCaller(ctx context.Context, caller Caller) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+// CallerFrom returns the authenticated caller, if the request was authenticated.
+func CallerFrom(ctx context.Context) (Caller, bool) {
+	caller, ok := ctx.Value(callerKey{}).(Caller)
+	return caller, ok
+}
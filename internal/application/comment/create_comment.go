@@ -0,0 +1,83 @@
+package comment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"usermanagement/internal/application/auth"
+	"usermanagement/internal/application/validation"
+	"usermanagement/internal/domain/comment"
+	"usermanagement/internal/domain/moderation"
+	"usermanagement/internal/domain/post"
+	"usermanagement/internal/domain/user"
+)
+
+// CreateCommentUseCase implements the comment on a post use case.
+type CreateCommentUseCase struct {
+	comments  comment.CommentRepository
+	posts     post.PostRepository
+	ids       user.IDGenerator
+	policy    moderation.Policy
+	validator *validation.Validator
+}
+
+// NewCreateCommentUseCase creates a new instance.
+func NewCreateCommentUseCase(comments comment.CommentRepository, posts post.PostRepository, ids user.IDGenerator, policy moderation.Policy, validator *validation.Validator) *CreateCommentUseCase {
+	return &CreateCommentUseCase{comments: comments, posts: posts, ids: ids, policy: policy, validator: validator}
+}
+
+// Execute adds the caller's comment to a post. Comments wait for moderation
+// unless the caller moderates the post themselves and the content policy
+// did not flag them.
+func (uc *CreateCommentUseCase) Execute(ctx context.Context, input CreateCommentInput) (*CommentOutput, error) {
+	caller, ok := auth.CallerFrom(ctx)
+	if !ok {
+		return nil, auth.ErrForbidden
+	}
+
+	if err := uc.validator.Struct(input); err != nil {
+		return nil, err
+	}
+
+	p, err := findVisiblePost(ctx, uc.posts, input.PostID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.ParentID != nil {
+		if _, err := findComment(ctx, uc.comments, p.ID(), *input.ParentID); err != nil {
+			if errors.Is(err, comment.ErrCommentNotFound) {
+				return nil, comment.ErrInvalidParent
+			}
+			return nil, err
+		}
+	}
+
+	flagged, err := moderation.Enforce(ctx, uc.policy, moderation.FieldCommentBody, input.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := uc.ids.NewID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate comment id: %w", err)
+	}
+
+	domainComment, err := comment.New(id, p.ID(), caller.UserID, input.ParentID, input.Body)
+	if err != nil {
+		return nil, err
+	}
+	if canModerate(ctx, p) && !flagged {
+		if err := domainComment.Moderate(comment.StatusApproved); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.comments.Save(ctx, domainComment); err != nil {
+		return nil, fmt.Errorf("failed to save comment: %w", err)
+	}
+
+	output := MapFromDomain(domainComment)
+	return &output, nil
+}
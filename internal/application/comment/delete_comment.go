@@ -0,0 +1,70 @@
+package comment
+
+import (
+	"context"
+	"fmt"
+
+	"usermanagement/internal/application/auth"
+	"usermanagement/internal/application/usecase"
+	"usermanagement/internal/domain/comment"
+	"usermanagement/internal/domain/post"
+)
+
+// DeleteCommentUseCase implements the delete comment use case.
+type DeleteCommentUseCase struct {
+	comments comment.CommentRepository
+	posts    post.PostRepository
+}
+
+// NewDeleteCommentUseCase creates a new instance.
+func NewDeleteCommentUseCase(comments comment.CommentRepository, posts post.PostRepository) *DeleteCommentUseCase {
+	return &DeleteCommentUseCase{comments: comments, posts: posts}
+}
+
+// Execute deletes a comment together with its replies. Its author, the
+// post's author and admins may delete it.
+func (uc *DeleteCommentUseCase) Execute(ctx context.Context, input DeleteCommentInput) error {
+	domainComment, err := uc.check(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.comments.Delete(ctx, domainComment.ID()); err != nil {
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+
+	return nil
+}
+
+// Preview reports what Execute would delete, without deleting it.
+func (uc *DeleteCommentUseCase) Preview(ctx context.Context, input DeleteCommentInput) ([]usecase.Change, error) {
+	domainComment, err := uc.check(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return []usecase.Change{{Action: "delete", Resource: "comment", ID: domainComment.ID().String()}}, nil
+}
+
+// check finds the comment and verifies the caller may delete it.
+func (uc *DeleteCommentUseCase) check(ctx context.Context, input DeleteCommentInput) (*comment.Comment, error) {
+	caller, ok := auth.CallerFrom(ctx)
+	if !ok {
+		return nil, auth.ErrForbidden
+	}
+
+	p, err := findVisiblePost(ctx, uc.posts, input.PostID)
+	if err != nil {
+		return nil, err
+	}
+
+	domainComment, err := findComment(ctx, uc.comments, p.ID(), input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if caller.UserID != domainComment.AuthorID() && !canModerate(ctx, p) {
+		return nil, auth.ErrForbidden
+	}
+
+	return domainComment, nil
+}
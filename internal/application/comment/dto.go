@@ -0,0 +1,66 @@
+package comment
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"usermanagement/internal/application/pagination"
+	"usermanagement/internal/domain/comment"
+)
+
+// CreateCommentInput represents data needed to comment on a post.
+type CreateCommentInput struct {
+	PostID uuid.UUID `json:"-"` // From URL param, not body
+	// ParentID makes the comment a reply to another comment on the post.
+	ParentID *uuid.UUID `json:"parent_id,omitempty"`
+	Body     string     `json:"body" validate:"notblank,max=10000"`
+}
+
+// ListCommentsInput selects a page of comments on a post.
+type ListCommentsInput struct {
+	PostID uuid.UUID
+	// Status defaults to approved; other statuses are for moderators.
+	Status string `validate:"omitempty,oneof=pending approved spam"`
+	Page   pagination.Params
+}
+
+// ModerateCommentInput represents a moderation decision on a comment.
+type ModerateCommentInput struct {
+	PostID uuid.UUID `json:"-"` // From URL param, not body
+	ID     uuid.UUID `json:"-"` // From URL param, not body
+	Status string    `json:"status" validate:"required,oneof=pending approved spam"`
+}
+
+// DeleteCommentInput identifies a comment to delete.
+type DeleteCommentInput struct {
+	PostID uuid.UUID
+	ID     uuid.UUID
+}
+
+// CommentOutput represents comment data returned to clients. Clients build
+// threads from ParentID.
+type CommentOutput struct {
+	ID        uuid.UUID  `json:"id"`
+	PostID    uuid.UUID  `json:"post_id"`
+	AuthorID  uuid.UUID  `json:"author_id"`
+	ParentID  *uuid.UUID `json:"parent_id,omitempty"`
+	Body      string     `json:"body"`
+	Status    string     `json:"status"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// MapFromDomain converts domain entity to output DTO.
+func MapFromDomain(c *comment.Comment) CommentOutput {
+	return CommentOutput{
+		ID:        c.ID(),
+		PostID:    c.PostID(),
+		AuthorID:  c.AuthorID(),
+		ParentID:  c.ParentID(),
+		Body:      c.Body(),
+		Status:    string(c.Status()),
+		CreatedAt: c.CreatedAt(),
+		UpdatedAt: c.UpdatedAt(),
+	}
+}
@@ -0,0 +1,63 @@
+package comment
+
+import (
+	"context"
+	"fmt"
+
+	"usermanagement/internal/application/auth"
+	"usermanagement/internal/application/pagination"
+	"usermanagement/internal/application/validation"
+	"usermanagement/internal/domain/comment"
+	"usermanagement/internal/domain/post"
+)
+
+// ListCommentsUseCase implements the list comments on a post use case.
+type ListCommentsUseCase struct {
+	comments  comment.CommentRepository
+	posts     post.PostRepository
+	validator *validation.Validator
+}
+
+// NewListCommentsUseCase creates a new instance.
+func NewListCommentsUseCase(comments comment.CommentRepository, posts post.PostRepository, validator *validation.Validator) *ListCommentsUseCase {
+	return &ListCommentsUseCase{comments: comments, posts: posts, validator: validator}
+}
+
+// Execute returns a page of a post's comments in one moderation state,
+// oldest first. Readers only see approved comments; the moderation queues
+// are for the post's author and admins.
+func (uc *ListCommentsUseCase) Execute(ctx context.Context, input ListCommentsInput) (*pagination.Page[CommentOutput], error) {
+	if err := uc.validator.Struct(input); err != nil {
+		return nil, err
+	}
+
+	p, err := findVisiblePost(ctx, uc.posts, input.PostID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := comment.StatusApproved
+	if input.Status != "" {
+		status = comment.Status(input.Status)
+	}
+	if status != comment.StatusApproved && !canModerate(ctx, p) {
+		return nil, auth.ErrForbidden
+	}
+
+	params := input.Page.Normalize()
+	comments, err := uc.comments.FindByPost(ctx, p.ID(), status, params.Limit, params.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list comments: %w", err)
+	}
+
+	total, err := uc.comments.CountByPost(ctx, p.ID(), status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count comments: %w", err)
+	}
+
+	outputs := make([]CommentOutput, len(comments))
+	for i, c := range comments {
+		outputs[i] = MapFromDomain(c)
+	}
+	return pagination.NewPage(outputs, params, total), nil
+}
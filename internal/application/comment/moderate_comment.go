@@ -0,0 +1,55 @@
+package comment
+
+import (
+	"context"
+	"fmt"
+
+	"usermanagement/internal/application/auth"
+	"usermanagement/internal/application/validation"
+	"usermanagement/internal/domain/comment"
+	"usermanagement/internal/domain/post"
+)
+
+// ModerateCommentUseCase implements the moderate comment use case.
+type ModerateCommentUseCase struct {
+	comments  comment.CommentRepository
+	posts     post.PostRepository
+	validator *validation.Validator
+}
+
+// NewModerateCommentUseCase creates a new instance.
+func NewModerateCommentUseCase(comments comment.CommentRepository, posts post.PostRepository, validator *validation.Validator) *ModerateCommentUseCase {
+	return &ModerateCommentUseCase{comments: comments, posts: posts, validator: validator}
+}
+
+// Execute approves a comment, marks it as spam or sends it back to pending.
+// Only the post's author or an admin may moderate.
+func (uc *ModerateCommentUseCase) Execute(ctx context.Context, input ModerateCommentInput) (*CommentOutput, error) {
+	if err := uc.validator.Struct(input); err != nil {
+		return nil, err
+	}
+
+	p, err := findVisiblePost(ctx, uc.posts, input.PostID)
+	if err != nil {
+		return nil, err
+	}
+	if !canModerate(ctx, p) {
+		return nil, auth.ErrForbidden
+	}
+
+	domainComment, err := findComment(ctx, uc.comments, p.ID(), input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := domainComment.Moderate(comment.Status(input.Status)); err != nil {
+		return nil, err
+	}
+
+	if err := uc.comments.Update(ctx, domainComment); err != nil {
+		return nil, fmt.Errorf("failed to update comment: %w", err)
+	}
+
+	output := MapFromDomain(domainComment)
+	return &output, nil
+}
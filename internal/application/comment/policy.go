@@ -0,0 +1,53 @@
+package comment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"usermanagement/internal/application/auth"
+	"usermanagement/internal/domain/comment"
+	"usermanagement/internal/domain/post"
+)
+
+// canModerate reports whether the caller may moderate comments on p: its
+// author or an admin.
+func canModerate(ctx context.Context, p *post.Post) bool {
+	caller, ok := auth.CallerFrom(ctx)
+	return ok && (caller.IsAdmin() || caller.UserID == p.AuthorID())
+}
+
+// findVisiblePost loads the post being commented on. Posts the request may
+// not see are reported as not found, as the post endpoints do.
+func findVisiblePost(ctx context.Context, posts post.PostRepository, id uuid.UUID) (*post.Post, error) {
+	p, err := posts.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, post.ErrPostNotFound) {
+			return nil, post.ErrPostNotFound
+		}
+		return nil, fmt.Errorf("failed to find post: %w", err)
+	}
+
+	if !p.IsPublished() && !canModerate(ctx, p) {
+		return nil, post.ErrPostNotFound
+	}
+	return p, nil
+}
+
+// findComment loads a comment, reporting comments on other posts as not found.
+func findComment(ctx context.Context, comments comment.CommentRepository, postID, id uuid.UUID) (*comment.Comment, error) {
+	c, err := comments.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, comment.ErrCommentNotFound) {
+			return nil, comment.ErrCommentNotFound
+		}
+		return nil, fmt.Errorf("failed to find comment: %w", err)
+	}
+
+	if c.PostID() != postID {
+		return nil, comment.ErrCommentNotFound
+	}
+	return c, nil
+}
@@ -0,0 +1,75 @@
+// Package pagination defines the paging parameters and response envelope
+// shared by every list operation.
+package pagination
+
+const (
+	// DefaultLimit applies when a request does not ask for a page size.
+	DefaultLimit = 10
+	// MaxLimit caps the page size a client may request.
+	MaxLimit = 100
+)
+
+// Params selects a page of results.
+type Params struct {
+	Limit  int
+	Offset int
+	// Cursor resumes a listing after the page that returned it; Offset is
+	// ignored when it is set. Only listings that support cursors read it.
+	Cursor string
+}
+
+// Normalize applies the default and maximum page size and clamps a negative
+// offset to zero, or drops it in favour of a cursor.
+func (p Params) Normalize() Params {
+	if p.Limit <= 0 {
+		p.Limit = DefaultLimit
+	}
+	if p.Limit > MaxLimit {
+		p.Limit = MaxLimit
+	}
+	if p.Offset < 0 || p.Cursor != "" {
+		p.Offset = 0
+	}
+	return p
+}
+
+// Meta describes where a page sits in the full result set.
+type Meta struct {
+	Limit   int   `json:"limit"`
+	Offset  int   `json:"offset"`
+	Total   int64 `json:"total"`
+	HasMore bool  `json:"has_more"`
+	// NextCursor fetches the following page, for listings with cursors.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// Page is the response envelope of every list operation.
+type Page[T any] struct {
+	Items []T  `json:"items"`
+	Meta  Meta `json:"meta"`
+}
+
+// NewPage wraps the items fetched with p, out of total results.
+func NewPage[T any](items []T, p Params, total int64) *Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return &Page[T]{
+		Items: items,
+		Meta: Meta{
+			Limit:   p.Limit,
+			Offset:  p.Offset,
+			Total:   total,
+			HasMore: int64(p.Offset+len(items)) < total,
+		},
+	}
+}
+
+// NewCursorPage wraps items fetched with p, out of total results. next is
+// the cursor of the following page, empty on the last one.
+func NewCursorPage[T any](items []T, p Params, total int64, next string) *Page[T] {
+	page := NewPage(items, p, total)
+	page.Meta.HasMore = next != ""
+	page.Meta.NextCursor = next
+	return page
+}
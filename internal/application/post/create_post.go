@@ -0,0 +1,59 @@
+package post
+
+import (
+	"context"
+	"fmt"
+
+	"usermanagement/internal/application/auth"
+	"usermanagement/internal/application/validation"
+	"usermanagement/internal/domain/moderation"
+	"usermanagement/internal/domain/post"
+	"usermanagement/internal/domain/user"
+)
+
+// CreatePostUseCase implements the create post use case.
+type CreatePostUseCase struct {
+	repo      post.PostRepository
+	ids       user.IDGenerator
+	policy    moderation.Policy
+	validator *validation.Validator
+}
+
+// NewCreatePostUseCase creates a new instance.
+func NewCreatePostUseCase(repo post.PostRepository, ids user.IDGenerator, policy moderation.Policy, validator *validation.Validator) *CreatePostUseCase {
+	return &CreatePostUseCase{repo: repo, ids: ids, policy: policy, validator: validator}
+}
+
+// Execute creates a draft post authored by the caller.
+func (uc *CreatePostUseCase) Execute(ctx context.Context, input CreatePostInput) (*PostOutput, error) {
+	caller, ok := auth.CallerFrom(ctx)
+	if !ok || !canWrite(caller) {
+		return nil, auth.ErrForbidden
+	}
+
+	if err := uc.validator.Struct(input); err != nil {
+		return nil, err
+	}
+
+	if _, err := moderation.Enforce(ctx, uc.policy, moderation.FieldPostTitle, input.Title); err != nil {
+		return nil, err
+	}
+
+	id, err := uc.ids.NewID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate post id: %w", err)
+	}
+
+	domainPost, err := post.New(id, caller.UserID, input.Title, input.Slug, input.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// The unique index on slug reports duplicates as ErrSlugExists.
+	if err := uc.repo.Save(ctx, domainPost); err != nil {
+		return nil, fmt.Errorf("failed to save post: %w", err)
+	}
+
+	output := MapFromDomain(domainPost)
+	return &output, nil
+}
@@ -0,0 +1,70 @@
+package post
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"usermanagement/internal/application/auth"
+	"usermanagement/internal/application/usecase"
+	"usermanagement/internal/domain/post"
+)
+
+// DeletePostUseCase implements the delete post use case.
+type DeletePostUseCase struct {
+	repo post.PostRepository
+}
+
+// NewDeletePostUseCase creates a new instance.
+func NewDeletePostUseCase(repo post.PostRepository) *DeletePostUseCase {
+	return &DeletePostUseCase{repo: repo}
+}
+
+// Execute deletes a post. Only its author or an admin may delete it.
+func (uc *DeletePostUseCase) Execute(ctx context.Context, id uuid.UUID) error {
+	if err := uc.check(ctx, id); err != nil {
+		return err
+	}
+
+	if err := uc.repo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete post: %w", err)
+	}
+
+	return nil
+}
+
+// Preview reports what Execute would delete, without deleting it. The
+// post's comments go with it.
+func (uc *DeletePostUseCase) Preview(ctx context.Context, id uuid.UUID) ([]usecase.Change, error) {
+	if err := uc.check(ctx, id); err != nil {
+		return nil, err
+	}
+	return []usecase.Change{{Action: "delete", Resource: "post", ID: id.String()}}, nil
+}
+
+// check verifies the post exists and the caller may delete it.
+func (uc *DeletePostUseCase) check(ctx context.Context, id uuid.UUID) error {
+	caller, ok := auth.CallerFrom(ctx)
+	if !ok {
+		return auth.ErrForbidden
+	}
+
+	domainPost, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, post.ErrPostNotFound) {
+			return post.ErrPostNotFound
+		}
+		return fmt.Errorf("failed to find post: %w", err)
+	}
+
+	if !canModify(caller, domainPost) {
+		if !canView(ctx, domainPost) {
+			return post.ErrPostNotFound
+		}
+		return auth.ErrForbidden
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package post
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"usermanagement/internal/domain/post"
+)
+
+// CreatePostInput represents data needed to create a post.
+type CreatePostInput struct {
+	Title string `json:"title" validate:"notblank,max=200"`
+	// Slug is derived from the title when empty.
+	Slug string `json:"slug,omitempty" validate:"omitempty,max=200"`
+	Body string `json:"body" validate:"max=100000"`
+}
+
+// UpdatePostInput represents data needed to update a post.
+type UpdatePostInput struct {
+	ID     uuid.UUID `json:"-"` // From URL param, not body
+	Title  *string   `json:"title,omitempty" validate:"omitempty,notblank,max=200"`
+	Slug   *string   `json:"slug,omitempty" validate:"omitempty,max=200"`
+	Body   *string   `json:"body,omitempty" validate:"omitempty,max=100000"`
+	Status *string   `json:"status,omitempty" validate:"omitempty,oneof=draft published"`
+}
+
+// PostOutput represents post data returned to clients.
+type PostOutput struct {
+	ID          uuid.UUID  `json:"id"`
+	AuthorID    uuid.UUID  `json:"author_id"`
+	Title       string     `json:"title"`
+	Slug        string     `json:"slug"`
+	Body        string     `json:"body"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+}
+
+// MapFromDomain converts domain entity to output DTO.
+func MapFromDomain(p *post.Post) PostOutput {
+	return PostOutput{
+		ID:          p.ID(),
+		AuthorID:    p.AuthorID(),
+		Title:       p.Title(),
+		Slug:        p.Slug(),
+		Body:        p.Body(),
+		Status:      string(p.Status()),
+		CreatedAt:   p.CreatedAt(),
+		UpdatedAt:   p.UpdatedAt(),
+		PublishedAt: p.PublishedAt(),
+	}
+}
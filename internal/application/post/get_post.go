@@ -0,0 +1,40 @@
+package post
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"usermanagement/internal/domain/post"
+)
+
+// GetPostUseCase implements the get post use case.
+type GetPostUseCase struct {
+	repo post.PostRepository
+}
+
+// NewGetPostUseCase creates a new instance.
+func NewGetPostUseCase(repo post.PostRepository) *GetPostUseCase {
+	return &GetPostUseCase{repo: repo}
+}
+
+// Execute retrieves a post by ID. Drafts the caller may not see are reported
+// as not found.
+func (uc *GetPostUseCase) Execute(ctx context.Context, id uuid.UUID) (*PostOutput, error) {
+	domainPost, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, post.ErrPostNotFound) {
+			return nil, post.ErrPostNotFound
+		}
+		return nil, fmt.Errorf("failed to find post: %w", err)
+	}
+
+	if !canView(ctx, domainPost) {
+		return nil, post.ErrPostNotFound
+	}
+
+	output := MapFromDomain(domainPost)
+	return &output, nil
+}
@@ -0,0 +1,40 @@
+package post
+
+import (
+	"context"
+	"fmt"
+
+	"usermanagement/internal/application/pagination"
+	"usermanagement/internal/domain/post"
+)
+
+// ListPostsUseCase implements the list published posts use case.
+type ListPostsUseCase struct {
+	repo post.PostRepository
+}
+
+// NewListPostsUseCase creates a new instance.
+func NewListPostsUseCase(repo post.PostRepository) *ListPostsUseCase {
+	return &ListPostsUseCase{repo: repo}
+}
+
+// Execute returns a page of published posts, newest first.
+func (uc *ListPostsUseCase) Execute(ctx context.Context, params pagination.Params) (*pagination.Page[PostOutput], error) {
+	params = params.Normalize()
+
+	posts, err := uc.repo.FindByStatus(ctx, post.StatusPublished, params.Limit, params.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list posts: %w", err)
+	}
+
+	total, err := uc.repo.CountByStatus(ctx, post.StatusPublished)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count posts: %w", err)
+	}
+
+	outputs := make([]PostOutput, len(posts))
+	for i, p := range posts {
+		outputs[i] = MapFromDomain(p)
+	}
+	return pagination.NewPage(outputs, params, total), nil
+}
@@ -0,0 +1,30 @@
+package post
+
+import (
+	"context"
+
+	"usermanagement/internal/application/auth"
+	"usermanagement/internal/domain/post"
+	"usermanagement/internal/domain/user"
+)
+
+// canWrite reports whether the caller may author posts: editors and admins.
+func canWrite(caller auth.Caller) bool {
+	return caller.Role == user.RoleEditor || caller.Role == user.RoleAdmin
+}
+
+// canModify reports whether the caller may change or delete p: its author,
+// while still allowed to write, or an admin.
+func canModify(caller auth.Caller, p *post.Post) bool {
+	return caller.IsAdmin() || (canWrite(caller) && caller.UserID == p.AuthorID())
+}
+
+// canView reports whether the request may see p. Published posts are public;
+// drafts are visible only to those who may modify them.
+func canView(ctx context.Context, p *post.Post) bool {
+	if p.IsPublished() {
+		return true
+	}
+	caller, ok := auth.CallerFrom(ctx)
+	return ok && canModify(caller, p)
+}
@@ -0,0 +1,94 @@
+package post
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"usermanagement/internal/application/auth"
+	"usermanagement/internal/application/validation"
+	"usermanagement/internal/domain/moderation"
+	"usermanagement/internal/domain/post"
+	"usermanagement/internal/domain/transaction"
+)
+
+// UpdatePostUseCase implements the update post use case.
+type UpdatePostUseCase struct {
+	repo      post.PostRepository
+	tx        transaction.UnitOfWork
+	policy    moderation.Policy
+	validator *validation.Validator
+}
+
+// NewUpdatePostUseCase creates a new instance.
+func NewUpdatePostUseCase(repo post.PostRepository, tx transaction.UnitOfWork, policy moderation.Policy, validator *validation.Validator) *UpdatePostUseCase {
+	return &UpdatePostUseCase{repo: repo, tx: tx, policy: policy, validator: validator}
+}
+
+// Execute updates a post. Only its author or an admin may change it.
+func (uc *UpdatePostUseCase) Execute(ctx context.Context, input UpdatePostInput) (*PostOutput, error) {
+	caller, ok := auth.CallerFrom(ctx)
+	if !ok {
+		return nil, auth.ErrForbidden
+	}
+
+	if err := uc.validator.Struct(input); err != nil {
+		return nil, err
+	}
+
+	var output *PostOutput
+	err := uc.tx.Do(ctx, func(ctx context.Context) error {
+		var err error
+		output, err = uc.update(ctx, caller, input)
+		return err
+	})
+	return output, err
+}
+
+// update applies input inside the unit of work, so the post cannot change
+// between being read and written back.
+func (uc *UpdatePostUseCase) update(ctx context.Context, caller auth.Caller, input UpdatePostInput) (*PostOutput, error) {
+	domainPost, err := uc.repo.FindByID(ctx, input.ID)
+	if err != nil {
+		if errors.Is(err, post.ErrPostNotFound) {
+			return nil, post.ErrPostNotFound
+		}
+		return nil, fmt.Errorf("failed to find post: %w", err)
+	}
+
+	if !canModify(caller, domainPost) {
+		if !canView(ctx, domainPost) {
+			return nil, post.ErrPostNotFound
+		}
+		return nil, auth.ErrForbidden
+	}
+
+	if input.Title != nil {
+		if _, err := moderation.Enforce(ctx, uc.policy, moderation.FieldPostTitle, *input.Title); err != nil {
+			return nil, err
+		}
+		if err := domainPost.UpdateTitle(*input.Title); err != nil {
+			return nil, err
+		}
+	}
+	if input.Slug != nil {
+		if err := domainPost.UpdateSlug(*input.Slug); err != nil {
+			return nil, err
+		}
+	}
+	if input.Body != nil {
+		domainPost.UpdateBody(*input.Body)
+	}
+	if input.Status != nil {
+		if err := domainPost.ChangeStatus(post.Status(*input.Status)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.repo.Update(ctx, domainPost); err != nil {
+		return nil, fmt.Errorf("failed to update post: %w", err)
+	}
+
+	output := MapFromDomain(domainPost)
+	return &output, nil
+}
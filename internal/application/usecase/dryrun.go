@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+)
+
+// Change describes a record a destructive use case removes or modifies.
+type Change struct {
+	Action   string `json:"action"`
+	Resource string `json:"resource"`
+	ID       string `json:"id"`
+}
+
+// Previewer is implemented by destructive use cases that can report what
+// they would change, after running the same checks as Execute.
+type Previewer[I any] interface {
+	Preview(ctx context.Context, input I) ([]Change, error)
+}
+
+type dryRunKey struct{}
+
+// dryRun collects the changes previewed under one dry-run context.
+type dryRun struct {
+	mu      sync.Mutex
+	changes []Change
+}
+
+// WithDryRun marks ctx as a dry run: commands decorated with DryRunCommand
+// only preview their changes, and DryRunChanges reports them afterwards.
+func WithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, &dryRun{})
+}
+
+// DryRunChanges returns the changes previewed under ctx, and whether ctx is
+// a dry run at all.
+func DryRunChanges(ctx context.Context) ([]Change, bool) {
+	run, ok := ctx.Value(dryRunKey{}).(*dryRun)
+	if !ok {
+		return nil, false
+	}
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	return append([]Change{}, run.changes...), true
+}
+
+// DryRunCommand decorates a destructive command so that, under a dry-run
+// context, preview runs instead of next and nothing is committed.
+func DryRunCommand[I any](next Command[I], preview Previewer[I]) Command[I] {
+	return &dryRunCommand[I]{next: next, preview: preview}
+}
+
+type dryRunCommand[I any] struct {
+	next    Command[I]
+	preview Previewer[I]
+}
+
+func (c *dryRunCommand[I]) Execute(ctx context.Context, input I) error {
+	run, ok := ctx.Value(dryRunKey{}).(*dryRun)
+	if !ok {
+		return c.next.Execute(ctx, input)
+	}
+
+	changes, err := c.preview.Preview(ctx, input)
+	if err != nil {
+		return err
+	}
+	run.mu.Lock()
+	run.changes = append(run.changes, changes...)
+	run.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,15 @@
+package usecase
+
+import "context"
+
+// UseCase is the common shape of application use cases that return a result.
+// Delivery adapters depend on it so use cases can be decorated (metrics,
+// tracing, ...) without the handlers knowing.
+type UseCase[I, O any] interface {
+	Execute(ctx context.Context, input I) (O, error)
+}
+
+// Command is a use case that only reports success or failure.
+type Command[I any] interface {
+	Execute(ctx context.Context, input I) error
+}
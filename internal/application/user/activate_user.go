@@ -0,0 +1,64 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"usermanagement/internal/application/validation"
+	"usermanagement/internal/domain/user"
+)
+
+// ActivateUserUseCase implements the activation of users who signed up.
+type ActivateUserUseCase struct {
+	activations user.ActivationRepository
+	repo        user.UserRepository
+	validator   *validation.Validator
+}
+
+// NewActivateUserUseCase creates a new instance.
+func NewActivateUserUseCase(activations user.ActivationRepository, repo user.UserRepository, validator *validation.Validator) *ActivateUserUseCase {
+	return &ActivateUserUseCase{activations: activations, repo: repo, validator: validator}
+}
+
+// Execute activates the user the token was mailed to, who may log in from
+// then on. Each token works once.
+func (uc *ActivateUserUseCase) Execute(ctx context.Context, input ActivateUserInput) (*UserOutput, error) {
+	if err := uc.validator.Struct(input); err != nil {
+		return nil, err
+	}
+
+	id, err := uc.activations.Activate(ctx, hashActivationToken(input.Token), time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	domainUser, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	output := MapFromDomain(domainUser)
+	return &output, nil
+}
+
+// newActivationToken returns a random activation token and the hash it is
+// stored under.
+func newActivationToken() (token string, hash []byte, err error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", nil, fmt.Errorf("failed to generate activation token: %w", err)
+	}
+	token = base64.RawURLEncoding.EncodeToString(random)
+	return token, hashActivationToken(token), nil
+}
+
+// hashActivationToken hashes a token for storage. Tokens are random, so a
+// fast hash is enough.
+func hashActivationToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
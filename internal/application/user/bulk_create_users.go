@@ -0,0 +1,154 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
+
+	"usermanagement/internal/application/validation"
+	"usermanagement/internal/domain/errcode"
+	"usermanagement/internal/domain/moderation"
+	"usermanagement/internal/domain/user"
+)
+
+// BulkCreateUsersUseCase implements the bulk user creation use case.
+type BulkCreateUsersUseCase struct {
+	repo      user.UserRepository
+	ids       user.IDGenerator
+	hasher    user.PasswordHasher
+	policy    moderation.Policy
+	validator *validation.Validator
+}
+
+// NewBulkCreateUsersUseCase creates a new instance.
+func NewBulkCreateUsersUseCase(repo user.UserRepository, ids user.IDGenerator, hasher user.PasswordHasher, policy moderation.Policy, validator *validation.Validator) *BulkCreateUsersUseCase {
+	return &BulkCreateUsersUseCase{repo: repo, ids: ids, hasher: hasher, policy: policy, validator: validator}
+}
+
+// Execute checks every item like a single create and saves the valid ones
+// with one repository call. Items that fail validation, the content policy
+// or on a taken email are reported without failing the others; within the
+// request the first item with an email wins. Other errors fail the whole
+// request.
+func (uc *BulkCreateUsersUseCase) Execute(ctx context.Context, input BulkCreateUsersInput) (*BulkCreateUsersOutput, error) {
+	if err := uc.validator.Struct(input); err != nil {
+		return nil, err
+	}
+
+	results := make([]BulkUserResult, len(input.Users))
+	users := make([]*user.User, len(input.Users))
+
+	// Password hashing is CPU bound; spread it over the cores.
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(runtime.GOMAXPROCS(0))
+	for i, item := range input.Users {
+		i, item := i, item
+		results[i].Index = i
+		g.Go(func() error {
+			u, err := uc.prepare(gctx, item)
+			if err != nil {
+				if results[i].Error = itemError(err); results[i].Error == nil {
+					return fmt.Errorf("user %d: %w", i, err)
+				}
+				return nil
+			}
+			users[i] = u
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	batch := make([]*user.User, 0, len(users))
+	seen := make(map[string]bool, len(users))
+	for i, u := range users {
+		if u == nil {
+			continue
+		}
+		if seen[u.Email()] {
+			results[i].Error = itemError(user.ErrEmailExists)
+			users[i] = nil
+			continue
+		}
+		seen[u.Email()] = true
+		batch = append(batch, u)
+	}
+
+	saved := make(map[uuid.UUID]bool, len(batch))
+	if len(batch) > 0 {
+		ids, err := uc.repo.SaveBatch(ctx, batch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to save users: %w", err)
+		}
+		for _, id := range ids {
+			saved[id] = true
+		}
+	}
+
+	output := &BulkCreateUsersOutput{Results: results}
+	for i, u := range users {
+		switch {
+		case u == nil:
+		case saved[u.ID()]:
+			created := MapFromDomain(u)
+			results[i].User = &created
+		default:
+			results[i].Error = itemError(user.ErrEmailExists)
+		}
+		if results[i].User != nil {
+			output.Created++
+		} else {
+			output.Failed++
+		}
+	}
+	return output, nil
+}
+
+// prepare checks one item and builds its user, as CreateUserUseCase does.
+func (uc *BulkCreateUsersUseCase) prepare(ctx context.Context, input CreateUserInput) (*user.User, error) {
+	if err := uc.validator.Struct(input); err != nil {
+		return nil, err
+	}
+
+	if _, err := moderation.Enforce(ctx, uc.policy, moderation.FieldUserName, input.Name); err != nil {
+		return nil, err
+	}
+
+	id, err := uc.ids.NewID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate user id: %w", err)
+	}
+
+	u, err := user.New(id, input.Name, input.Email)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.SetPassword(uc.hasher, input.Password); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// itemError describes err for one item, or returns nil when err is not
+// about the item itself and should fail the request.
+func itemError(err error) *BulkUserError {
+	var verr *validation.Error
+	if errors.As(err, &verr) {
+		return &BulkUserError{Code: errcode.ValidationFailed, Message: "validation failed", Fields: verr.Fields}
+	}
+
+	var coded *errcode.Error
+	if !errors.As(err, &coded) {
+		return nil
+	}
+	switch coded.Code {
+	case errcode.ValidationFailed, errcode.ContentRejected, errcode.EmailConflict:
+		return &BulkUserError{Code: coded.Code, Message: coded.Message}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"usermanagement/internal/domain/user"
+)
+
+// CreateOrGetUserOutput is the user a create-or-get request resolved to.
+type CreateOrGetUserOutput struct {
+	User UserOutput
+	// Created is false when the user already existed.
+	Created bool
+}
+
+// CreateOrGetUserUseCase creates a user, or returns the existing one when a
+// retried request already did. It lets integrators retry registrations
+// without having to tell a lost response from a real conflict.
+type CreateOrGetUserUseCase struct {
+	create *CreateUserUseCase
+	repo   user.UserRepository
+	hasher user.PasswordHasher
+}
+
+// NewCreateOrGetUserUseCase creates a new instance.
+func NewCreateOrGetUserUseCase(create *CreateUserUseCase, repo user.UserRepository, hasher user.PasswordHasher) *CreateOrGetUserUseCase {
+	return &CreateOrGetUserUseCase{create: create, repo: repo, hasher: hasher}
+}
+
+// Execute runs the use case. The existing user is only returned when the
+// password matches, so the endpoint can't be used to look accounts up;
+// otherwise the request fails with ErrEmailExists like a plain create.
+func (uc *CreateOrGetUserUseCase) Execute(ctx context.Context, input CreateUserInput) (*CreateOrGetUserOutput, error) {
+	created, err := uc.create.Execute(ctx, input)
+	if err == nil {
+		return &CreateOrGetUserOutput{User: *created, Created: true}, nil
+	}
+	if !errors.Is(err, user.ErrEmailExists) {
+		return nil, err
+	}
+
+	existing, err := uc.repo.FindByEmail(ctx, strings.ToLower(strings.TrimSpace(input.Email)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find existing user: %w", err)
+	}
+	if !existing.VerifyPassword(uc.hasher, input.Password) {
+		return nil, user.ErrEmailExists
+	}
+
+	return &CreateOrGetUserOutput{User: MapFromDomain(existing), Created: false}, nil
+}
@@ -2,44 +2,57 @@ package user
 
 import (
 	"context"
-	"errors"
 	"fmt"
 
+	"usermanagement/internal/application/validation"
+	"usermanagement/internal/domain/moderation"
 	"usermanagement/internal/domain/user"
 )
 
 // CreateUserUseCase implements the create user use case.
 type CreateUserUseCase struct {
-	repo user.UserRepository
+	repo      user.UserRepository
+	ids       user.IDGenerator
+	hasher    user.PasswordHasher
+	policy    moderation.Policy
+	validator *validation.Validator
 }
 
 // NewCreateUserUseCase creates a new instance.
-func NewCreateUserUseCase(repo user.UserRepository) *CreateUserUseCase {
-	return &CreateUserUseCase{repo: repo}
+func NewCreateUserUseCase(repo user.UserRepository, ids user.IDGenerator, hasher user.PasswordHasher, policy moderation.Policy, validator *validation.Validator) *CreateUserUseCase {
+	return &CreateUserUseCase{repo: repo, ids: ids, hasher: hasher, policy: policy, validator: validator}
 }
 
 // Execute runs the use case.
 func (uc *CreateUserUseCase) Execute(ctx context.Context, input CreateUserInput) (*UserOutput, error) {
-	// Check email uniqueness
-	existing, err := uc.repo.FindByEmail(ctx, input.Email)
-	if err != nil && !errors.Is(err, user.ErrUserNotFound) {
-		return nil, fmt.Errorf("failed to check email existence: %w", err)
+	if err := uc.validator.Struct(input); err != nil {
+		return nil, err
 	}
-	if existing != nil {
-		return nil, user.ErrEmailExists
+
+	if _, err := moderation.Enforce(ctx, uc.policy, moderation.FieldUserName, input.Name); err != nil {
+		return nil, err
+	}
+
+	id, err := uc.ids.NewID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate user id: %w", err)
 	}
 
 	// Create domain entity (validates invariants)
-	domainUser, err := user.New(input.Name, input.Email)
+	domainUser, err := user.New(id, input.Name, input.Email)
 	if err != nil {
 		return nil, err // Domain error propagates directly
 	}
+	if err := domainUser.SetPassword(uc.hasher, input.Password); err != nil {
+		return nil, err
+	}
 
-	// Persist
+	// Persist. Email uniqueness is enforced by the database: a duplicate,
+	// even from a concurrent request, comes back as ErrEmailExists.
 	if err := uc.repo.Save(ctx, domainUser); err != nil {
 		return nil, fmt.Errorf("failed to save user: %w", err)
 	}
 
 	output := MapFromDomain(domainUser)
 	return &output, nil
-}
\ No newline at end of file
+}
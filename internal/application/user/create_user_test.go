@@ -0,0 +1,111 @@
+package user_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	app "usermanagement/internal/application/user"
+	"usermanagement/internal/application/validation"
+	"usermanagement/internal/domain/user"
+	"usermanagement/internal/infra/auth"
+	"usermanagement/internal/infra/idgen"
+	"usermanagement/internal/infra/moderation"
+	"usermanagement/internal/infra/persistence/memory"
+)
+
+// newCreateUser wires the create user use case on memory storage.
+func newCreateUser(t *testing.T) (*app.CreateUserUseCase, *memory.UserRepository, user.PasswordHasher) {
+	t.Helper()
+
+	validator, err := validation.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := app.RegisterValidators(validator, app.ValidationRules{}); err != nil {
+		t.Fatal(err)
+	}
+	ids, err := idgen.New("v7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hasher, err := auth.NewBcryptHasher(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	users := memory.NewUserRepository()
+	return app.NewCreateUserUseCase(users, ids, hasher, moderation.NewWordList(nil, nil), validator), users, hasher
+}
+
+func TestCreateUserConcurrentDuplicates(t *testing.T) {
+	createUser, users, _ := newCreateUser(t)
+
+	const n = 20
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			_, errs[i] = createUser.Execute(context.Background(), app.CreateUserInput{
+				Name:     "Ada",
+				Email:    "ada@example.com",
+				Password: "correct-horse",
+			})
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	created := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			created++
+		case !errors.Is(err, user.ErrEmailExists):
+			t.Errorf("Execute = %v, want nil or ErrEmailExists", err)
+		}
+	}
+	if created != 1 {
+		t.Fatalf("%d of %d concurrent creates succeeded, want exactly 1", created, n)
+	}
+	if total, _, err := users.CountUsers(context.Background(), time.Time{}); err != nil || total != 1 {
+		t.Fatalf("stored %d users (err %v), want 1", total, err)
+	}
+}
+
+func TestCreateOrGetUser(t *testing.T) {
+	createUser, users, hasher := newCreateUser(t)
+	createOrGet := app.NewCreateOrGetUserUseCase(createUser, users, hasher)
+	ctx := context.Background()
+	input := app.CreateUserInput{Name: "Ada", Email: "ada@example.com", Password: "correct-horse"}
+
+	first, err := createOrGet.Execute(ctx, input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !first.Created {
+		t.Fatal("first request did not create the user")
+	}
+
+	// A retry, even with the email in another case, gets the same user.
+	input.Email = "ADA@example.com"
+	retry, err := createOrGet.Execute(ctx, input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retry.Created || retry.User.ID != first.User.ID {
+		t.Fatalf("retry = %+v, want the existing user %s", retry, first.User.ID)
+	}
+
+	// Without the password it is a plain conflict, not an account lookup.
+	input.Password = "wrong-horse"
+	if _, err := createOrGet.Execute(ctx, input); !errors.Is(err, user.ErrEmailExists) {
+		t.Fatalf("Execute with another password = %v, want ErrEmailExists", err)
+	}
+}
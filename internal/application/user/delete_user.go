@@ -5,25 +5,62 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/google/uuid"
-
+	"usermanagement/internal/application/auth"
+	"usermanagement/internal/application/usecase"
+	"usermanagement/internal/domain/transaction"
 	"usermanagement/internal/domain/user"
 )
 
 // DeleteUserUseCase implements the delete user use case.
 type DeleteUserUseCase struct {
 	repo user.UserRepository
+	tx   transaction.UnitOfWork
 }
 
 // NewDeleteUserUseCase creates a new instance.
-func NewDeleteUserUseCase(repo user.UserRepository) *DeleteUserUseCase {
-	return &DeleteUserUseCase{repo: repo}
+func NewDeleteUserUseCase(repo user.UserRepository, tx transaction.UnitOfWork) *DeleteUserUseCase {
+	return &DeleteUserUseCase{repo: repo, tx: tx}
+}
+
+// Execute deletes a user. Only admins may delete users.
+func (uc *DeleteUserUseCase) Execute(ctx context.Context, input DeleteUserInput) error {
+	return uc.tx.Do(ctx, func(ctx context.Context) error {
+		if err := uc.check(ctx, input); err != nil {
+			return err
+		}
+
+		// Not every store locks the user between check and delete, so the
+		// delete carries the expected version too: an update stored in
+		// between fails it with ErrVersionConflict.
+		var version int64
+		if input.IfVersion != nil {
+			version = *input.IfVersion
+		}
+		if err := uc.repo.Delete(ctx, input.ID, version); err != nil {
+			return fmt.Errorf("failed to delete user: %w", err)
+		}
+
+		return nil
+	})
 }
 
-// Execute deletes a user.
-func (uc *DeleteUserUseCase) Execute(ctx context.Context, id uuid.UUID) error {
+// Preview reports what Execute would delete, without deleting it.
+func (uc *DeleteUserUseCase) Preview(ctx context.Context, input DeleteUserInput) ([]usecase.Change, error) {
+	if err := uc.check(ctx, input); err != nil {
+		return nil, err
+	}
+	return []usecase.Change{{Action: "delete", Resource: "user", ID: input.ID.String()}}, nil
+}
+
+// check verifies the caller may delete the user, that it exists and that
+// it is at the version the caller expects.
+func (uc *DeleteUserUseCase) check(ctx context.Context, input DeleteUserInput) error {
+	if caller, ok := auth.CallerFrom(ctx); !ok || !caller.IsAdmin() {
+		return auth.ErrForbidden
+	}
+
 	// Verify existence first
-	_, err := uc.repo.FindByID(ctx, id)
+	existing, err := uc.repo.FindByID(ctx, input.ID)
 	if err != nil {
 		if errors.Is(err, user.ErrUserNotFound) {
 			return user.ErrUserNotFound
@@ -31,9 +68,8 @@ func (uc *DeleteUserUseCase) Execute(ctx context.Context, id uuid.UUID) error {
 		return fmt.Errorf("failed to find user: %w", err)
 	}
 
-	if err := uc.repo.Delete(ctx, id); err != nil {
-		return fmt.Errorf("failed to delete user: %w", err)
+	if input.IfVersion != nil {
+		return existing.CheckVersion(*input.IfVersion)
 	}
-
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,64 @@
+package user_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"usermanagement/internal/application/auth"
+	app "usermanagement/internal/application/user"
+	"usermanagement/internal/domain/user"
+	"usermanagement/internal/infra/persistence/memory"
+)
+
+// racingRepo stores an update of every user right after it was read, as a
+// concurrent writer would on a store that does not lock what it reads.
+type racingRepo struct {
+	*memory.UserRepository
+}
+
+func (r racingRepo) FindByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	u, err := r.UserRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	racer, _ := r.UserRepository.FindByID(ctx, id)
+	if err := racer.UpdateName("Racer"); err != nil {
+		return nil, err
+	}
+	if err := r.UserRepository.Update(ctx, racer); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func TestDeleteUserRefusesUpdatesStoredAfterTheCheck(t *testing.T) {
+	users := memory.NewUserRepository()
+	now := time.Now()
+	id := uuid.New()
+	if err := users.Save(context.Background(), user.Reconstruct(id, "Ada", "ada@example.com", "hash", user.RoleViewer, now, now, 1)); err != nil {
+		t.Fatal(err)
+	}
+	deleteUser := app.NewDeleteUserUseCase(racingRepo{users}, memory.NewUnitOfWork())
+	ctx := auth.WithCaller(context.Background(), auth.Caller{Role: user.RoleAdmin})
+
+	version := int64(1)
+	err := deleteUser.Execute(ctx, app.DeleteUserInput{ID: id, IfVersion: &version})
+	if !errors.Is(err, user.ErrVersionConflict) {
+		t.Fatalf("Execute = %v, want ErrVersionConflict", err)
+	}
+	if u, err := users.FindByID(context.Background(), id); err != nil || u.Name() != "Racer" {
+		t.Fatalf("user after the refused delete = %v, %v; want the racing update kept", u, err)
+	}
+
+	// Without If-Match the user is deleted whatever changed meanwhile.
+	if err := deleteUser.Execute(ctx, app.DeleteUserInput{ID: id}); err != nil {
+		t.Fatalf("Execute without If-Match = %v", err)
+	}
+	if _, err := users.FindByID(context.Background(), id); !errors.Is(err, user.ErrUserNotFound) {
+		t.Fatalf("user after delete: %v, want ErrUserNotFound", err)
+	}
+}
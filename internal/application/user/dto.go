@@ -2,6 +2,9 @@ package user
 
 import (
 	"time"
+	"usermanagement/internal/application/pagination"
+	"usermanagement/internal/application/validation"
+	"usermanagement/internal/domain/errcode"
 	"usermanagement/internal/domain/user"
 
 	"github.com/google/uuid"
@@ -12,15 +15,93 @@ import (
 
 // CreateUserInput represents data needed to create a user.
 type CreateUserInput struct {
-	Name  string `json:"name"`
-	Email string `json:"email"`
+	Name  string `json:"name" validate:"notblank,max=100"`
+	Email string `json:"email" validate:"required,email,email_domain"`
+	// Password is bounded by bcrypt's 72-byte input limit.
+	Password string `json:"password" validate:"required,min=8,max=72"`
+}
+
+// SignupInput represents a public self-registration.
+type SignupInput struct {
+	Name     string `json:"name" validate:"notblank,max=100"`
+	Email    string `json:"email" validate:"required,email,email_domain,not_disposable"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+	// CaptchaToken is the response token of the CAPTCHA widget.
+	CaptchaToken string `json:"captcha_token"`
+	RemoteIP     string `json:"-"` // From the connection, not body
+}
+
+// MaxBulkUsers caps the users of one bulk creation. Hashing their
+// passwords dominates its cost, so larger imports should be split.
+const MaxBulkUsers = 100
+
+// BulkCreateUsersInput holds the users of a bulk creation.
+type BulkCreateUsersInput struct {
+	// Keep max in sync with MaxBulkUsers.
+	Users []CreateUserInput `json:"users" validate:"min=1,max=100"`
+}
+
+// BulkCreateUsersOutput reports every item of a bulk creation, in request
+// order.
+type BulkCreateUsersOutput struct {
+	Created int              `json:"created"`
+	Failed  int              `json:"failed"`
+	Results []BulkUserResult `json:"results"`
+}
+
+// BulkUserResult is the user an item created, or why it was not created.
+type BulkUserResult struct {
+	Index int            `json:"index"`
+	User  *UserOutput    `json:"user,omitempty"`
+	Error *BulkUserError `json:"error,omitempty"`
+}
+
+// BulkUserError is the error body a single create would have answered.
+type BulkUserError struct {
+	Code    errcode.Code            `json:"code"`
+	Message string                  `json:"error"`
+	Fields  []validation.FieldError `json:"fields,omitempty"`
 }
 
 // UpdateUserInput represents data needed to update a user.
 type UpdateUserInput struct {
 	ID    uuid.UUID `json:"-"` // From URL param, not body
-	Name  *string   `json:"name,omitempty"`
-	Email *string   `json:"email,omitempty"`
+	Name  *string   `json:"name,omitempty" validate:"omitempty,notblank,max=100"`
+	Email *string   `json:"email,omitempty" validate:"omitempty,email,email_domain"`
+	// IfVersion, from If-Match, refuses the update unless the stored user
+	// is still at this version. Nil updates whatever is stored.
+	IfVersion *int64 `json:"-"`
+}
+
+// DeleteUserInput identifies a user to delete.
+type DeleteUserInput struct {
+	ID uuid.UUID
+	// IfVersion, from If-Match, refuses the deletion unless the stored user
+	// is still at this version. Nil deletes whatever is stored.
+	IfVersion *int64
+}
+
+// LoginUserInput holds the credentials submitted to log in.
+type LoginUserInput struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+// ActivateUserInput holds the activation token mailed to a user who signed
+// up.
+type ActivateUserInput struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// RefreshTokenInput holds a refresh token to exchange for new tokens.
+type RefreshTokenInput struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// SearchUsersInput selects a page of users matching a search query.
+type SearchUsersInput struct {
+	Query string `json:"q" validate:"notblank,max=200"`
+	Page  pagination.Params
 }
 
 // UserOutput represents user data returned to clients.
@@ -28,29 +109,21 @@ type UserOutput struct {
 	ID        uuid.UUID `json:"id"`
 	Name      string    `json:"name"`
 	Email     string    `json:"email"`
+	Role      string    `json:"role"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	Version   int64     `json:"version"`
 }
 
 // MapFromDomain converts domain entity to output DTO.
-func MapFromDomain(u * user.User) UserOutput {
+func MapFromDomain(u *user.User) UserOutput {
 	return UserOutput{
 		ID:        u.ID(),
 		Name:      u.Name(),
 		Email:     u.Email(),
+		Role:      string(u.Role()),
 		CreatedAt: u.CreatedAt(),
 		UpdatedAt: u.UpdatedAt(),
+		Version:   u.Version(),
 	}
 }
-
-// PaginationInput for list operations.
-type PaginationInput struct {
-	Limit  int `json:"limit"`
-	Offset int `json:"offset"`
-}
-
-// ListUsersOutput represents paginated user list.
-type ListUsersOutput struct {
-	Users []*UserOutput `json:"users"`
-	Total int           `json:"total"`
-}
\ No newline at end of file
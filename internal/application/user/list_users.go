@@ -0,0 +1,77 @@
+package user
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/sync/errgroup"
+
+	"usermanagement/internal/application/pagination"
+	"usermanagement/internal/domain/user"
+)
+
+// ListUsersUseCase implements the list users use case.
+type ListUsersUseCase struct {
+	repo user.UserRepository
+}
+
+// NewListUsersUseCase creates a new instance.
+func NewListUsersUseCase(repo user.UserRepository) *ListUsersUseCase {
+	return &ListUsersUseCase{repo: repo}
+}
+
+// Execute returns a page of users, newest first. Pages are addressed by
+// offset or, so deep pages stay cheap, by the cursor every page but the
+// last returns.
+func (uc *ListUsersUseCase) Execute(ctx context.Context, params pagination.Params) (*pagination.Page[UserOutput], error) {
+	params = params.Normalize()
+
+	var keyset *user.Keyset
+	if params.Cursor != "" {
+		createdAt, id, err := pagination.DecodeCursor(params.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		keyset = &user.Keyset{CreatedAt: createdAt, ID: id}
+	}
+
+	// The page and the total are independent queries, so run them side by
+	// side rather than paying for two round trips in a row.
+	var users []*user.User
+	var total int64
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() (err error) {
+		// One extra row tells whether another page follows.
+		if keyset != nil {
+			users, err = uc.repo.FindAfter(gctx, keyset, params.Limit+1)
+		} else {
+			users, err = uc.repo.FindAll(gctx, params.Limit+1, params.Offset)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to list users: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() (err error) {
+		if total, err = uc.repo.Count(gctx); err != nil {
+			return fmt.Errorf("failed to count users: %w", err)
+		}
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	var next string
+	if len(users) > params.Limit {
+		users = users[:params.Limit]
+		last := users[len(users)-1]
+		next = pagination.EncodeCursor(last.CreatedAt(), last.ID())
+	}
+
+	outputs := make([]UserOutput, len(users))
+	for i, u := range users {
+		outputs[i] = MapFromDomain(u)
+	}
+	return pagination.NewCursorPage(outputs, params, total, next), nil
+}
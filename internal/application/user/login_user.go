@@ -1 +1,61 @@
-package user
\ No newline at end of file
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"usermanagement/internal/application/auth"
+	"usermanagement/internal/application/validation"
+	"usermanagement/internal/domain/user"
+)
+
+// LoginUserUseCase implements the login use case.
+type LoginUserUseCase struct {
+	repo        user.UserRepository
+	activations user.ActivationRepository
+	tokens      auth.TokenIssuer
+	hasher      user.PasswordHasher
+	validator   *validation.Validator
+}
+
+// NewLoginUserUseCase creates a new instance. Users awaiting activation are
+// refused when activations is set.
+func NewLoginUserUseCase(repo user.UserRepository, activations user.ActivationRepository, tokens auth.TokenIssuer, hasher user.PasswordHasher, validator *validation.Validator) *LoginUserUseCase {
+	return &LoginUserUseCase{repo: repo, activations: activations, tokens: tokens, hasher: hasher, validator: validator}
+}
+
+// Execute verifies the credentials and issues a token pair. Unknown emails
+// and wrong passwords fail alike, so callers can't probe for accounts.
+func (uc *LoginUserUseCase) Execute(ctx context.Context, input LoginUserInput) (*auth.Tokens, error) {
+	if err := uc.validator.Struct(input); err != nil {
+		return nil, err
+	}
+
+	domainUser, err := uc.repo.FindByEmail(ctx, strings.ToLower(strings.TrimSpace(input.Email)))
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, auth.ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	if !domainUser.VerifyPassword(uc.hasher, input.Password) {
+		return nil, auth.ErrInvalidCredentials
+	}
+
+	// Checked after the password, so only the account's owner learns that
+	// it is not active yet.
+	if uc.activations != nil {
+		pending, err := uc.activations.Pending(ctx, domainUser.ID())
+		if err != nil {
+			return nil, fmt.Errorf("failed to check activation: %w", err)
+		}
+		if pending {
+			return nil, user.ErrNotActivated
+		}
+	}
+
+	return uc.tokens.Issue(domainUser.ID(), domainUser.Role())
+}
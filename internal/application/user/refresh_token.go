@@ -0,0 +1,48 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"usermanagement/internal/application/auth"
+	"usermanagement/internal/application/validation"
+	"usermanagement/internal/domain/user"
+)
+
+// RefreshTokenUseCase implements the token refresh use case.
+type RefreshTokenUseCase struct {
+	repo      user.UserRepository
+	tokens    auth.TokenIssuer
+	validator *validation.Validator
+}
+
+// NewRefreshTokenUseCase creates a new instance.
+func NewRefreshTokenUseCase(repo user.UserRepository, tokens auth.TokenIssuer, validator *validation.Validator) *RefreshTokenUseCase {
+	return &RefreshTokenUseCase{repo: repo, tokens: tokens, validator: validator}
+}
+
+// Execute exchanges a valid refresh token for a new token pair, as long as
+// the user still exists.
+func (uc *RefreshTokenUseCase) Execute(ctx context.Context, input RefreshTokenInput) (*auth.Tokens, error) {
+	if err := uc.validator.Struct(input); err != nil {
+		return nil, err
+	}
+
+	id, err := uc.tokens.VerifyRefresh(input.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// Re-read the user so deleted users can't refresh and role changes
+	// take effect.
+	domainUser, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return nil, auth.ErrInvalidToken
+		}
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	return uc.tokens.Issue(domainUser.ID(), domainUser.Role())
+}
@@ -0,0 +1,42 @@
+package user
+
+import (
+	"context"
+	"fmt"
+
+	"usermanagement/internal/application/pagination"
+	"usermanagement/internal/application/validation"
+	"usermanagement/internal/domain/user"
+)
+
+// SearchUsersUseCase implements the user search use case.
+type SearchUsersUseCase struct {
+	repo      user.UserRepository
+	validator *validation.Validator
+}
+
+// NewSearchUsersUseCase creates a new instance.
+func NewSearchUsersUseCase(repo user.UserRepository, validator *validation.Validator) *SearchUsersUseCase {
+	return &SearchUsersUseCase{repo: repo, validator: validator}
+}
+
+// Execute returns a page of the users whose name or email matches the
+// query, best match first. Search pages are addressed by offset only.
+func (uc *SearchUsersUseCase) Execute(ctx context.Context, input SearchUsersInput) (*pagination.Page[UserOutput], error) {
+	if err := uc.validator.Struct(input); err != nil {
+		return nil, err
+	}
+
+	input.Page.Cursor = ""
+	params := input.Page.Normalize()
+	result, err := uc.repo.Search(ctx, input.Query, user.Page{Limit: params.Limit, Offset: params.Offset})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search users: %w", err)
+	}
+
+	outputs := make([]UserOutput, len(result.Hits))
+	for i, hit := range result.Hits {
+		outputs[i] = MapFromDomain(hit.User)
+	}
+	return pagination.NewPage(outputs, params, result.Total), nil
+}
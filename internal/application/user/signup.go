@@ -0,0 +1,121 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"usermanagement/internal/application/validation"
+	"usermanagement/internal/domain/errcode"
+	"usermanagement/internal/domain/transaction"
+	"usermanagement/internal/domain/user"
+)
+
+var (
+	// ErrCaptchaFailed is returned when the CAPTCHA token is missing or was
+	// not accepted by the provider.
+	ErrCaptchaFailed = errcode.New(errcode.CaptchaFailed, "captcha verification failed")
+	// ErrTooManySignups is returned when an address signs up too often.
+	ErrTooManySignups = errcode.New(errcode.RateLimited, "too many signups, try again later")
+)
+
+// CaptchaVerifier checks a CAPTCHA response token solved by a client.
+// Implementations must return ErrCaptchaFailed for tokens they reject.
+type CaptchaVerifier interface {
+	Verify(ctx context.Context, token, remoteIP string) error
+}
+
+// SignupLimiter bounds how often one key, such as a client IP, may sign up.
+type SignupLimiter interface {
+	Allow(key string) bool
+}
+
+// SignupActivation holds signups back until their owners activate them.
+type SignupActivation struct {
+	Activations user.ActivationRepository
+	Tx          transaction.UnitOfWork
+	// TTL is how long an activation token stays valid.
+	TTL time.Duration
+}
+
+// SignupUseCase implements public self-registration. Unlike the plain create
+// user use case it is meant to face the internet: callers are rate limited
+// per IP, must solve a CAPTCHA and may not use disposable mailboxes.
+type SignupUseCase struct {
+	create     *CreateUserUseCase
+	captcha    CaptchaVerifier
+	limiter    SignupLimiter
+	validator  *validation.Validator
+	activation *SignupActivation
+}
+
+// NewSignupUseCase creates a new instance. With activation, new users may
+// not log in until they activate their account; without it they are active
+// at once.
+func NewSignupUseCase(create *CreateUserUseCase, captcha CaptchaVerifier, limiter SignupLimiter, validator *validation.Validator, activation *SignupActivation) *SignupUseCase {
+	return &SignupUseCase{create: create, captcha: captcha, limiter: limiter, validator: validator, activation: activation}
+}
+
+// Execute runs the use case. Checks run cheapest first, so floods are turned
+// away before they cost a CAPTCHA round trip or a password hash.
+func (uc *SignupUseCase) Execute(ctx context.Context, input SignupInput) (*UserOutput, error) {
+	if !uc.limiter.Allow(input.RemoteIP) {
+		return nil, ErrTooManySignups
+	}
+
+	if err := uc.validator.Struct(input); err != nil {
+		return nil, err
+	}
+
+	if err := uc.captcha.Verify(ctx, input.CaptchaToken, input.RemoteIP); err != nil {
+		return nil, err
+	}
+
+	create := CreateUserInput{
+		Name:     input.Name,
+		Email:    input.Email,
+		Password: input.Password,
+	}
+	if uc.activation == nil {
+		return uc.create.Execute(ctx, create)
+	}
+	return uc.createPending(ctx, create)
+}
+
+// createPending creates a user held back by an activation. The token only
+// leaves in the user.activation_requested event, for a mailer to send.
+func (uc *SignupUseCase) createPending(ctx context.Context, input CreateUserInput) (*UserOutput, error) {
+	token, hash, err := newActivationToken()
+	if err != nil {
+		return nil, err
+	}
+
+	var output *UserOutput
+	err = uc.activation.Tx.Do(ctx, func(ctx context.Context) error {
+		created, err := uc.create.Execute(ctx, input)
+		if err != nil {
+			return err
+		}
+		output = created
+
+		expiresAt := time.Now().Add(uc.activation.TTL)
+		return uc.activation.Activations.Save(ctx,
+			user.Activation{UserID: created.ID, TokenHash: hash, ExpiresAt: expiresAt},
+			user.ActivationRequest{ID: created.ID, Name: created.Name, Email: created.Email, Token: token, ExpiresAt: expiresAt},
+		)
+	})
+	if err == nil {
+		return output, nil
+	}
+	if output == nil {
+		return nil, err
+	}
+
+	// Sharded users are saved outside the unit of work, so a user whose
+	// activation failed may still exist, and would be active.
+	if delErr := uc.create.repo.Delete(context.WithoutCancel(ctx), output.ID, 0); delErr != nil && !errors.Is(delErr, user.ErrUserNotFound) {
+		return nil, fmt.Errorf("failed to save activation: %w (and to remove the user: %v)", err, delErr)
+	}
+	return nil, fmt.Errorf("failed to save activation: %w", err)
+}
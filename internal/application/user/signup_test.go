@@ -0,0 +1,114 @@
+package user_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	app "usermanagement/internal/application/user"
+	"usermanagement/internal/application/validation"
+	"usermanagement/internal/domain/user"
+	"usermanagement/internal/infra/auth"
+	"usermanagement/internal/infra/captcha"
+	"usermanagement/internal/infra/persistence/memory"
+	"usermanagement/internal/infra/ratelimit"
+)
+
+// activations keeps activations in memory and the requests that would have
+// been published, in place of the outbox.
+type activations struct {
+	mu       sync.Mutex
+	pending  []user.Activation
+	requests []user.ActivationRequest
+}
+
+func (a *activations) Save(_ context.Context, activation user.Activation, request user.ActivationRequest) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.pending = append(a.pending, activation)
+	a.requests = append(a.requests, request)
+	return nil
+}
+
+func (a *activations) Pending(_ context.Context, userID uuid.UUID) (bool, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, p := range a.pending {
+		if p.UserID == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (a *activations) Activate(_ context.Context, tokenHash []byte, now time.Time) (uuid.UUID, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for i, p := range a.pending {
+		if bytes.Equal(p.TokenHash, tokenHash) && p.ExpiresAt.After(now) {
+			a.pending = append(a.pending[:i], a.pending[i+1:]...)
+			return p.UserID, nil
+		}
+	}
+	return uuid.Nil, user.ErrActivationInvalid
+}
+
+func TestSignupWaitsForActivation(t *testing.T) {
+	createUser, users, hasher := newCreateUser(t)
+	validator, err := validation.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := app.RegisterValidators(validator, app.ValidationRules{}); err != nil {
+		t.Fatal(err)
+	}
+	tokens, err := auth.NewJWTIssuer("signup-test-secret-0123456789abcdef", "usermanagement", 15*time.Minute, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := &activations{}
+	signup := app.NewSignupUseCase(createUser, captcha.Disabled{}, ratelimit.NewWindow(100, time.Hour), validator,
+		&app.SignupActivation{Activations: store, Tx: memory.NewUnitOfWork(), TTL: time.Hour})
+	login := app.NewLoginUserUseCase(users, store, tokens, hasher, validator)
+	activate := app.NewActivateUserUseCase(store, users, validator)
+	ctx := context.Background()
+
+	created, err := signup.Execute(ctx, app.SignupInput{Name: "Ada", Email: "ada@example.com", Password: "correct-horse", RemoteIP: "192.0.2.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(store.requests) != 1 || store.requests[0].ID != created.ID || store.requests[0].Email != "ada@example.com" {
+		t.Fatalf("activation requests = %+v, want one for the new user", store.requests)
+	}
+
+	credentials := app.LoginUserInput{Email: "ada@example.com", Password: "correct-horse"}
+	if _, err := login.Execute(ctx, credentials); !errors.Is(err, user.ErrNotActivated) {
+		t.Fatalf("login before activation = %v, want ErrNotActivated", err)
+	}
+	// Only the owner of the password learns the account awaits activation.
+	if _, err := login.Execute(ctx, app.LoginUserInput{Email: "ada@example.com", Password: "wrong-horse"}); errors.Is(err, user.ErrNotActivated) {
+		t.Fatalf("login with a wrong password = %v, want it refused as invalid credentials", err)
+	}
+
+	if _, err := activate.Execute(ctx, app.ActivateUserInput{Token: "not-the-token"}); !errors.Is(err, user.ErrActivationInvalid) {
+		t.Fatalf("activation with an unknown token = %v, want ErrActivationInvalid", err)
+	}
+	activated, err := activate.Execute(ctx, app.ActivateUserInput{Token: store.requests[0].Token})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if activated.ID != created.ID {
+		t.Fatalf("activated user %v, want %v", activated.ID, created.ID)
+	}
+	if _, err := login.Execute(ctx, credentials); err != nil {
+		t.Fatalf("login after activation = %v", err)
+	}
+	if _, err := activate.Execute(ctx, app.ActivateUserInput{Token: store.requests[0].Token}); !errors.Is(err, user.ErrActivationInvalid) {
+		t.Fatalf("second activation = %v, want ErrActivationInvalid", err)
+	}
+}
@@ -5,21 +5,49 @@ import (
 	"errors"
 	"fmt"
 
+	"usermanagement/internal/application/auth"
+	"usermanagement/internal/application/validation"
+	"usermanagement/internal/domain/moderation"
+	"usermanagement/internal/domain/transaction"
 	"usermanagement/internal/domain/user"
 )
 
 // UpdateUserUseCase implements the update user use case.
 type UpdateUserUseCase struct {
-	repo user.UserRepository
+	repo      user.UserRepository
+	tx        transaction.UnitOfWork
+	policy    moderation.Policy
+	validator *validation.Validator
 }
 
 // NewUpdateUserUseCase creates a new instance.
-func NewUpdateUserUseCase(repo user.UserRepository) *UpdateUserUseCase {
-	return &UpdateUserUseCase{repo: repo}
+func NewUpdateUserUseCase(repo user.UserRepository, tx transaction.UnitOfWork, policy moderation.Policy, validator *validation.Validator) *UpdateUserUseCase {
+	return &UpdateUserUseCase{repo: repo, tx: tx, policy: policy, validator: validator}
 }
 
-// Execute updates a user.
+// Execute updates a user. Users may update themselves; admins may update
+// anyone.
 func (uc *UpdateUserUseCase) Execute(ctx context.Context, input UpdateUserInput) (*UserOutput, error) {
+	if caller, ok := auth.CallerFrom(ctx); !ok || (caller.UserID != input.ID && !caller.IsAdmin()) {
+		return nil, auth.ErrForbidden
+	}
+
+	if err := uc.validator.Struct(input); err != nil {
+		return nil, err
+	}
+
+	var output *UserOutput
+	err := uc.tx.Do(ctx, func(ctx context.Context) error {
+		var err error
+		output, err = uc.update(ctx, input)
+		return err
+	})
+	return output, err
+}
+
+// update applies input inside the unit of work, so the user cannot change
+// between being read and written back.
+func (uc *UpdateUserUseCase) update(ctx context.Context, input UpdateUserInput) (*UserOutput, error) {
 	// Retrieve existing
 	domainUser, err := uc.repo.FindByID(ctx, input.ID)
 	if err != nil {
@@ -29,6 +57,12 @@ func (uc *UpdateUserUseCase) Execute(ctx context.Context, input UpdateUserInput)
 		return nil, fmt.Errorf("failed to find user: %w", err)
 	}
 
+	if input.IfVersion != nil {
+		if err := domainUser.CheckVersion(*input.IfVersion); err != nil {
+			return nil, err
+		}
+	}
+
 	// Check email uniqueness if changing email
 	if input.Email != nil && *input.Email != domainUser.Email() {
 		existing, err := uc.repo.FindByEmail(ctx, *input.Email)
@@ -38,7 +72,7 @@ func (uc *UpdateUserUseCase) Execute(ctx context.Context, input UpdateUserInput)
 		if existing != nil {
 			return nil, user.ErrEmailExists
 		}
-		
+
 		if err := domainUser.UpdateEmail(*input.Email); err != nil {
 			return nil, err
 		}
@@ -46,6 +80,9 @@ func (uc *UpdateUserUseCase) Execute(ctx context.Context, input UpdateUserInput)
 
 	// Update name if provided
 	if input.Name != nil {
+		if _, err := moderation.Enforce(ctx, uc.policy, moderation.FieldUserName, *input.Name); err != nil {
+			return nil, err
+		}
 		if err := domainUser.UpdateName(*input.Name); err != nil {
 			return nil, err
 		}
@@ -58,4 +95,4 @@ func (uc *UpdateUserUseCase) Execute(ctx context.Context, input UpdateUserInput)
 
 	output := MapFromDomain(domainUser)
 	return &output, nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,55 @@
+package user
+
+import (
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"usermanagement/internal/application/validation"
+)
+
+// ValidationRules configures the user-specific input rules.
+type ValidationRules struct {
+	// BlockedEmailDomains are rejected by the email_domain rule, e.g.
+	// disposable mailbox providers.
+	BlockedEmailDomains []string
+	// DisposableEmailDomains are rejected, with their subdomains, by the
+	// not_disposable rule applied to public signups.
+	DisposableEmailDomains []string
+}
+
+// RegisterValidators adds the custom rules used by the user DTOs to v.
+func RegisterValidators(v *validation.Validator, rules ValidationRules) error {
+	blocked := make(map[string]bool, len(rules.BlockedEmailDomains))
+	for _, domain := range rules.BlockedEmailDomains {
+		blocked[strings.ToLower(domain)] = true
+	}
+
+	err := v.RegisterRule("email_domain", func(fl validator.FieldLevel) bool {
+		_, domain, ok := strings.Cut(fl.Field().String(), "@")
+		return ok && !blocked[strings.ToLower(strings.TrimSpace(domain))]
+	}, "{0} uses an email domain that is not allowed")
+	if err != nil {
+		return err
+	}
+
+	disposable := make(map[string]bool, len(rules.DisposableEmailDomains))
+	for _, domain := range rules.DisposableEmailDomains {
+		disposable[strings.ToLower(domain)] = true
+	}
+
+	return v.RegisterRule("not_disposable", func(fl validator.FieldLevel) bool {
+		_, domain, ok := strings.Cut(fl.Field().String(), "@")
+		if !ok {
+			return false
+		}
+		// Providers hand out subdomains too, e.g. x.mailinator.com.
+		for domain = strings.ToLower(strings.TrimSpace(domain)); domain != ""; {
+			if disposable[domain] {
+				return false
+			}
+			_, domain, _ = strings.Cut(domain, ".")
+		}
+		return true
+	}, "{0} uses a disposable email provider")
+}
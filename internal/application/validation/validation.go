@@ -0,0 +1,117 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/locales/en"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	entranslations "github.com/go-playground/validator/v10/translations/en"
+
+	"usermanagement/internal/domain/errcode"
+)
+
+// ErrValidation is the coded error every validation failure unwraps to.
+var ErrValidation = errcode.New(errcode.ValidationFailed, "validation failed")
+
+// FieldError describes why a single input field was rejected.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error reports all field errors found in an input.
+type Error struct {
+	Fields []FieldError
+}
+
+func (e *Error) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Message
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// Unwrap lets callers map validation failures through the error code catalog.
+func (e *Error) Unwrap() error {
+	return ErrValidation
+}
+
+// Validator validates DTOs declared with `validate:"..."` struct tags and
+// translates failures into human-readable, field-level messages.
+type Validator struct {
+	validate *validator.Validate
+	trans    ut.Translator
+}
+
+// New creates a validator with the built-in rules and English messages, plus
+// the "notblank" rule for strings that must contain non-whitespace.
+func New() (*Validator, error) {
+	locale := en.New()
+	trans, _ := ut.New(locale, locale).GetTranslator("en")
+
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	// Report fields by their JSON names, as clients know them.
+	validate.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	if err := entranslations.RegisterDefaultTranslations(validate, trans); err != nil {
+		return nil, fmt.Errorf("failed to register translations: %w", err)
+	}
+
+	v := &Validator{validate: validate, trans: trans}
+	err := v.RegisterRule("notblank", func(fl validator.FieldLevel) bool {
+		return strings.TrimSpace(fl.Field().String()) != ""
+	}, "{0} cannot be blank")
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// RegisterRule adds a custom tag usable in any DTO. The message may reference
+// the field name as {0}.
+func (v *Validator) RegisterRule(tag string, fn validator.Func, message string) error {
+	if err := v.validate.RegisterValidation(tag, fn); err != nil {
+		return fmt.Errorf("failed to register rule %q: %w", tag, err)
+	}
+	return v.validate.RegisterTranslation(tag, v.trans,
+		func(ut ut.Translator) error {
+			return ut.Add(tag, message, true)
+		},
+		func(ut ut.Translator, fe validator.FieldError) string {
+			msg, _ := ut.T(tag, fe.Field())
+			return msg
+		},
+	)
+}
+
+// Struct validates s and returns an *Error listing every invalid field.
+func (v *Validator) Struct(s any) error {
+	err := v.validate.Struct(s)
+	if err == nil {
+		return nil
+	}
+
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return fmt.Errorf("failed to validate input: %w", err)
+	}
+
+	fields := make([]FieldError, 0, len(verrs))
+	for _, fe := range verrs {
+		fields = append(fields, FieldError{
+			Field:   fe.Field(),
+			Message: fe.Translate(v.trans),
+		})
+	}
+	return &Error{Fields: fields}
+}
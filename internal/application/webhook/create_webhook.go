@@ -0,0 +1,58 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"usermanagement/internal/application/validation"
+	"usermanagement/internal/domain/user"
+	"usermanagement/internal/domain/webhook"
+)
+
+// CreateWebhookUseCase implements the register webhook use case.
+type CreateWebhookUseCase struct {
+	subs      webhook.SubscriptionRepository
+	ids       user.IDGenerator
+	validator *validation.Validator
+}
+
+// NewCreateWebhookUseCase creates a new instance.
+func NewCreateWebhookUseCase(subs webhook.SubscriptionRepository, ids user.IDGenerator, validator *validation.Validator) *CreateWebhookUseCase {
+	return &CreateWebhookUseCase{subs: subs, ids: ids, validator: validator}
+}
+
+// Execute registers a webhook for the calling admin with a fresh signing
+// secret, which is only ever returned here.
+func (uc *CreateWebhookUseCase) Execute(ctx context.Context, input CreateWebhookInput) (*CreatedWebhookOutput, error) {
+	caller, err := requireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.validator.Struct(input); err != nil {
+		return nil, err
+	}
+
+	id, err := uc.ids.NewID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate webhook id: %w", err)
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	s, err := webhook.New(id, caller.UserID, input.URL, input.Events, "whsec_"+hex.EncodeToString(key))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.subs.Save(ctx, s); err != nil {
+		return nil, fmt.Errorf("failed to save webhook: %w", err)
+	}
+
+	return &CreatedWebhookOutput{WebhookOutput: MapFromDomain(s), Secret: s.Secret()}, nil
+}
@@ -0,0 +1,45 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"usermanagement/internal/application/usecase"
+	"usermanagement/internal/domain/webhook"
+)
+
+// DeleteWebhookUseCase implements the delete webhook use case.
+type DeleteWebhookUseCase struct {
+	subs webhook.SubscriptionRepository
+}
+
+// NewDeleteWebhookUseCase creates a new instance.
+func NewDeleteWebhookUseCase(subs webhook.SubscriptionRepository) *DeleteWebhookUseCase {
+	return &DeleteWebhookUseCase{subs: subs}
+}
+
+// Execute deletes one of the caller's webhooks with its delivery log.
+// Pending deliveries are dropped.
+func (uc *DeleteWebhookUseCase) Execute(ctx context.Context, id uuid.UUID) error {
+	s, err := findOwned(ctx, uc.subs, id)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.subs.Delete(ctx, s.ID()); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	return nil
+}
+
+// Preview reports what Execute would delete, without deleting it.
+func (uc *DeleteWebhookUseCase) Preview(ctx context.Context, id uuid.UUID) ([]usecase.Change, error) {
+	s, err := findOwned(ctx, uc.subs, id)
+	if err != nil {
+		return nil, err
+	}
+	return []usecase.Change{{Action: "delete", Resource: "webhook", ID: s.ID().String()}}, nil
+}
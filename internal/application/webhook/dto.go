@@ -0,0 +1,80 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"usermanagement/internal/application/pagination"
+	"usermanagement/internal/domain/webhook"
+)
+
+// CreateWebhookInput represents data needed to register a webhook.
+type CreateWebhookInput struct {
+	URL    string   `json:"url" validate:"required,url,max=2000"`
+	Events []string `json:"events" validate:"required,min=1,dive,oneof=user.created user.updated user.deleted user.email_changed user.activation_requested"`
+}
+
+// ListDeliveriesInput selects a page of a webhook's delivery log.
+type ListDeliveriesInput struct {
+	WebhookID uuid.UUID
+	Page      pagination.Params
+}
+
+// WebhookOutput represents webhook data returned to clients. The signing
+// secret is only returned on creation.
+type WebhookOutput struct {
+	ID        uuid.UUID `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreatedWebhookOutput is returned once, when a webhook is registered.
+type CreatedWebhookOutput struct {
+	WebhookOutput
+	// Secret signs every payload sent to the webhook; see the
+	// X-Webhook-Signature header.
+	Secret string `json:"secret"`
+}
+
+// DeliveryOutput represents one entry of a webhook's delivery log.
+type DeliveryOutput struct {
+	ID             uuid.UUID       `json:"id"`
+	EventID        uuid.UUID       `json:"event_id"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	LastStatusCode int             `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+}
+
+// MapFromDomain converts domain entity to output DTO.
+func MapFromDomain(s *webhook.Subscription) WebhookOutput {
+	return WebhookOutput{
+		ID:        s.ID(),
+		URL:       s.URL(),
+		Events:    s.Events(),
+		CreatedAt: s.CreatedAt(),
+	}
+}
+
+// mapDelivery converts a delivery log entry to its output DTO.
+func mapDelivery(d *webhook.Delivery) DeliveryOutput {
+	return DeliveryOutput{
+		ID:             d.ID,
+		EventID:        d.EventID,
+		Event:          d.Topic,
+		Payload:        d.Payload,
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		CreatedAt:      d.CreatedAt,
+		DeliveredAt:    d.DeliveredAt,
+	}
+}
@@ -0,0 +1,46 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	"usermanagement/internal/application/pagination"
+	"usermanagement/internal/domain/webhook"
+)
+
+// ListDeliveriesUseCase implements the webhook delivery log use case.
+type ListDeliveriesUseCase struct {
+	subs       webhook.SubscriptionRepository
+	deliveries webhook.DeliveryRepository
+}
+
+// NewListDeliveriesUseCase creates a new instance.
+func NewListDeliveriesUseCase(subs webhook.SubscriptionRepository, deliveries webhook.DeliveryRepository) *ListDeliveriesUseCase {
+	return &ListDeliveriesUseCase{subs: subs, deliveries: deliveries}
+}
+
+// Execute returns a page of one of the caller's webhooks' deliveries,
+// newest first, with the outcome of their last attempt.
+func (uc *ListDeliveriesUseCase) Execute(ctx context.Context, input ListDeliveriesInput) (*pagination.Page[DeliveryOutput], error) {
+	s, err := findOwned(ctx, uc.subs, input.WebhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	params := input.Page.Normalize()
+	deliveries, err := uc.deliveries.FindBySubscription(ctx, s.ID(), params.Limit, params.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+
+	total, err := uc.deliveries.CountBySubscription(ctx, s.ID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to count webhook deliveries: %w", err)
+	}
+
+	outputs := make([]DeliveryOutput, len(deliveries))
+	for i, d := range deliveries {
+		outputs[i] = mapDelivery(d)
+	}
+	return pagination.NewPage(outputs, params, total), nil
+}
@@ -0,0 +1,44 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	"usermanagement/internal/application/pagination"
+	"usermanagement/internal/domain/webhook"
+)
+
+// ListWebhooksUseCase implements the list webhooks use case.
+type ListWebhooksUseCase struct {
+	subs webhook.SubscriptionRepository
+}
+
+// NewListWebhooksUseCase creates a new instance.
+func NewListWebhooksUseCase(subs webhook.SubscriptionRepository) *ListWebhooksUseCase {
+	return &ListWebhooksUseCase{subs: subs}
+}
+
+// Execute returns a page of the calling admin's webhooks, newest first.
+func (uc *ListWebhooksUseCase) Execute(ctx context.Context, params pagination.Params) (*pagination.Page[WebhookOutput], error) {
+	caller, err := requireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	params = params.Normalize()
+	subs, err := uc.subs.FindByOwner(ctx, caller.UserID, params.Limit, params.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+
+	total, err := uc.subs.CountByOwner(ctx, caller.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count webhooks: %w", err)
+	}
+
+	outputs := make([]WebhookOutput, len(subs))
+	for i, s := range subs {
+		outputs[i] = MapFromDomain(s)
+	}
+	return pagination.NewPage(outputs, params, total), nil
+}
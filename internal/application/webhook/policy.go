@@ -0,0 +1,44 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"usermanagement/internal/application/auth"
+	"usermanagement/internal/domain/webhook"
+)
+
+// requireAdmin returns the caller when they may manage webhooks. Events
+// carry every user's profile, so only admins may subscribe to them.
+func requireAdmin(ctx context.Context) (auth.Caller, error) {
+	caller, ok := auth.CallerFrom(ctx)
+	if !ok || !caller.IsAdmin() {
+		return auth.Caller{}, auth.ErrForbidden
+	}
+	return caller, nil
+}
+
+// findOwned loads one of the caller's webhooks. Other admins' webhooks are
+// reported as not found.
+func findOwned(ctx context.Context, subs webhook.SubscriptionRepository, id uuid.UUID) (*webhook.Subscription, error) {
+	caller, err := requireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := subs.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, webhook.ErrSubscriptionNotFound) {
+			return nil, webhook.ErrSubscriptionNotFound
+		}
+		return nil, fmt.Errorf("failed to find webhook: %w", err)
+	}
+
+	if s.OwnerID() != caller.UserID {
+		return nil, webhook.ErrSubscriptionNotFound
+	}
+	return s, nil
+}
@@ -0,0 +1,91 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"usermanagement/internal/application/validation"
+	"usermanagement/internal/domain/errcode"
+	"usermanagement/internal/infra/logger"
+)
+
+// codeByErrcode maps error codes to gRPC status codes, as statusByCode does
+// for HTTP.
+var codeByErrcode = map[errcode.Code]codes.Code{
+	errcode.UserNotFound:       codes.NotFound,
+	errcode.EmailConflict:      codes.AlreadyExists,
+	errcode.DataConflict:       codes.Aborted,
+	errcode.VersionConflict:    codes.Aborted,
+	errcode.ValidationFailed:   codes.InvalidArgument,
+	errcode.InvalidRequest:     codes.InvalidArgument,
+	errcode.Unavailable:        codes.Unavailable,
+	errcode.Unauthorized:       codes.Unauthenticated,
+	errcode.InvalidCredentials: codes.Unauthenticated,
+	errcode.Forbidden:          codes.PermissionDenied,
+	errcode.ContentRejected:    codes.InvalidArgument,
+	errcode.RateLimited:        codes.ResourceExhausted,
+	errcode.CaptchaFailed:      codes.InvalidArgument,
+}
+
+// errorDomain qualifies the errcode carried in ErrorInfo details.
+const errorDomain = "usermanagement"
+
+// newStatus builds a status error whose ErrorInfo reason is the stable error
+// code, so clients can branch on it as they do on the HTTP "code" field.
+func newStatus(code errcode.Code, message string) error {
+	st := status.New(codeByErrcode[code], message)
+	if withInfo, err := st.WithDetails(&errdetails.ErrorInfo{Reason: string(code), Domain: errorDomain}); err == nil {
+		st = withInfo
+	}
+	return st.Err()
+}
+
+// toStatus maps domain errors to gRPC status errors.
+func toStatus(err error, logger *logger.Logger) error {
+	var verr *validation.Error
+	if errors.As(err, &verr) {
+		violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(verr.Fields))
+		for _, f := range verr.Fields {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: f.Field, Description: f.Message})
+		}
+		st := status.New(codes.InvalidArgument, "validation failed")
+		if withFields, err := st.WithDetails(
+			&errdetails.ErrorInfo{Reason: string(errcode.ValidationFailed), Domain: errorDomain},
+			&errdetails.BadRequest{FieldViolations: violations},
+		); err == nil {
+			st = withFields
+		}
+		return st.Err()
+	}
+
+	// Context errors first: they arrive wrapped in repository errors.
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		logger.Warn("request deadline exceeded", zap.Error(err))
+		return status.Error(codes.DeadlineExceeded, "request timed out")
+	case errors.Is(err, context.Canceled):
+		logger.Debug("request canceled", zap.Error(err))
+		return status.Error(codes.Canceled, "request canceled")
+	}
+
+	code := errcode.Of(err)
+	grpcCode, ok := codeByErrcode[code]
+	if !ok {
+		logger.Error("unexpected error", zap.Error(err))
+		return status.Error(codes.Internal, "internal server error")
+	}
+
+	if grpcCode == codes.Unavailable {
+		logger.Warn("dependency unavailable", zap.Error(err))
+		return newStatus(code, "service temporarily unavailable")
+	}
+
+	var coded *errcode.Error
+	errors.As(err, &coded)
+	return newStatus(code, coded.Message)
+}
@@ -0,0 +1,68 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"usermanagement/internal/application/auth"
+	"usermanagement/internal/delivery/grpc/userpb"
+	"usermanagement/internal/domain/user"
+	"usermanagement/internal/infra/logger"
+)
+
+// adminMethods are reserved for admins, like their HTTP counterparts.
+// Creating users skips the signup abuse checks, so it is one of them.
+var adminMethods = map[string]bool{
+	userpb.UserService_CreateUser_FullMethodName: true,
+	userpb.UserService_DeleteUser_FullMethodName: true,
+}
+
+// AuthenticateInterceptor rejects calls without a valid bearer access token
+// in the "authorization" metadata, and calls to adminMethods by others. It
+// stores the caller in the context.
+func AuthenticateInterceptor(tokens auth.TokenIssuer, logger *logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get("authorization")
+		if len(values) == 0 {
+			return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+		}
+		scheme, token, ok := strings.Cut(values[0], " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+			return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+		}
+
+		caller, err := tokens.VerifyAccess(token)
+		if err != nil {
+			logger.Debug("rejected access token", zap.Error(err))
+			return nil, status.Error(codes.Unauthenticated, auth.ErrInvalidToken.Message)
+		}
+		if adminMethods[info.FullMethod] && caller.Role != user.RoleAdmin {
+			return nil, status.Error(codes.PermissionDenied, auth.ErrForbidden.Message)
+		}
+
+		return handler(auth.WithCaller(ctx, caller), req)
+	}
+}
+
+// LoggingInterceptor logs every call with its outcome and duration.
+func LoggingInterceptor(logger *logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		logger.Info("grpc request",
+			zap.String("method", info.FullMethod),
+			zap.String("code", status.Code(err).String()),
+			zap.Duration("duration", time.Since(start)),
+		)
+		return resp, err
+	}
+}
@@ -0,0 +1,20 @@
+package grpc
+
+import (
+	"google.golang.org/grpc"
+
+	"usermanagement/internal/application/auth"
+	"usermanagement/internal/delivery/grpc/userpb"
+	"usermanagement/internal/infra/logger"
+)
+
+// NewServer creates a gRPC server with the user service registered behind
+// logging and authentication.
+func NewServer(users *UserServer, tokens auth.TokenIssuer, logger *logger.Logger) *grpc.Server {
+	srv := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		LoggingInterceptor(logger),
+		AuthenticateInterceptor(tokens, logger),
+	))
+	userpb.RegisterUserServiceServer(srv, users)
+	return srv
+}
@@ -0,0 +1,152 @@
+// Package grpc exposes the user use cases over gRPC, alongside the HTTP API.
+package grpc
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"usermanagement/internal/application/pagination"
+	"usermanagement/internal/application/usecase"
+	app "usermanagement/internal/application/user"
+	"usermanagement/internal/delivery/grpc/userpb"
+	"usermanagement/internal/domain/errcode"
+	"usermanagement/internal/infra/logger"
+)
+
+// UserServer implements userpb.UserServiceServer on top of the same use
+// cases the HTTP UserHandler calls.
+type UserServer struct {
+	userpb.UnimplementedUserServiceServer
+
+	createUC usecase.UseCase[app.CreateUserInput, *app.UserOutput]
+	getUC    usecase.UseCase[uuid.UUID, *app.UserOutput]
+	listUC   usecase.UseCase[pagination.Params, *pagination.Page[app.UserOutput]]
+	updateUC usecase.UseCase[app.UpdateUserInput, *app.UserOutput]
+	deleteUC usecase.Command[app.DeleteUserInput]
+	logger   *logger.Logger
+}
+
+// NewUserServer creates a gRPC user service with injected use cases.
+func NewUserServer(
+	createUC usecase.UseCase[app.CreateUserInput, *app.UserOutput],
+	getUC usecase.UseCase[uuid.UUID, *app.UserOutput],
+	listUC usecase.UseCase[pagination.Params, *pagination.Page[app.UserOutput]],
+	updateUC usecase.UseCase[app.UpdateUserInput, *app.UserOutput],
+	deleteUC usecase.Command[app.DeleteUserInput],
+	logger *logger.Logger,
+) *UserServer {
+	return &UserServer{
+		createUC: createUC,
+		getUC:    getUC,
+		listUC:   listUC,
+		updateUC: updateUC,
+		deleteUC: deleteUC,
+		logger:   logger,
+	}
+}
+
+// CreateUser registers a new user.
+func (s *UserServer) CreateUser(ctx context.Context, req *userpb.CreateUserRequest) (*userpb.User, error) {
+	output, err := s.createUC.Execute(ctx, app.CreateUserInput{
+		Name:     req.GetName(),
+		Email:    req.GetEmail(),
+		Password: req.GetPassword(),
+	})
+	if err != nil {
+		return nil, toStatus(err, s.logger)
+	}
+	return toProto(output), nil
+}
+
+// GetUser returns one user by ID.
+func (s *UserServer) GetUser(ctx context.Context, req *userpb.GetUserRequest) (*userpb.User, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := s.getUC.Execute(ctx, id)
+	if err != nil {
+		return nil, toStatus(err, s.logger)
+	}
+	return toProto(output), nil
+}
+
+// ListUsers returns a page of users, by offset or by cursor.
+func (s *UserServer) ListUsers(ctx context.Context, req *userpb.ListUsersRequest) (*userpb.ListUsersResponse, error) {
+	params := pagination.Params{
+		Limit:  int(req.GetLimit()),
+		Offset: int(req.GetOffset()),
+		Cursor: req.GetCursor(),
+	}
+
+	page, err := s.listUC.Execute(ctx, params.Normalize())
+	if err != nil {
+		return nil, toStatus(err, s.logger)
+	}
+
+	users := make([]*userpb.User, 0, len(page.Items))
+	for i := range page.Items {
+		users = append(users, toProto(&page.Items[i]))
+	}
+	return &userpb.ListUsersResponse{
+		Users:      users,
+		Total:      page.Meta.Total,
+		HasMore:    page.Meta.HasMore,
+		NextCursor: page.Meta.NextCursor,
+	}, nil
+}
+
+// UpdateUser changes the fields set in the request.
+func (s *UserServer) UpdateUser(ctx context.Context, req *userpb.UpdateUserRequest) (*userpb.User, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := s.updateUC.Execute(ctx, app.UpdateUserInput{
+		ID:        id,
+		Name:      req.Name,
+		Email:     req.Email,
+		IfVersion: req.IfVersion,
+	})
+	if err != nil {
+		return nil, toStatus(err, s.logger)
+	}
+	return toProto(output), nil
+}
+
+// DeleteUser removes a user.
+func (s *UserServer) DeleteUser(ctx context.Context, req *userpb.DeleteUserRequest) (*userpb.DeleteUserResponse, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.deleteUC.Execute(ctx, app.DeleteUserInput{ID: id}); err != nil {
+		return nil, toStatus(err, s.logger)
+	}
+	return &userpb.DeleteUserResponse{}, nil
+}
+
+func parseID(raw string) (uuid.UUID, error) {
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.Nil, newStatus(errcode.InvalidRequest, "invalid user id format")
+	}
+	return id, nil
+}
+
+func toProto(u *app.UserOutput) *userpb.User {
+	return &userpb.User{
+		Id:        u.ID.String(),
+		Name:      u.Name,
+		Email:     u.Email,
+		Role:      u.Role,
+		CreatedAt: timestamppb.New(u.CreatedAt),
+		UpdatedAt: timestamppb.New(u.UpdatedAt),
+		Version:   u.Version,
+	}
+}
@@ -0,0 +1,5 @@
+// Package userpb holds the protobuf messages and service stubs of the gRPC
+// user API, generated from user.proto.
+package userpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative user.proto
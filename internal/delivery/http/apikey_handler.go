@@ -0,0 +1,86 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	app "usermanagement/internal/application/apikey"
+	"usermanagement/internal/application/pagination"
+	"usermanagement/internal/application/usecase"
+	"usermanagement/internal/domain/errcode"
+	"usermanagement/internal/infra/logger"
+)
+
+// APIKeyHandler handles HTTP requests for API keys.
+type APIKeyHandler struct {
+	createUC usecase.UseCase[app.CreateAPIKeyInput, *app.CreatedAPIKeyOutput]
+	listUC   usecase.UseCase[pagination.Params, *pagination.Page[app.APIKeyOutput]]
+	revokeUC usecase.Command[uuid.UUID]
+	ids      PublicIDs
+	logger   *logger.Logger
+}
+
+// NewAPIKeyHandler creates a new HTTP handler with injected use cases.
+func NewAPIKeyHandler(
+	createUC usecase.UseCase[app.CreateAPIKeyInput, *app.CreatedAPIKeyOutput],
+	listUC usecase.UseCase[pagination.Params, *pagination.Page[app.APIKeyOutput]],
+	revokeUC usecase.Command[uuid.UUID],
+	ids PublicIDs,
+	logger *logger.Logger,
+) *APIKeyHandler {
+	return &APIKeyHandler{
+		createUC: createUC,
+		listUC:   listUC,
+		revokeUC: revokeUC,
+		ids:      ids,
+		logger:   logger,
+	}
+}
+
+// Create handles POST /api-keys. The response holds the key, which is not
+// shown again.
+func (h *APIKeyHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input app.CreateAPIKeyInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, r, http.StatusBadRequest, errcode.InvalidRequest, "invalid request body")
+		return
+	}
+
+	output, err := h.createUC.Execute(r.Context(), input)
+	if err != nil {
+		handleDomainError(w, r, err, h.logger)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, output)
+}
+
+// List handles GET /api-keys?limit=&offset=.
+func (h *APIKeyHandler) List(w http.ResponseWriter, r *http.Request) {
+	page, err := h.listUC.Execute(r.Context(), parsePagination(r))
+	if err != nil {
+		handleDomainError(w, r, err, h.logger)
+		return
+	}
+
+	respondPage(w, page)
+}
+
+// Revoke handles DELETE /api-keys/{id}.
+func (h *APIKeyHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, errcode.InvalidRequest, "invalid API key id format")
+		return
+	}
+
+	if err := h.revokeUC.Execute(r.Context(), id); err != nil {
+		handleDomainError(w, r, err, h.logger)
+		return
+	}
+
+	respondDeleted(w, r, h.ids, h.logger)
+}
@@ -0,0 +1,166 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"usermanagement/internal/application/auth"
+	"usermanagement/internal/application/usecase"
+	app "usermanagement/internal/application/user"
+	"usermanagement/internal/domain/apikey"
+	"usermanagement/internal/domain/errcode"
+	"usermanagement/internal/domain/user"
+	"usermanagement/internal/infra/logger"
+)
+
+// AuthHandler handles HTTP requests for authentication.
+type AuthHandler struct {
+	loginUC   usecase.UseCase[app.LoginUserInput, *auth.Tokens]
+	refreshUC usecase.UseCase[app.RefreshTokenInput, *auth.Tokens]
+	logger    *logger.Logger
+}
+
+// NewAuthHandler creates a new HTTP handler with injected use cases.
+func NewAuthHandler(
+	loginUC usecase.UseCase[app.LoginUserInput, *auth.Tokens],
+	refreshUC usecase.UseCase[app.RefreshTokenInput, *auth.Tokens],
+	logger *logger.Logger,
+) *AuthHandler {
+	return &AuthHandler{
+		loginUC:   loginUC,
+		refreshUC: refreshUC,
+		logger:    logger,
+	}
+}
+
+// Login handles POST /auth/login.
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var input app.LoginUserInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, r, http.StatusBadRequest, errcode.InvalidRequest, "invalid request body")
+		return
+	}
+
+	tokens, err := h.loginUC.Execute(r.Context(), input)
+	if err != nil {
+		handleDomainError(w, r, err, h.logger)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, tokens)
+}
+
+// Refresh handles POST /auth/refresh.
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var input app.RefreshTokenInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, r, http.StatusBadRequest, errcode.InvalidRequest, "invalid request body")
+		return
+	}
+
+	tokens, err := h.refreshUC.Execute(r.Context(), input)
+	if err != nil {
+		handleDomainError(w, r, err, h.logger)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, tokens)
+}
+
+// AuthenticateMiddleware rejects requests without a valid bearer access
+// token or API key and stores the caller in the request context. API keys
+// are sent as "Authorization: ApiKey <key>".
+func AuthenticateMiddleware(tokens auth.TokenIssuer, apiKeys auth.APIKeyVerifier, logger *logger.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+			if ok && strings.EqualFold(scheme, "ApiKey") && token != "" {
+				caller, err := apiKeys.VerifyAPIKey(r.Context(), token)
+				if errors.Is(err, auth.ErrInvalidToken) {
+					logger.Debug("rejected API key", zap.Error(err))
+					respondError(w, r, http.StatusUnauthorized, errcode.Unauthorized, "invalid API key")
+					return
+				}
+				if err != nil {
+					handleDomainError(w, r, err, logger)
+					return
+				}
+				next.ServeHTTP(w, r.WithContext(auth.WithCaller(r.Context(), caller)))
+				return
+			}
+			if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				respondError(w, r, http.StatusUnauthorized, errcode.Unauthorized, "missing bearer token")
+				return
+			}
+
+			caller, err := tokens.VerifyAccess(token)
+			if err != nil {
+				logger.Debug("rejected access token", zap.Error(err))
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				respondError(w, r, http.StatusUnauthorized, errcode.Unauthorized, auth.ErrInvalidToken.Message)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithCaller(r.Context(), caller)))
+		})
+	}
+}
+
+// OptionalAuthenticateMiddleware stores the caller in the request context
+// when a valid bearer access token is present and lets anonymous requests
+// through. Routes behind it must treat the caller as optional.
+func OptionalAuthenticateMiddleware(tokens auth.TokenIssuer, apiKeys auth.APIKeyVerifier, logger *logger.Logger) func(next http.Handler) http.Handler {
+	authenticate := AuthenticateMiddleware(tokens, apiKeys, logger)
+	return func(next http.Handler) http.Handler {
+		withCaller := authenticate(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			// A token that is present but invalid is still rejected, so
+			// clients notice expiry instead of silently losing access.
+			withCaller.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireRole rejects authenticated callers whose role is not listed. It
+// guards routes coarsely; use cases still enforce their own policies.
+func RequireRole(roles ...user.Role) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			caller, ok := auth.CallerFrom(r.Context())
+			if !ok || !slices.Contains(roles, caller.Role) {
+				respondError(w, r, http.StatusForbidden, errcode.Forbidden, auth.ErrForbidden.Message)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireScope rejects callers using API keys without the scope for the
+// request: read for GET requests, write for the others. Callers with user
+// tokens pass; their role decides.
+func RequireScope(read, write apikey.Scope) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scope := write
+			if r.Method == http.MethodGet || r.Method == http.MethodHead {
+				scope = read
+			}
+			if caller, ok := auth.CallerFrom(r.Context()); ok && !caller.Allows(scope) {
+				respondError(w, r, http.StatusForbidden, errcode.Forbidden, "API key lacks the "+string(scope)+" scope")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"usermanagement/internal/domain/errcode"
+)
+
+// ClientProfileHeader selects a named serialization profile, so legacy
+// consumers get the field names and timestamps they were built against
+// without a copy of every DTO. Responses echo the profile they used.
+const ClientProfileHeader = "X-Client-Profile"
+
+// Field naming styles of a SerializationProfile.
+const (
+	FieldNamesSnake = "snake" // created_at
+	FieldNamesCamel = "camel" // createdAt
+)
+
+// Timestamp formats of a SerializationProfile.
+const (
+	TimestampsRFC3339 = "rfc3339"  // "2024-05-01T12:00:00Z"
+	TimestampsEpochMS = "epoch_ms" // 1714564800000
+)
+
+// SerializationProfile describes how a client expects JSON to look. The zero
+// value is the canonical form the handlers produce: snake_case field names
+// and RFC 3339 timestamps.
+type SerializationProfile struct {
+	FieldNames string `json:"field_names"`
+	Timestamps string `json:"timestamps"`
+}
+
+// canonical reports whether p needs no rewriting.
+func (p SerializationProfile) canonical() bool {
+	return (p.FieldNames == "" || p.FieldNames == FieldNamesSnake) &&
+		(p.Timestamps == "" || p.Timestamps == TimestampsRFC3339)
+}
+
+// ParseClientProfiles decodes a JSON object of serialization profiles by
+// name, e.g. {"legacy": {"field_names": "camel", "timestamps": "epoch_ms"}}.
+func ParseClientProfiles(raw string) (map[string]SerializationProfile, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	var profiles map[string]SerializationProfile
+	if err := json.Unmarshal([]byte(raw), &profiles); err != nil {
+		return nil, fmt.Errorf("invalid client profiles: %w", err)
+	}
+	for name, p := range profiles {
+		switch p.FieldNames {
+		case "", FieldNamesSnake, FieldNamesCamel:
+		default:
+			return nil, fmt.Errorf("invalid client profile %q: unknown field_names %q", name, p.FieldNames)
+		}
+		switch p.Timestamps {
+		case "", TimestampsRFC3339, TimestampsEpochMS:
+		default:
+			return nil, fmt.Errorf("invalid client profile %q: unknown timestamps %q", name, p.Timestamps)
+		}
+	}
+	return profiles, nil
+}
+
+// ClientProfiles rewrites JSON requests and responses for clients naming one
+// of profiles in ClientProfileHeader; other clients get fallback, the
+// default of the API version the middleware is mounted on. Request bodies
+// are translated back to the canonical form before handlers decode them, so
+// "*At" fields and epoch timestamps are accepted from those clients too.
+func ClientProfiles(profiles map[string]SerializationProfile, fallback SerializationProfile) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", ClientProfileHeader)
+
+			profile := fallback
+			name := r.Header.Get(ClientProfileHeader)
+			if name != "" {
+				p, ok := profiles[name]
+				if !ok {
+					respondError(w, r, http.StatusBadRequest, errcode.InvalidRequest, "unknown client profile")
+					return
+				}
+				profile = p
+				w.Header().Set(ClientProfileHeader, name)
+			}
+			if profile.canonical() {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if r.Body != nil && isJSON(r.Header) {
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					respondError(w, r, http.StatusBadRequest, errcode.InvalidRequest, "invalid request body")
+					return
+				}
+				// Bodies that are not JSON reach the handler unchanged and
+				// fail its decoding as usual.
+				if payload, ok := decodeJSON(body); ok {
+					var buf bytes.Buffer
+					newJSONEncoder(&buf).Encode(profile.fromClient(payload))
+					body = buf.Bytes()
+				}
+				r.Body = io.NopCloser(bytes.NewReader(body))
+				r.ContentLength = int64(len(body))
+			}
+
+			bw := &bufferedWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(bw, r)
+
+			body := bw.body.Bytes()
+			payload, ok := decodeJSON(body)
+			if !isJSON(w.Header()) || !ok {
+				w.WriteHeader(bw.status)
+				w.Write(body)
+				return
+			}
+
+			w.Header().Del("Content-Length")
+			w.WriteHeader(bw.status)
+			newJSONEncoder(w).Encode(profile.toClient(payload))
+		})
+	}
+}
+
+// decodeJSON decodes body keeping numbers exact.
+func decodeJSON(body []byte) (any, bool) {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, false
+	}
+	var payload any
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&payload); err != nil {
+		return nil, false
+	}
+	return payload, true
+}
+
+// toClient rewrites a canonical JSON value into p's form.
+func (p SerializationProfile) toClient(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for key, val := range t {
+			if s, ok := val.(string); ok && p.Timestamps == TimestampsEpochMS && strings.HasSuffix(key, "_at") {
+				if ts, err := time.Parse(time.RFC3339Nano, s); err == nil {
+					val = ts.UnixMilli()
+				}
+			}
+			if p.FieldNames == FieldNamesCamel {
+				key = snakeToCamel(key)
+			}
+			out[key] = p.toClient(val)
+		}
+		return out
+	case []any:
+		for i, item := range t {
+			t[i] = p.toClient(item)
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+// fromClient rewrites a JSON value in p's form into the canonical one.
+func (p SerializationProfile) fromClient(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for key, val := range t {
+			if p.FieldNames == FieldNamesCamel {
+				key = camelToSnake(key)
+			}
+			if n, ok := val.(json.Number); ok && p.Timestamps == TimestampsEpochMS && strings.HasSuffix(key, "_at") {
+				if ms, err := strconv.ParseInt(n.String(), 10, 64); err == nil {
+					val = time.UnixMilli(ms).UTC().Format(time.RFC3339Nano)
+				}
+			}
+			out[key] = p.fromClient(val)
+		}
+		return out
+	case []any:
+		for i, item := range t {
+			t[i] = p.fromClient(item)
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+// snakeToCamel turns "created_at" into "createdAt".
+func snakeToCamel(s string) string {
+	parts := strings.Split(s, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// camelToSnake turns "createdAt" into "created_at". Runs of capitals count
+// as one word, so "postID" becomes "post_id".
+func camelToSnake(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			prevLower := i > 0 && !unicode.IsUpper(runes[i-1]) && runes[i-1] != '_'
+			acronymEnd := i > 0 && unicode.IsUpper(runes[i-1]) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || acronymEnd {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,147 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	app "usermanagement/internal/application/comment"
+	"usermanagement/internal/application/pagination"
+	"usermanagement/internal/application/usecase"
+	"usermanagement/internal/domain/errcode"
+	"usermanagement/internal/infra/logger"
+)
+
+// CommentHandler handles HTTP requests for comments on posts.
+type CommentHandler struct {
+	createUC   usecase.UseCase[app.CreateCommentInput, *app.CommentOutput]
+	listUC     usecase.UseCase[app.ListCommentsInput, *pagination.Page[app.CommentOutput]]
+	moderateUC usecase.UseCase[app.ModerateCommentInput, *app.CommentOutput]
+	deleteUC   usecase.Command[app.DeleteCommentInput]
+	ids        PublicIDs
+	logger     *logger.Logger
+}
+
+// NewCommentHandler creates a new HTTP handler with injected use cases.
+func NewCommentHandler(
+	createUC usecase.UseCase[app.CreateCommentInput, *app.CommentOutput],
+	listUC usecase.UseCase[app.ListCommentsInput, *pagination.Page[app.CommentOutput]],
+	moderateUC usecase.UseCase[app.ModerateCommentInput, *app.CommentOutput],
+	deleteUC usecase.Command[app.DeleteCommentInput],
+	ids PublicIDs,
+	logger *logger.Logger,
+) *CommentHandler {
+	return &CommentHandler{
+		createUC:   createUC,
+		listUC:     listUC,
+		moderateUC: moderateUC,
+		deleteUC:   deleteUC,
+		ids:        ids,
+		logger:     logger,
+	}
+}
+
+// Create handles POST /posts/{id}/comments.
+func (h *CommentHandler) Create(w http.ResponseWriter, r *http.Request) {
+	postID, ok := h.parsePostID(w, r)
+	if !ok {
+		return
+	}
+
+	var input app.CreateCommentInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, r, http.StatusBadRequest, errcode.InvalidRequest, "invalid request body")
+		return
+	}
+	input.PostID = postID
+
+	output, err := h.createUC.Execute(r.Context(), input)
+	if err != nil {
+		handleDomainError(w, r, err, h.logger)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, h.ids.comment(output))
+}
+
+// List handles GET /posts/{id}/comments?status=&limit=&offset=.
+func (h *CommentHandler) List(w http.ResponseWriter, r *http.Request) {
+	postID, ok := h.parsePostID(w, r)
+	if !ok {
+		return
+	}
+
+	page, err := h.listUC.Execute(r.Context(), app.ListCommentsInput{
+		PostID: postID,
+		Status: r.URL.Query().Get("status"),
+		Page:   parsePagination(r),
+	})
+	if err != nil {
+		handleDomainError(w, r, err, h.logger)
+		return
+	}
+
+	respondPage(w, mapPage(page, h.ids.comment))
+}
+
+// Moderate handles PUT /posts/{id}/comments/{commentID}/status.
+func (h *CommentHandler) Moderate(w http.ResponseWriter, r *http.Request) {
+	postID, ok := h.parsePostID(w, r)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "commentID"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, errcode.InvalidRequest, "invalid comment id format")
+		return
+	}
+
+	var input app.ModerateCommentInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, r, http.StatusBadRequest, errcode.InvalidRequest, "invalid request body")
+		return
+	}
+	input.PostID = postID
+	input.ID = id
+
+	output, err := h.moderateUC.Execute(r.Context(), input)
+	if err != nil {
+		handleDomainError(w, r, err, h.logger)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, h.ids.comment(output))
+}
+
+// Delete handles DELETE /posts/{id}/comments/{commentID}.
+func (h *CommentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	postID, ok := h.parsePostID(w, r)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "commentID"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, errcode.InvalidRequest, "invalid comment id format")
+		return
+	}
+
+	if err := h.deleteUC.Execute(r.Context(), app.DeleteCommentInput{PostID: postID, ID: id}); err != nil {
+		handleDomainError(w, r, err, h.logger)
+		return
+	}
+
+	respondDeleted(w, r, h.ids, h.logger)
+}
+
+// parsePostID reads the {id} URL parameter, responding with 400 when it is
+// not a post ID.
+func (h *CommentHandler) parsePostID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := parseID(h.ids.Posts, chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, errcode.InvalidRequest, "invalid post id format")
+		return uuid.Nil, false
+	}
+	return id, true
+}
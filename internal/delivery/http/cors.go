@@ -0,0 +1,63 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+)
+
+// OriginPolicy holds the origins allowed to make credentialed cross-origin
+// requests. It can be replaced at runtime when configuration reloads.
+type OriginPolicy struct {
+	origins atomic.Pointer[map[string]bool]
+}
+
+// NewOriginPolicy creates a policy allowing exactly the given origins.
+func NewOriginPolicy(origins []string) (*OriginPolicy, error) {
+	p := &OriginPolicy{}
+	if err := p.Set(origins); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Set validates origins and atomically replaces the allowed set. On error the
+// previous set stays in effect.
+func (p *OriginPolicy) Set(origins []string) error {
+	allowed := make(map[string]bool, len(origins))
+	for _, origin := range origins {
+		normalized, err := normalizeOrigin(origin)
+		if err != nil {
+			return err
+		}
+		allowed[normalized] = true
+	}
+	p.origins.Store(&allowed)
+	return nil
+}
+
+// Allowed reports whether origin may make cross-origin requests.
+func (p *OriginPolicy) Allowed(_ *http.Request, origin string) bool {
+	return (*p.origins.Load())[strings.ToLower(origin)]
+}
+
+// normalizeOrigin accepts only bare scheme://host[:port] origins. Wildcards
+// are rejected because they cannot be combined with credentials.
+func normalizeOrigin(origin string) (string, error) {
+	u, err := url.Parse(origin)
+	if err != nil {
+		return "", fmt.Errorf("invalid CORS origin %q: %w", origin, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("invalid CORS origin %q: scheme must be http or https", origin)
+	}
+	if u.Host == "" || strings.Contains(u.Host, "*") {
+		return "", fmt.Errorf("invalid CORS origin %q: host must be explicit", origin)
+	}
+	if u.User != nil || (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf("invalid CORS origin %q: must be scheme://host[:port] only", origin)
+	}
+	return strings.ToLower(u.Scheme + "://" + u.Host), nil
+}
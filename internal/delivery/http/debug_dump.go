@@ -0,0 +1,175 @@
+package http
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+
+	"usermanagement/internal/infra/logger"
+)
+
+// DebugDumpHeader enables body dumping for a single request. Its value is
+// "<unix expiry>.<hex HMAC-SHA256 of the expiry>" signed with the configured
+// secret, so only operators holding the secret can turn it on.
+const DebugDumpHeader = "X-Debug-Dump"
+
+// maxDumpBytes bounds how much of each body is logged.
+const maxDumpBytes = 64 << 10
+
+const redacted = "[REDACTED]"
+
+var sensitiveHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "X-Api-Key", DebugDumpHeader}
+
+var sensitiveFields = map[string]bool{
+	"password":      true,
+	"token":         true,
+	"access_token":  true,
+	"refresh_token": true,
+	"secret":        true,
+	"api_key":       true,
+	// the plaintext of a newly created API key
+	"key":           true,
+	"captcha_token": true,
+}
+
+// DebugDumpOptions configures DebugDumpMiddleware.
+type DebugDumpOptions struct {
+	// Global dumps every request. Never enable it in production.
+	Global bool
+	// Secret verifies per-request DebugDumpHeader signatures. Empty disables
+	// per-request dumps.
+	Secret string
+}
+
+// DebugDumpMiddleware logs full, sanitized request and response bodies with
+// timing for troubleshooting integrations.
+func DebugDumpMiddleware(opts DebugDumpOptions, logger *logger.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !opts.Global && !validDumpSignature(opts.Secret, r.Header.Get(DebugDumpHeader)) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			reqBody, _ := io.ReadAll(io.LimitReader(r.Body, maxDumpBytes))
+			r.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(reqBody), r.Body), r.Body}
+
+			start := time.Now()
+			dw := &dumpWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(dw, r)
+
+			logger.Info("debug dump",
+				zap.String("request_id", middleware.GetReqID(r.Context())),
+				zap.String("method", r.Method),
+				zap.String("url", r.URL.String()),
+				zap.Any("request_headers", sanitizeHeaders(r.Header)),
+				zap.String("request_body", sanitizeBody(reqBody)),
+				zap.Int("status", dw.status),
+				zap.Any("response_headers", sanitizeHeaders(w.Header())),
+				zap.String("response_body", sanitizeBody(dw.body.Bytes())),
+				zap.Duration("duration", time.Since(start)),
+			)
+		})
+	}
+}
+
+// SignDebugDump returns a DebugDumpHeader value valid until expiry.
+func SignDebugDump(secret string, expiry time.Time) string {
+	ts := strconv.FormatInt(expiry.Unix(), 10)
+	return ts + "." + dumpSignature(secret, ts)
+}
+
+func validDumpSignature(secret, value string) bool {
+	if secret == "" || value == "" {
+		return false
+	}
+	ts, sig, ok := strings.Cut(value, ".")
+	if !ok {
+		return false
+	}
+	expiry, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || time.Now().Unix() > expiry {
+		return false
+	}
+	return hmac.Equal([]byte(sig), []byte(dumpSignature(secret, ts)))
+}
+
+func dumpSignature(secret, ts string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func sanitizeHeaders(h http.Header) http.Header {
+	clean := h.Clone()
+	for _, name := range sensitiveHeaders {
+		if clean.Get(name) != "" {
+			clean.Set(name, redacted)
+		}
+	}
+	return clean
+}
+
+// sanitizeBody redacts sensitive fields in JSON bodies. Non-JSON bodies are
+// logged as-is.
+func sanitizeBody(body []byte) string {
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return string(body)
+	}
+	clean, _ := json.Marshal(redactJSON(v))
+	return string(clean)
+}
+
+func redactJSON(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, val := range t {
+			if sensitiveFields[strings.ToLower(k)] {
+				t[k] = redacted
+				continue
+			}
+			t[k] = redactJSON(val)
+		}
+	case []any:
+		for i, val := range t {
+			t[i] = redactJSON(val)
+		}
+	}
+	return v
+}
+
+// dumpWriter copies up to maxDumpBytes of the response while writing it through.
+type dumpWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (dw *dumpWriter) WriteHeader(code int) {
+	dw.status = code
+	dw.ResponseWriter.WriteHeader(code)
+}
+
+func (dw *dumpWriter) Write(b []byte) (int, error) {
+	if room := maxDumpBytes - dw.body.Len(); room > 0 {
+		if len(b) < room {
+			room = len(b)
+		}
+		dw.body.Write(b[:room])
+	}
+	return dw.ResponseWriter.Write(b)
+}
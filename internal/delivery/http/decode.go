@@ -0,0 +1,101 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+
+	"usermanagement/internal/application/validation"
+	"usermanagement/internal/domain/errcode"
+)
+
+// maxBodyBytes bounds the request bodies the API reads. The largest
+// legitimate body, a bulk creation of MaxBulkUsers users, is far smaller.
+const maxBodyBytes = 1 << 20
+
+// LimitBody refuses to read more than maxBodyBytes of any request body, so
+// no caller can make the server buffer an unbounded body. Reads past the
+// limit fail with *http.MaxBytesError.
+func LimitBody(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Body != nil {
+			r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// decodeStrict decodes a JSON object into v, a pointer to a DTO, refusing
+// members the DTO has no field for. All unknown members are reported at
+// once, as a *validation.Error naming each of them. Bodies over
+// maxBodyBytes fail with *http.MaxBytesError.
+func decodeStrict(body io.Reader, v any) error {
+	data, err := io.ReadAll(io.LimitReader(body, maxBodyBytes+1))
+	if err != nil {
+		return err
+	}
+	if len(data) > maxBodyBytes {
+		return &http.MaxBytesError{Limit: maxBodyBytes}
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+	known := jsonFields(reflect.TypeOf(v).Elem())
+	var fields []validation.FieldError
+	for name := range members {
+		if !known[name] {
+			fields = append(fields, validation.FieldError{Field: name, Message: name + " is not a known field"})
+		}
+	}
+	if len(fields) > 0 {
+		slices.SortFunc(fields, func(a, b validation.FieldError) int { return strings.Compare(a.Field, b.Field) })
+		return &validation.Error{Fields: fields}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+// jsonFields returns the JSON member names struct type t decodes.
+func jsonFields(t reflect.Type) map[string]bool {
+	names := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = f.Name
+		}
+		names[name] = true
+	}
+	return names
+}
+
+// respondBodyError answers a request whose body could not be read or
+// decodeStrict refused.
+func respondBodyError(w http.ResponseWriter, r *http.Request, err error) {
+	var verr *validation.Error
+	if errors.As(err, &verr) {
+		respondValidationError(w, r, verr)
+		return
+	}
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		respondError(w, r, http.StatusRequestEntityTooLarge, errcode.InvalidRequest, "request body too large")
+		return
+	}
+	respondError(w, r, http.StatusBadRequest, errcode.InvalidRequest, "invalid request body")
+}
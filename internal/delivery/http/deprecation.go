@@ -0,0 +1,89 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+
+	"usermanagement/internal/infra/logger"
+)
+
+var deprecatedRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "http_deprecated_requests_total",
+	Help: "Number of requests served by deprecated routes.",
+}, []string{"method", "route"})
+
+// Deprecation describes a route that is scheduled for retirement.
+type Deprecation struct {
+	// Since is when the route was deprecated.
+	Since time.Time `json:"since"`
+	// Sunset is when the route stops being served. Zero means not yet scheduled.
+	Sunset time.Time `json:"sunset"`
+	// Successor is the URL of the replacement route, if any.
+	Successor string `json:"successor,omitempty"`
+}
+
+// DeprecationRegistry keeps track of deprecated routes and marks their responses.
+type DeprecationRegistry struct {
+	mu     sync.RWMutex
+	routes map[string]Deprecation
+	logger *logger.Logger
+}
+
+// NewDeprecationRegistry creates an empty registry.
+func NewDeprecationRegistry(logger *logger.Logger) *DeprecationRegistry {
+	return &DeprecationRegistry{
+		routes: make(map[string]Deprecation),
+		logger: logger,
+	}
+}
+
+// Deprecate registers a route as deprecated and returns middleware that
+// emits the Deprecation, Sunset and Link headers for it.
+// Use it with chi's With, e.g. r.With(reg.Deprecate("GET", "/users/{id}", dep)).Get(...).
+func (d *DeprecationRegistry) Deprecate(method, route string, dep Deprecation) func(next http.Handler) http.Handler {
+	key := method + " " + route
+
+	d.mu.Lock()
+	d.routes[key] = dep
+	d.mu.Unlock()
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h := w.Header()
+			h.Set("Deprecation", fmt.Sprintf("@%d", dep.Since.Unix()))
+			if !dep.Sunset.IsZero() {
+				h.Set("Sunset", dep.Sunset.UTC().Format(http.TimeFormat))
+			}
+			if dep.Successor != "" {
+				h.Add("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", dep.Successor))
+			}
+
+			deprecatedRequests.WithLabelValues(method, route).Inc()
+			d.logger.Warn("deprecated route called",
+				zap.String("method", method),
+				zap.String("route", route),
+				zap.String("user_agent", r.UserAgent()),
+			)
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Routes returns a copy of all registered deprecations keyed by "METHOD route".
+func (d *DeprecationRegistry) Routes() map[string]Deprecation {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	routes := make(map[string]Deprecation, len(d.routes))
+	for k, v := range d.routes {
+		routes[k] = v
+	}
+	return routes
+}
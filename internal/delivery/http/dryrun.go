@@ -0,0 +1,53 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"usermanagement/internal/application/usecase"
+	"usermanagement/internal/domain/errcode"
+	"usermanagement/internal/infra/logger"
+)
+
+// DryRun implements the ?dry_run=true convention: destructive requests are
+// checked and answered with what they would change, without committing it.
+// Only DELETE routes support it; elsewhere the parameter is refused rather
+// than silently ignored, so an operator never commits by mistake.
+func DryRun(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if !query.Has("dry_run") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		dryRun, err := strconv.ParseBool(query.Get("dry_run"))
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, errcode.InvalidRequest, "invalid dry_run value")
+			return
+		}
+		if dryRun && r.Method != http.MethodDelete {
+			respondError(w, r, http.StatusBadRequest, errcode.InvalidRequest, "dry_run is only supported by DELETE requests")
+			return
+		}
+		if dryRun {
+			r = r.WithContext(usecase.WithDryRun(r.Context()))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// respondDeleted answers a successful delete: 204, or 200 with the changes
+// a dry run would have made.
+func respondDeleted(w http.ResponseWriter, r *http.Request, ids PublicIDs, logger *logger.Logger) {
+	changes, dryRun := usecase.DryRunChanges(r.Context())
+	if !dryRun {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	logger.Info("dry run", zap.String("path", r.URL.Path), zap.Any("changes", changes))
+	respondJSON(w, http.StatusOK, map[string]any{"dry_run": true, "changes": ids.changes(changes)})
+}
@@ -0,0 +1,114 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+
+	"usermanagement/internal/domain/errcode"
+)
+
+// EnvelopeHeader asks for responses wrapped in a uniform envelope,
+// {"data": ..., "meta": ..., "errors": ...}, so clients such as mobile apps
+// can parse every endpoint the same way. Its value is the envelope version;
+// responses echo the version they used.
+const EnvelopeHeader = "X-Response-Envelope"
+
+// envelopeVersions lists the envelope versions this server can produce.
+var envelopeVersions = map[string]bool{"v1": true}
+
+// envelope is the v1 response shape. Absent parts are null.
+type envelope struct {
+	Data   json.RawMessage `json:"data"`
+	Meta   json.RawMessage `json:"meta"`
+	Errors []envelopeError `json:"errors"`
+}
+
+type envelopeError struct {
+	Code    string          `json:"code"`
+	Message string          `json:"message"`
+	Fields  json.RawMessage `json:"fields,omitempty"`
+}
+
+// ResponseEnvelope wraps JSON responses in the envelope when the client asks
+// for one with EnvelopeHeader. It rewrites the buffered response after the
+// handler finishes, so handlers keep writing their plain payloads.
+func ResponseEnvelope(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", EnvelopeHeader)
+
+		version := r.Header.Get(EnvelopeHeader)
+		if version == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !envelopeVersions[version] {
+			respondError(w, r, http.StatusBadRequest, errcode.InvalidRequest, "unsupported response envelope version")
+			return
+		}
+
+		bw := &bufferedWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(bw, r)
+
+		body := bw.body.Bytes()
+		if !isJSON(w.Header()) || len(body) == 0 {
+			w.WriteHeader(bw.status)
+			w.Write(body)
+			return
+		}
+
+		w.Header().Del("Content-Length")
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(EnvelopeHeader, version)
+		w.WriteHeader(bw.status)
+		newJSONEncoder(w).Encode(wrapEnvelope(bw.status, body))
+	})
+}
+
+// wrapEnvelope builds the envelope for a JSON body. Problem bodies become
+// the errors list; list pages split into data and meta.
+func wrapEnvelope(status int, body []byte) envelope {
+	body = bytes.TrimSpace(body)
+
+	if status >= http.StatusBadRequest {
+		var e struct {
+			Detail string          `json:"detail"`
+			Code   string          `json:"code"`
+			Fields json.RawMessage `json:"fields"`
+		}
+		if err := json.Unmarshal(body, &e); err == nil && e.Code != "" {
+			return envelope{Errors: []envelopeError{{Code: e.Code, Message: e.Detail, Fields: e.Fields}}}
+		}
+	}
+
+	var page map[string]json.RawMessage
+	if err := json.Unmarshal(body, &page); err == nil && len(page) == 2 && page["items"] != nil && page["meta"] != nil {
+		return envelope{Data: page["items"], Meta: page["meta"]}
+	}
+
+	return envelope{Data: body}
+}
+
+// bufferedWriter holds back the status and body so middleware can rewrite
+// the response once the handler is done. Headers go straight through.
+type bufferedWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (bw *bufferedWriter) WriteHeader(code int) {
+	bw.status = code
+}
+
+func (bw *bufferedWriter) Write(b []byte) (int, error) {
+	return bw.body.Write(b)
+}
+
+// isJSON reports whether the response being written is JSON, problem
+// details included.
+func isJSON(h http.Header) bool {
+	mediaType, _, _ := mime.ParseMediaType(h.Get("Content-Type"))
+	return mediaType == "application/json" || mediaType == ProblemContentType
+}
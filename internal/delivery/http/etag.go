@@ -0,0 +1,65 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"usermanagement/internal/domain/errcode"
+)
+
+// errInvalidIfMatch reports an If-Match header that names no user version.
+var errInvalidIfMatch = errors.New("If-Match must be * or a single version ETag")
+
+// setETag tags a response with the version of the user it carries, for
+// clients to send back in If-Match or If-None-Match.
+func setETag(w http.ResponseWriter, version int64) {
+	w.Header().Set("ETag", etag(version))
+}
+
+func etag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// parseIfMatch returns the version an If-Match header requires, or nil when
+// the header is absent or "*", which any stored user matches.
+func parseIfMatch(r *http.Request) (*int64, error) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" || value == "*" {
+		return nil, nil
+	}
+	unquoted, ok := strings.CutPrefix(value, `"`)
+	if !ok {
+		return nil, errInvalidIfMatch
+	}
+	unquoted, ok = strings.CutSuffix(unquoted, `"`)
+	if !ok {
+		return nil, errInvalidIfMatch
+	}
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version < 1 {
+		return nil, errInvalidIfMatch
+	}
+	return &version, nil
+}
+
+// etagMatches reports whether an If-None-Match header names version. The
+// comparison is weak, as RFC 9110 asks for If-None-Match, so a W/ prefix
+// added by a proxy still matches.
+func etagMatches(header string, version int64) bool {
+	want := etag(version)
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == want {
+			return true
+		}
+	}
+	return false
+}
+
+// respondStaleVersion answers a request whose If-Match names a version the
+// user has moved past.
+func respondStaleVersion(w http.ResponseWriter, r *http.Request) {
+	respondError(w, r, http.StatusPreconditionFailed, errcode.VersionConflict, "user was modified since the version in If-Match")
+}
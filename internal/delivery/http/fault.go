@@ -0,0 +1,105 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"path"
+	"time"
+
+	"go.uber.org/zap"
+
+	"usermanagement/internal/domain/errcode"
+	"usermanagement/internal/infra/logger"
+)
+
+// FaultRule describes faults to inject into requests matching Method and Path.
+// It exists for resilience testing of clients and must never be enabled in
+// production.
+type FaultRule struct {
+	// Method matches the request method; empty matches any method.
+	Method string `json:"method"`
+	// Path is a path.Match pattern, e.g. "/api/v1/users/*".
+	Path string `json:"path"`
+	// LatencyMS delays matching requests by this many milliseconds.
+	LatencyMS int `json:"latency_ms"`
+	// ErrorRate is the fraction (0..1) of matching requests answered with ErrorStatus.
+	ErrorRate float64 `json:"error_rate"`
+	// ErrorStatus is the injected status code; defaults to 503.
+	ErrorStatus int `json:"error_status"`
+	// DropRate is the fraction (0..1) of matching requests whose connection is dropped.
+	DropRate float64 `json:"drop_rate"`
+}
+
+// ParseFaultRules decodes a JSON array of fault rules.
+func ParseFaultRules(raw string) ([]FaultRule, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	var rules []FaultRule
+	if err := json.Unmarshal([]byte(raw), &rules); err != nil {
+		return nil, fmt.Errorf("invalid fault rules: %w", err)
+	}
+	for i, rule := range rules {
+		if _, err := path.Match(rule.Path, "/"); err != nil {
+			return nil, fmt.Errorf("invalid fault rule %d path %q: %w", i, rule.Path, err)
+		}
+		if rule.ErrorStatus == 0 {
+			rules[i].ErrorStatus = http.StatusServiceUnavailable
+		}
+	}
+	return rules, nil
+}
+
+// FaultInjectionMiddleware injects latency, errors and dropped connections
+// according to the first rule matching each request.
+func FaultInjectionMiddleware(rules []FaultRule, logger *logger.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rule, ok := matchFaultRule(rules, r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if rule.LatencyMS > 0 {
+				select {
+				case <-time.After(time.Duration(rule.LatencyMS) * time.Millisecond):
+				case <-r.Context().Done():
+					return
+				}
+			}
+
+			if rule.DropRate > 0 && rand.Float64() < rule.DropRate {
+				logger.Debug("fault injection: dropping connection", zap.String("path", r.URL.Path))
+				// Aborts the response and closes the connection without a reply.
+				panic(http.ErrAbortHandler)
+			}
+
+			if rule.ErrorRate > 0 && rand.Float64() < rule.ErrorRate {
+				logger.Debug("fault injection: injecting error",
+					zap.String("path", r.URL.Path),
+					zap.Int("status", rule.ErrorStatus),
+				)
+				respondError(w, r, rule.ErrorStatus, errcode.InjectedFault, "injected fault")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func matchFaultRule(rules []FaultRule, r *http.Request) (FaultRule, bool) {
+	for _, rule := range rules {
+		if rule.Method != "" && rule.Method != r.Method {
+			continue
+		}
+		if ok, _ := path.Match(rule.Path, r.URL.Path); ok {
+			return rule, true
+		}
+	}
+	return FaultRule{}, false
+}
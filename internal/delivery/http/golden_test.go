@@ -0,0 +1,344 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"usermanagement/internal/application/apikey"
+	"usermanagement/internal/application/comment"
+	"usermanagement/internal/application/post"
+	"usermanagement/internal/application/usecase"
+	app "usermanagement/internal/application/user"
+	"usermanagement/internal/application/validation"
+	"usermanagement/internal/application/webhook"
+	deliveryhttp "usermanagement/internal/delivery/http"
+	"usermanagement/internal/domain/user"
+	"usermanagement/internal/infra/auth"
+	"usermanagement/internal/infra/captcha"
+	"usermanagement/internal/infra/deadline"
+	"usermanagement/internal/infra/logger"
+	"usermanagement/internal/infra/moderation"
+	"usermanagement/internal/infra/persistence/memory"
+	"usermanagement/internal/infra/publicid"
+	"usermanagement/internal/infra/ratelimit"
+)
+
+// update rewrites the golden files from the responses instead of comparing:
+//
+//	go test ./internal/delivery/http -run TestGolden -update
+var update = flag.Bool("update", false, "rewrite golden files from the current responses")
+
+// adminID is the admin every scenario starts with.
+var adminID = uuid.MustParse("00000000-0000-0000-0000-0000000000ad")
+
+// step is one request of a scenario. Steps run in order against the same
+// server, so later steps see what earlier ones created.
+type step struct {
+	method string
+	path   string
+	// as is "admin" to send the seeded admin's access token, or empty for
+	// an anonymous request.
+	as     string
+	header map[string]string
+	body   string
+}
+
+// scenarios are recorded to testdata/golden/<name>.golden. IDs are handed
+// out in sequence from 00000000-0000-0000-0000-000000000001 within each
+// scenario.
+var scenarios = map[string][]step{
+	"users": {
+		{method: "POST", path: "/api/v1/users", body: `{"name":"Ada","email":"ada@example.com","password":"correct-horse"}`},
+		{method: "POST", path: "/api/v1/users", as: "admin", body: `{"name":"Ada","email":"ada@example.com","password":"correct-horse"}`},
+		{method: "POST", path: "/api/v1/users", as: "admin", body: `{"name":"Ada again","email":"ada@example.com","password":"correct-horse"}`},
+		{method: "POST", path: "/api/v1/users", as: "admin", body: `{"name":"","email":"not-an-email","password":"short"}`},
+		{method: "POST", path: "/api/v1/users", as: "admin", body: `{"name":"Eve","email":"eve@example.com","password":"correct-horse","role":"admin"}`},
+		{method: "GET", path: "/api/v1/users/00000000-0000-0000-0000-000000000001", as: "admin"},
+		{method: "GET", path: "/api/v1/users/00000000-0000-0000-0000-000000000001", as: "admin", header: map[string]string{"If-None-Match": `"1"`}},
+		{method: "PUT", path: "/api/v1/users/00000000-0000-0000-0000-000000000001", as: "admin", header: map[string]string{"If-Match": `"7"`}, body: `{"name":"Ada Lovelace"}`},
+		{method: "PUT", path: "/api/v1/users/00000000-0000-0000-0000-000000000001", as: "admin", header: map[string]string{"If-Match": `"1"`}, body: `{"name":"Ada Lovelace"}`},
+		{method: "GET", path: "/api/v1/users?limit=10", as: "admin"},
+		{method: "GET", path: "/api/v1/users/search?q=lovelace", as: "admin"},
+		{method: "DELETE", path: "/api/v1/users/00000000-0000-0000-0000-000000000001?dry_run=true", as: "admin"},
+		{method: "DELETE", path: "/api/v1/users/00000000-0000-0000-0000-000000000001", as: "admin"},
+		{method: "GET", path: "/api/v1/users/00000000-0000-0000-0000-000000000001", as: "admin"},
+		{method: "GET", path: "/api/v1/users"},
+	},
+	"signup_and_login": {
+		{method: "POST", path: "/api/v1/signup", body: `{"name":"Grace","email":"grace@example.com","password":"correct-horse"}`},
+		{method: "POST", path: "/api/v1/signup", body: `{"name":"Grace","email":"grace@example.com","password":"correct-horse"}`},
+		{method: "POST", path: "/api/v1/signup", body: `{"name":"Mallory","email":"mallory@example.com","password":"correct-horse","role":"admin"}`},
+		{method: "POST", path: "/api/v1/auth/login", body: `{"email":"grace@example.com","password":"correct-horse"}`},
+		{method: "POST", path: "/api/v1/auth/login", body: `{"email":"grace@example.com","password":"wrong-horse"}`},
+		{method: "POST", path: "/api/v1/auth/refresh", body: `{"refresh_token":"not-a-token"}`},
+	},
+	"posts_and_comments": {
+		{method: "POST", path: "/api/v1/posts", as: "admin", body: `{"title":"Hello","body":"First post"}`},
+		{method: "GET", path: "/api/v1/posts"},
+		{method: "PUT", path: "/api/v1/posts/00000000-0000-0000-0000-000000000001", as: "admin", body: `{"status":"published"}`},
+		{method: "GET", path: "/api/v1/posts/00000000-0000-0000-0000-000000000001"},
+		{method: "POST", path: "/api/v1/posts/00000000-0000-0000-0000-000000000001/comments", as: "admin", body: `{"body":"Nice"}`},
+		{method: "GET", path: "/api/v1/posts/00000000-0000-0000-0000-000000000001/comments"},
+		{method: "GET", path: "/api/v1/posts/00000000-0000-0000-0000-000000000002"},
+	},
+}
+
+func TestGolden(t *testing.T) {
+	for name, steps := range scenarios {
+		name, steps := name, steps
+		t.Run(name, func(t *testing.T) {
+			server, adminToken := newTestServer(t)
+
+			var got bytes.Buffer
+			for _, s := range steps {
+				req := httptest.NewRequest(s.method, s.path, strings.NewReader(s.body))
+				if s.body != "" {
+					req.Header.Set("Content-Type", "application/json")
+				}
+				if s.as == "admin" {
+					req.Header.Set("Authorization", "Bearer "+adminToken)
+				}
+				for k, v := range s.header {
+					req.Header.Set(k, v)
+				}
+
+				rec := httptest.NewRecorder()
+				server.ServeHTTP(rec, req)
+				writeExchange(&got, s, rec)
+			}
+
+			path := filepath.Join("testdata", "golden", name+".golden")
+			if *update {
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, got.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("%v; run with -update to record it", err)
+			}
+			if !bytes.Equal(got.Bytes(), want) {
+				t.Errorf("responses differ from %s; if the change is intended, run with -update\n%s", path, diff(string(want), got.String()))
+			}
+		})
+	}
+}
+
+// volatileHeaders change from run to run and are left out of golden files.
+var volatileHeaders = map[string]bool{"Date": true, "X-Request-Id": true}
+
+var (
+	timestampPattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})`)
+	jwtPattern       = regexp.MustCompile(`eyJ[\w-]+\.[\w-]+\.[\w-]+`)
+	requestIDPattern = regexp.MustCompile(`"request_id": "[^"]*"`)
+)
+
+// scrub replaces the parts of a response that change from run to run.
+func scrub(s string) string {
+	s = timestampPattern.ReplaceAllString(s, "<time>")
+	s = jwtPattern.ReplaceAllString(s, "<jwt>")
+	return requestIDPattern.ReplaceAllString(s, `"request_id": "<request-id>"`)
+}
+
+// writeExchange renders a request and its response the way golden files
+// hold them: the request line, the status, sorted headers and an indented
+// body.
+func writeExchange(w *bytes.Buffer, s step, rec *httptest.ResponseRecorder) {
+	fmt.Fprintf(w, "### %s %s", s.method, s.path)
+	if s.as != "" {
+		fmt.Fprintf(w, " (as %s)", s.as)
+	}
+	w.WriteString("\n")
+	if s.body != "" {
+		fmt.Fprintf(w, "%s\n", s.body)
+	}
+
+	fmt.Fprintf(w, "\n%d %s\n", rec.Code, http.StatusText(rec.Code))
+	names := make([]string, 0, len(rec.Header()))
+	for name := range rec.Header() {
+		if !volatileHeaders[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "%s: %s\n", name, scrub(strings.Join(rec.Header()[name], ", ")))
+	}
+
+	body := rec.Body.Bytes()
+	var indented bytes.Buffer
+	if json.Indent(&indented, body, "", "  ") == nil {
+		body = indented.Bytes()
+	}
+	if len(body) > 0 {
+		fmt.Fprintf(w, "\n%s\n", scrub(string(body)))
+	}
+	w.WriteString("\n")
+}
+
+// diff reports the first line where got departs from want.
+func diff(want, got string) string {
+	wantLines, gotLines := strings.Split(want, "\n"), strings.Split(got, "\n")
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			return fmt.Sprintf("line %d:\n  want: %s\n  got:  %s", i+1, w, g)
+		}
+	}
+	return ""
+}
+
+// sequentialIDs hands out 00000000-0000-0000-0000-000000000001 and onward,
+// so golden files can name what a scenario creates.
+type sequentialIDs struct {
+	mu   sync.Mutex
+	next uint64
+}
+
+func (g *sequentialIDs) NewID() (uuid.UUID, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.next++
+	var id uuid.UUID
+	binary.BigEndian.PutUint64(id[8:], g.next)
+	return id, nil
+}
+
+// newTestServer wires the router the way cmd/server does on memory storage,
+// with an admin already stored, and returns it with the admin's access
+// token.
+func newTestServer(t *testing.T) (*chi.Mux, string) {
+	t.Helper()
+	log := &logger.Logger{Logger: zap.NewNop()}
+
+	validator, err := validation.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := app.RegisterValidators(validator, app.ValidationRules{}); err != nil {
+		t.Fatal(err)
+	}
+	tokens, err := auth.NewJWTIssuer("golden-test-secret-0123456789abcdef", "usermanagement", 15*time.Minute, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hasher, err := auth.NewBcryptHasher(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	captchaVerifier, err := captcha.New("none", "", deadline.Budget{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var publicIDs deliveryhttp.PublicIDs
+	if publicIDs.Users, err = publicid.New("uuid", "", "user"); err != nil {
+		t.Fatal(err)
+	}
+	if publicIDs.Posts, err = publicid.New("uuid", "", "post"); err != nil {
+		t.Fatal(err)
+	}
+	policy := moderation.NewWordList(nil, nil)
+	ids := &sequentialIDs{}
+
+	users := memory.NewUserRepository()
+	comments := memory.NewCommentRepository()
+	posts := memory.NewPostRepository(comments)
+	webhooks := memory.NewWebhookRepository()
+	apiKeys := memory.NewAPIKeyRepository()
+	tx := memory.NewUnitOfWork()
+
+	hash, err := hasher.Hash("correct-horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := users.Save(context.Background(), user.Reconstruct(adminID, "Admin", "admin@example.com", hash, user.RoleAdmin, created, created, 1)); err != nil {
+		t.Fatal(err)
+	}
+	adminTokens, err := tokens.Issue(adminID, user.RoleAdmin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	createUser := app.NewCreateUserUseCase(users, ids, hasher, policy, validator)
+	// Destructive use cases preview under ?dry_run=true, as in cmd/server.
+	deleteUser := app.NewDeleteUserUseCase(users, tx)
+	deletePost := post.NewDeletePostUseCase(posts)
+	deleteComment := comment.NewDeleteCommentUseCase(comments, posts)
+	deleteWebhook := webhook.NewDeleteWebhookUseCase(webhooks)
+	revokeAPIKey := apikey.NewRevokeAPIKeyUseCase(apiKeys)
+	handler := deliveryhttp.NewUserHandler(
+		createUser,
+		app.NewCreateOrGetUserUseCase(createUser, users, hasher),
+		app.NewBulkCreateUsersUseCase(users, ids, hasher, policy, validator),
+		app.NewGetUserUseCase(users),
+		app.NewListUsersUseCase(users),
+		app.NewSearchUsersUseCase(users, validator),
+		app.NewUpdateUserUseCase(users, tx, policy, validator),
+		usecase.DryRunCommand[app.DeleteUserInput](deleteUser, deleteUser),
+		publicIDs, log)
+	postHandler := deliveryhttp.NewPostHandler(
+		post.NewCreatePostUseCase(posts, ids, policy, validator),
+		post.NewGetPostUseCase(posts),
+		post.NewListPostsUseCase(posts),
+		post.NewUpdatePostUseCase(posts, tx, policy, validator),
+		usecase.DryRunCommand[uuid.UUID](deletePost, deletePost),
+		publicIDs, log)
+	commentHandler := deliveryhttp.NewCommentHandler(
+		comment.NewCreateCommentUseCase(comments, posts, ids, policy, validator),
+		comment.NewListCommentsUseCase(comments, posts, validator),
+		comment.NewModerateCommentUseCase(comments, posts, validator),
+		usecase.DryRunCommand[comment.DeleteCommentInput](deleteComment, deleteComment),
+		publicIDs, log)
+	authHandler := deliveryhttp.NewAuthHandler(
+		app.NewLoginUserUseCase(users, nil, tokens, hasher, validator),
+		app.NewRefreshTokenUseCase(users, tokens, validator),
+		log)
+	signupHandler := deliveryhttp.NewSignupHandler(
+		app.NewSignupUseCase(createUser, captchaVerifier, ratelimit.NewWindow(100, time.Hour), validator, nil),
+		nil, publicIDs, log)
+	webhookHandler := deliveryhttp.NewWebhookHandler(
+		webhook.NewCreateWebhookUseCase(webhooks, ids, validator),
+		webhook.NewListWebhooksUseCase(webhooks),
+		usecase.DryRunCommand[uuid.UUID](deleteWebhook, deleteWebhook),
+		webhook.NewListDeliveriesUseCase(webhooks, webhooks),
+		publicIDs, log)
+	apiKeyHandler := deliveryhttp.NewAPIKeyHandler(
+		apikey.NewCreateAPIKeyUseCase(apiKeys, ids, validator),
+		apikey.NewListAPIKeysUseCase(apiKeys),
+		usecase.DryRunCommand[uuid.UUID](revokeAPIKey, revokeAPIKey),
+		publicIDs, log)
+
+	router := deliveryhttp.NewRouter(handler, postHandler, commentHandler, authHandler, signupHandler, webhookHandler, apiKeyHandler,
+		tokens, apikey.NewVerifier(apiKeys, users), deliveryhttp.NewDeprecationRegistry(log), deliveryhttp.RouterOptions{}, log)
+	return router, adminTokens.AccessToken
+}
@@ -1,8 +1,12 @@
 package http
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"io"
+	"mime"
 	"net/http"
 	"strconv"
 
@@ -10,130 +14,332 @@ import (
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 
+	"usermanagement/internal/application/pagination"
+	"usermanagement/internal/application/usecase"
 	app "usermanagement/internal/application/user"
-	"usermanagement/internal/domain/user"
+	"usermanagement/internal/application/validation"
+	"usermanagement/internal/domain/errcode"
 	"usermanagement/internal/infra/logger"
 )
 
 // UserHandler handles HTTP requests for user management.
 type UserHandler struct {
-	createUC *app.CreateUserUseCase
-	getUC    *app.GetUserUseCase
-	updateUC *app.UpdateUserUseCase
-	deleteUC *app.DeleteUserUseCase
-	logger   *logger.Logger
+	createUC      usecase.UseCase[app.CreateUserInput, *app.UserOutput]
+	createOrGetUC usecase.UseCase[app.CreateUserInput, *app.CreateOrGetUserOutput]
+	bulkCreateUC  usecase.UseCase[app.BulkCreateUsersInput, *app.BulkCreateUsersOutput]
+	getUC         usecase.UseCase[uuid.UUID, *app.UserOutput]
+	listUC        usecase.UseCase[pagination.Params, *pagination.Page[app.UserOutput]]
+	searchUC      usecase.UseCase[app.SearchUsersInput, *pagination.Page[app.UserOutput]]
+	updateUC      usecase.UseCase[app.UpdateUserInput, *app.UserOutput]
+	deleteUC      usecase.Command[app.DeleteUserInput]
+	ids           PublicIDs
+	logger        *logger.Logger
 }
 
 // NewUserHandler creates a new HTTP handler with injected use cases.
 func NewUserHandler(
-	createUC *app.CreateUserUseCase,
-	getUC *app.GetUserUseCase,
-	updateUC *app.UpdateUserUseCase,
-	deleteUC *app.DeleteUserUseCase,
+	createUC usecase.UseCase[app.CreateUserInput, *app.UserOutput],
+	createOrGetUC usecase.UseCase[app.CreateUserInput, *app.CreateOrGetUserOutput],
+	bulkCreateUC usecase.UseCase[app.BulkCreateUsersInput, *app.BulkCreateUsersOutput],
+	getUC usecase.UseCase[uuid.UUID, *app.UserOutput],
+	listUC usecase.UseCase[pagination.Params, *pagination.Page[app.UserOutput]],
+	searchUC usecase.UseCase[app.SearchUsersInput, *pagination.Page[app.UserOutput]],
+	updateUC usecase.UseCase[app.UpdateUserInput, *app.UserOutput],
+	deleteUC usecase.Command[app.DeleteUserInput],
+	ids PublicIDs,
 	logger *logger.Logger,
 ) *UserHandler {
 	return &UserHandler{
-		createUC: createUC,
-		getUC:    getUC,
-		updateUC: updateUC,
-		deleteUC: deleteUC,
-		logger:   logger,
+		createUC:      createUC,
+		createOrGetUC: createOrGetUC,
+		bulkCreateUC:  bulkCreateUC,
+		getUC:         getUC,
+		listUC:        listUC,
+		searchUC:      searchUC,
+		updateUC:      updateUC,
+		deleteUC:      deleteUC,
+		ids:           ids,
+		logger:        logger,
 	}
 }
 
 // Create handles POST /users.
 func (h *UserHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var input app.CreateUserInput
-	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		respondError(w, http.StatusBadRequest, "invalid request body")
+	if err := decodeStrict(r.Body, &input); err != nil {
+		respondBodyError(w, r, err)
 		return
 	}
 
 	output, err := h.createUC.Execute(r.Context(), input)
 	if err != nil {
-		h.handleDomainError(w, err)
+		handleDomainError(w, r, err, h.logger)
 		return
 	}
 
-	respondJSON(w, http.StatusCreated, output)
+	respondJSON(w, http.StatusCreated, h.ids.user(output))
 }
 
-// GetByID handles GET /users/{id}.
+// CreateOrGet handles POST /users/create-or-get. It answers 201 when the
+// user was created and 200 when a previous attempt already created it.
+func (h *UserHandler) CreateOrGet(w http.ResponseWriter, r *http.Request) {
+	var input app.CreateUserInput
+	if err := decodeStrict(r.Body, &input); err != nil {
+		respondBodyError(w, r, err)
+		return
+	}
+
+	output, err := h.createOrGetUC.Execute(r.Context(), input)
+	if err != nil {
+		handleDomainError(w, r, err, h.logger)
+		return
+	}
+
+	status := http.StatusOK
+	if output.Created {
+		status = http.StatusCreated
+	}
+	respondJSON(w, status, h.ids.user(&output.User))
+}
+
+// BulkCreate handles POST /users/bulk. The body is a JSON array of users,
+// or one user per line with Content-Type application/x-ndjson. It answers
+// 200 with the outcome of every item, whether or not all were created.
+func (h *UserHandler) BulkCreate(w http.ResponseWriter, r *http.Request) {
+	users, err := decodeBulkUsers(r)
+	if err != nil {
+		respondBodyError(w, r, err)
+		return
+	}
+
+	output, err := h.bulkCreateUC.Execute(r.Context(), app.BulkCreateUsersInput{Users: users})
+	if err != nil {
+		handleDomainError(w, r, err, h.logger)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, h.ids.bulkUsers(output))
+}
+
+// decodeBulkUsers reads the users of a bulk creation. It stops one past
+// MaxBulkUsers, which is enough for validation to reject the request.
+// Unknown members of any user fail the whole request, reported as
+// users[i].member.
+func decodeBulkUsers(r *http.Request) ([]app.CreateUserInput, error) {
+	dec := json.NewDecoder(r.Body)
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	ndjson := mediaType == "application/x-ndjson"
+	if !ndjson {
+		if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+			return nil, errors.New("expected a JSON array")
+		}
+	}
+
+	var users []app.CreateUserInput
+	var unknown []validation.FieldError
+	for len(users) <= app.MaxBulkUsers {
+		if !ndjson && !dec.More() {
+			break
+		}
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			if ndjson && errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		var input app.CreateUserInput
+		if err := decodeStrict(bytes.NewReader(raw), &input); err != nil {
+			var verr *validation.Error
+			if !errors.As(err, &verr) {
+				return nil, err
+			}
+			prefix := "users[" + strconv.Itoa(len(users)) + "]."
+			for _, f := range verr.Fields {
+				unknown = append(unknown, validation.FieldError{Field: prefix + f.Field, Message: prefix + f.Message})
+			}
+		}
+		users = append(users, input)
+	}
+	if len(unknown) > 0 {
+		return nil, &validation.Error{Fields: unknown}
+	}
+	return users, nil
+}
+
+// GetByID handles GET /users/{id}, answering 304 when If-None-Match
+// names the current version.
 func (h *UserHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
-	id, err := uuid.Parse(idStr)
+	id, err := parseID(h.ids.Users, idStr)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, "invalid user id format")
+		respondError(w, r, http.StatusBadRequest, errcode.InvalidRequest, "invalid user id format")
 		return
 	}
 
 	output, err := h.getUC.Execute(r.Context(), id)
 	if err != nil {
-		h.handleDomainError(w, err)
+		handleDomainError(w, r, err, h.logger)
 		return
 	}
 
-	respondJSON(w, http.StatusOK, output)
+	setETag(w, output.Version)
+	if etagMatches(r.Header.Get("If-None-Match"), output.Version) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	respondJSON(w, http.StatusOK, h.ids.user(output))
 }
 
+// List handles GET /users?limit=&offset= and GET /users?limit=&cursor=.
+func (h *UserHandler) List(w http.ResponseWriter, r *http.Request) {
+	page, err := h.listUC.Execute(r.Context(), parsePagination(r))
+	if err != nil {
+		handleDomainError(w, r, err, h.logger)
+		return
+	}
+
+	respondPage(w, mapPage(page, h.ids.user))
+}
 
-// Update handles PUT /users/{id}.
+// Search handles GET /users/search?q=&limit=&offset=.
+func (h *UserHandler) Search(w http.ResponseWriter, r *http.Request) {
+	page, err := h.searchUC.Execute(r.Context(), app.SearchUsersInput{
+		Query: r.URL.Query().Get("q"),
+		Page:  parsePagination(r),
+	})
+	if err != nil {
+		handleDomainError(w, r, err, h.logger)
+		return
+	}
+
+	respondPage(w, mapPage(page, h.ids.user))
+}
+
+// Update handles PUT /users/{id}, conditionally with If-Match.
 func (h *UserHandler) Update(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
-	id, err := uuid.Parse(idStr)
+	id, err := parseID(h.ids.Users, idStr)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, "invalid user id format")
+		respondError(w, r, http.StatusBadRequest, errcode.InvalidRequest, "invalid user id format")
 		return
 	}
 
 	var input app.UpdateUserInput
-	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		respondError(w, http.StatusBadRequest, "invalid request body")
+	if err := decodeStrict(r.Body, &input); err != nil {
+		respondBodyError(w, r, err)
 		return
 	}
 	input.ID = id
+	if input.IfVersion, err = parseIfMatch(r); err != nil {
+		respondError(w, r, http.StatusBadRequest, errcode.InvalidRequest, err.Error())
+		return
+	}
 
 	output, err := h.updateUC.Execute(r.Context(), input)
 	if err != nil {
-		h.handleDomainError(w, err)
+		// A stale If-Match fails the precondition; without one, the user
+		// changed between being read and written.
+		if input.IfVersion != nil && errcode.Of(err) == errcode.VersionConflict {
+			respondStaleVersion(w, r)
+			return
+		}
+		handleDomainError(w, r, err, h.logger)
 		return
 	}
 
-	respondJSON(w, http.StatusOK, output)
+	setETag(w, output.Version)
+	respondJSON(w, http.StatusOK, h.ids.user(output))
 }
 
-// Delete handles DELETE /users/{id}.
+// Delete handles DELETE /users/{id}, conditionally with If-Match.
 func (h *UserHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
-	id, err := uuid.Parse(idStr)
+	id, err := parseID(h.ids.Users, idStr)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, "invalid user id format")
+		respondError(w, r, http.StatusBadRequest, errcode.InvalidRequest, "invalid user id format")
 		return
 	}
 
-	if err := h.deleteUC.Execute(r.Context(), id); err != nil {
-		h.handleDomainError(w, err)
+	input := app.DeleteUserInput{ID: id}
+	if input.IfVersion, err = parseIfMatch(r); err != nil {
+		respondError(w, r, http.StatusBadRequest, errcode.InvalidRequest, err.Error())
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	if err := h.deleteUC.Execute(r.Context(), input); err != nil {
+		if input.IfVersion != nil && errcode.Of(err) == errcode.VersionConflict {
+			respondStaleVersion(w, r)
+			return
+		}
+		handleDomainError(w, r, err, h.logger)
+		return
+	}
+
+	respondDeleted(w, r, h.ids, h.logger)
+}
+
+// statusByCode maps error codes to HTTP status codes.
+var statusByCode = map[errcode.Code]int{
+	errcode.UserNotFound:        http.StatusNotFound,
+	errcode.EmailConflict:       http.StatusConflict,
+	errcode.DataConflict:        http.StatusConflict,
+	errcode.ValidationFailed:    http.StatusBadRequest,
+	errcode.InvalidRequest:      http.StatusBadRequest,
+	errcode.Unavailable:         http.StatusServiceUnavailable,
+	errcode.Unauthorized:        http.StatusUnauthorized,
+	errcode.InvalidCredentials:  http.StatusUnauthorized,
+	errcode.Forbidden:           http.StatusForbidden,
+	errcode.PostNotFound:        http.StatusNotFound,
+	errcode.SlugConflict:        http.StatusConflict,
+	errcode.CommentNotFound:     http.StatusNotFound,
+	errcode.ContentRejected:     http.StatusUnprocessableEntity,
+	errcode.RateLimited:         http.StatusTooManyRequests,
+	errcode.CaptchaFailed:       http.StatusBadRequest,
+	errcode.WebhookNotFound:     http.StatusNotFound,
+	errcode.APIKeyNotFound:      http.StatusNotFound,
+	errcode.VersionConflict:     http.StatusConflict,
+	errcode.AccountNotActivated: http.StatusForbidden,
+	errcode.ActivationInvalid:   http.StatusBadRequest,
 }
 
 // handleDomainError maps domain errors to HTTP status codes.
-func (h *UserHandler) handleDomainError(w http.ResponseWriter, err error) {
+func handleDomainError(w http.ResponseWriter, r *http.Request, err error, logger *logger.Logger) {
+	var verr *validation.Error
+	if errors.As(err, &verr) {
+		respondValidationError(w, r, verr)
+		return
+	}
+
+	// Context errors first: they arrive wrapped in repository errors.
 	switch {
-	case errors.Is(err, user.ErrUserNotFound):
-		respondError(w, http.StatusNotFound, "user not found")
-	case errors.Is(err, user.ErrEmailExists):
-		respondError(w, http.StatusConflict, "email already exists")
-	case errors.Is(err, user.ErrEmptyName):
-		respondError(w, http.StatusBadRequest, "name cannot be empty")
-	case errors.Is(err, user.ErrInvalidEmail):
-		respondError(w, http.StatusBadRequest, "invalid email format")
-	default:
-		h.logger.Error("unexpected error", zap.Error(err))
-		respondError(w, http.StatusInternalServerError, "internal server error")
+	case errors.Is(err, context.DeadlineExceeded):
+		logger.Warn("request deadline exceeded", zap.Error(err))
+		respondError(w, r, http.StatusGatewayTimeout, errcode.Timeout, "request timed out")
+		return
+	case errors.Is(err, context.Canceled):
+		// The client is gone; there is no one to answer.
+		logger.Debug("request canceled", zap.Error(err))
+		return
+	}
+
+	code := errcode.Of(err)
+	status, ok := statusByCode[code]
+	if !ok {
+		logger.Error("unexpected error", zap.Error(err))
+		respondError(w, r, http.StatusInternalServerError, errcode.Internal, "internal server error")
+		return
 	}
+
+	if status >= http.StatusInternalServerError {
+		logger.Warn("dependency unavailable", zap.Error(err))
+		respondError(w, r, status, code, "service temporarily unavailable")
+		return
+	}
+
+	var coded *errcode.Error
+	errors.As(err, &coded)
+	respondError(w, r, status, code, coded.Message)
 }
 
 // Helper functions
@@ -141,29 +347,40 @@ func (h *UserHandler) handleDomainError(w http.ResponseWriter, err error) {
 func respondJSON(w http.ResponseWriter, status int, payload interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(payload)
+	newJSONEncoder(w).Encode(payload)
 }
 
-func respondError(w http.ResponseWriter, status int, message string) {
-	respondJSON(w, status, map[string]string{"error": message})
+// respondPage writes a list page, also reporting its total in
+// X-Total-Count for clients that read headers only.
+func respondPage[T any](w http.ResponseWriter, page *pagination.Page[T]) {
+	w.Header().Set("X-Total-Count", strconv.FormatInt(page.Meta.Total, 10))
+	respondJSON(w, http.StatusOK, page)
 }
 
-func parsePagination(r *http.Request) (limit, offset int) {
+// respondError answers r with a problem of code, message being its detail.
+func respondError(w http.ResponseWriter, r *http.Request, status int, code errcode.Code, message string) {
+	respondProblem(w, newProblem(r, status, code, message))
+}
+
+// respondValidationError answers r with the fields verr found invalid.
+func respondValidationError(w http.ResponseWriter, r *http.Request, verr *validation.Error) {
+	p := newProblem(r, http.StatusBadRequest, errcode.ValidationFailed, "validation failed")
+	p.Fields = verr.Fields
+	respondProblem(w, p)
+}
+
+// parsePagination reads limit and offset from the query string. Missing or
+// malformed values fall back to the defaults.
+func parsePagination(r *http.Request) pagination.Params {
 	query := r.URL.Query()
-	
-	limitStr := query.Get("limit")
-	if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
-		limit = l
-	} else {
-		limit = 10
-	}
 
-	offsetStr := query.Get("offset")
-	if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
-		offset = o
-	} else {
-		offset = 0
+	var params pagination.Params
+	if l, err := strconv.Atoi(query.Get("limit")); err == nil {
+		params.Limit = l
 	}
-
-	return
-}
\ No newline at end of file
+	if o, err := strconv.Atoi(query.Get("offset")); err == nil {
+		params.Offset = o
+	}
+	params.Cursor = query.Get("cursor")
+	return params.Normalize()
+}
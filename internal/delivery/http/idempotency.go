@@ -0,0 +1,206 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"usermanagement/internal/application/auth"
+	"usermanagement/internal/domain/errcode"
+	"usermanagement/internal/infra/idempotency"
+	"usermanagement/internal/infra/logger"
+)
+
+// IdempotencyHeader carries a client-chosen key that makes a write safe to
+// retry: a request repeated with the same key gets the original response
+// instead of running again. Replayed responses carry IdempotentReplayHeader.
+const (
+	IdempotencyHeader      = "Idempotency-Key"
+	IdempotentReplayHeader = "Idempotent-Replayed"
+)
+
+// maxIdempotencyKeyLen bounds the keys clients may send.
+const maxIdempotencyKeyLen = 255
+
+// idempotencyStoreTimeout bounds storing the outcome once the handler is
+// done, whatever is left of the request deadline.
+const idempotencyStoreTimeout = 5 * time.Second
+
+// IdempotencyOptions configures Idempotency.
+type IdempotencyOptions struct {
+	Store idempotency.Store
+	// TTL is how long responses are kept for replay.
+	TTL time.Duration
+	// Lock is how long a running request holds its key; retries arriving
+	// meanwhile are refused. It should outlast the request timeout.
+	Lock time.Duration
+}
+
+// Idempotency replays the stored response to requests repeating an
+// IdempotencyHeader. Keys are scoped to the caller, and a key sent again
+// with a different method, path, query or body is refused rather than
+// replayed. Responses are stored before any envelope or client profile is
+// applied, so a retry may ask for a different shape.
+//
+// Server errors and rate limiting are not stored: the key is released so
+// the retry runs again.
+func Idempotency(opts IdempotencyOptions, logger *logger.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKeyLen {
+				respondError(w, r, http.StatusBadRequest, errcode.InvalidRequest, "Idempotency-Key must be at most 255 characters")
+				return
+			}
+
+			// The whole body is hashed and kept for the handler, so it
+			// is capped even when the router's LimitBody is not in front.
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+			if err != nil {
+				respondBodyError(w, r, err)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			claim := idempotency.Claim{
+				Key:         idempotencyScope(r.Context()) + ":" + key,
+				Fingerprint: fingerprint(r, body),
+				Lock:        opts.Lock,
+				TTL:         opts.TTL,
+			}
+			stored, err := opts.Store.Claim(r.Context(), claim)
+			if err != nil {
+				logger.Warn("failed to claim idempotency key", zap.Error(err))
+				respondError(w, r, http.StatusServiceUnavailable, errcode.Unavailable, "service temporarily unavailable")
+				return
+			}
+
+			switch {
+			case stored == nil:
+				serveIdempotent(w, r, next, opts.Store, claim, logger)
+			case stored.Fingerprint != claim.Fingerprint:
+				respondError(w, r, http.StatusUnprocessableEntity, errcode.IdempotencyKeyReused, "Idempotency-Key was already used for a different request")
+			case !stored.Done():
+				w.Header().Set("Retry-After", "1")
+				respondError(w, r, http.StatusConflict, errcode.IdempotencyKeyInUse, "a request with this Idempotency-Key is still in progress")
+			default:
+				replay(w, stored)
+			}
+		})
+	}
+}
+
+// serveIdempotent runs the request holding claim and stores its response,
+// or releases the key when the response should not be replayed.
+func serveIdempotent(w http.ResponseWriter, r *http.Request, next http.Handler, store idempotency.Store, claim idempotency.Claim, logger *logger.Logger) {
+	before := w.Header().Clone()
+	rec := &recordingWriter{ResponseWriter: w, status: http.StatusOK}
+
+	completed := false
+	defer func() {
+		if completed {
+			return
+		}
+		// The handler panicked; let a retry run it again.
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), idempotencyStoreTimeout)
+		defer cancel()
+		if err := store.Release(ctx, claim.Key, claim.Fingerprint); err != nil {
+			logger.Warn("failed to release idempotency key", zap.Error(err))
+		}
+	}()
+
+	next.ServeHTTP(rec, r)
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), idempotencyStoreTimeout)
+	defer cancel()
+
+	if rec.status >= http.StatusInternalServerError || rec.status == http.StatusTooManyRequests {
+		completed = true
+		if err := store.Release(ctx, claim.Key, claim.Fingerprint); err != nil {
+			logger.Warn("failed to release idempotency key", zap.Error(err))
+		}
+		return
+	}
+
+	// Keep the headers the handler set, not those of outer middleware,
+	// which set them again on the replay.
+	header := http.Header{}
+	for name, values := range w.Header() {
+		if !slices.Equal(before[name], values) {
+			header[name] = slices.Clone(values)
+		}
+	}
+
+	completed = true
+	err := store.Complete(ctx, claim.Key, idempotency.Record{
+		Fingerprint: claim.Fingerprint,
+		Status:      rec.status,
+		Header:      header,
+		Body:        rec.body.Bytes(),
+	})
+	if err != nil {
+		// The response went out; a retry will be refused as in progress
+		// until the claim lapses.
+		logger.Error("failed to store idempotent response", zap.Error(err))
+	}
+}
+
+// replay writes a stored response.
+func replay(w http.ResponseWriter, stored *idempotency.Record) {
+	for name, values := range stored.Header {
+		w.Header()[name] = values
+	}
+	w.Header().Set(IdempotentReplayHeader, "true")
+	w.Header().Set("Content-Length", strconv.Itoa(len(stored.Body)))
+	w.WriteHeader(stored.Status)
+	w.Write(stored.Body)
+}
+
+// idempotencyScope keeps one caller's keys apart from another's, and an
+// API key's apart from its owner's. Unauthenticated routes share one scope.
+func idempotencyScope(ctx context.Context) string {
+	if caller, ok := auth.CallerFrom(ctx); ok {
+		if caller.ViaAPIKey() {
+			return "api_key:" + caller.KeyID.String()
+		}
+		return caller.UserID.String()
+	}
+	return "anonymous"
+}
+
+// fingerprint identifies a request by its method, path, query and body.
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.RequestURI()+"\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// recordingWriter copies the response while writing it through.
+type recordingWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rw *recordingWriter) WriteHeader(code int) {
+	rw.status = code
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *recordingWriter) Write(b []byte) (int, error) {
+	rw.body.Write(b)
+	return rw.ResponseWriter.Write(b)
+}
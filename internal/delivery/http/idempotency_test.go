@@ -0,0 +1,135 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	deliveryhttp "usermanagement/internal/delivery/http"
+	"usermanagement/internal/infra/idempotency"
+	"usermanagement/internal/infra/logger"
+)
+
+// newIdempotent wraps handler in the Idempotency middleware on a fresh
+// memory store.
+func newIdempotent(handler http.HandlerFunc) http.Handler {
+	return deliveryhttp.Idempotency(deliveryhttp.IdempotencyOptions{
+		Store: idempotency.NewMemoryStore(),
+		TTL:   time.Hour,
+		Lock:  time.Minute,
+	}, &logger.Logger{Logger: zap.NewNop()})(handler)
+}
+
+// sendIdempotent posts body to h with the given Idempotency-Key.
+func sendIdempotent(h http.Handler, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(deliveryhttp.IdempotencyHeader, key)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIdempotencyReplaysTheFirstResponse(t *testing.T) {
+	var calls atomic.Int32
+	h := newIdempotent(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		w.Header().Set("Location", "/api/v1/users/1")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"call":` + strconv.Itoa(int(n)) + `}`))
+	})
+
+	first := sendIdempotent(h, "k1", `{"name":"Ada"}`)
+	retry := sendIdempotent(h, "k1", `{"name":"Ada"}`)
+
+	if calls.Load() != 1 {
+		t.Fatalf("handler ran %d times, want once", calls.Load())
+	}
+	if retry.Code != http.StatusCreated || retry.Body.String() != first.Body.String() {
+		t.Fatalf("replay = %d %s, want %d %s", retry.Code, retry.Body, first.Code, first.Body)
+	}
+	if retry.Header().Get(deliveryhttp.IdempotentReplayHeader) != "true" || retry.Header().Get("Location") != "/api/v1/users/1" {
+		t.Fatalf("replay headers = %v", retry.Header())
+	}
+	if first.Header().Get(deliveryhttp.IdempotentReplayHeader) != "" {
+		t.Fatal("first response marked as a replay")
+	}
+}
+
+func TestIdempotencyRefusesRetriesInFlight(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	h := newIdempotent(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- sendIdempotent(h, "k1", `{}`) }()
+	<-started
+
+	retry := sendIdempotent(h, "k1", `{}`)
+	if retry.Code != http.StatusConflict || retry.Header().Get("Retry-After") == "" {
+		t.Fatalf("retry in flight = %d (Retry-After %q), want 409 with Retry-After", retry.Code, retry.Header().Get("Retry-After"))
+	}
+
+	close(release)
+	if first := <-done; first.Code != http.StatusCreated {
+		t.Fatalf("first request = %d, want 201", first.Code)
+	}
+}
+
+func TestIdempotencyRefusesKeyReuseWithAnotherBody(t *testing.T) {
+	var calls atomic.Int32
+	h := newIdempotent(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	sendIdempotent(h, "k1", `{"name":"Ada"}`)
+	reused := sendIdempotent(h, "k1", `{"name":"Eve"}`)
+
+	if reused.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("key reused = %d, want 422", reused.Code)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("handler ran %d times, want once", calls.Load())
+	}
+}
+
+func TestIdempotencyRunsAgainAfterServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	h := newIdempotent(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	sendIdempotent(h, "k1", `{}`)
+	if retry := sendIdempotent(h, "k1", `{}`); retry.Code != http.StatusCreated {
+		t.Fatalf("retry after a server error = %d, want 201", retry.Code)
+	}
+}
+
+func TestIdempotencyLimitsTheBody(t *testing.T) {
+	var calls atomic.Int32
+	h := newIdempotent(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	})
+
+	rec := sendIdempotent(h, "k1", `"`+strings.Repeat("a", 2<<20)+`"`)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("oversized body = %d, want 413", rec.Code)
+	}
+	if calls.Load() != 0 {
+		t.Fatal("handler ran for an oversized body")
+	}
+}
@@ -0,0 +1,43 @@
+package http
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var requestsInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "http_requests_in_flight",
+	Help: "Number of HTTP requests currently being served.",
+})
+
+// InFlightTracker counts requests being served, so shutdown can report how
+// much work is still draining.
+type InFlightTracker struct {
+	count atomic.Int64
+}
+
+// NewInFlightTracker creates a tracker with no requests in flight.
+func NewInFlightTracker() *InFlightTracker {
+	return &InFlightTracker{}
+}
+
+// Middleware counts requests for as long as they are being served.
+func (t *InFlightTracker) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.count.Add(1)
+		requestsInFlight.Inc()
+		defer func() {
+			t.count.Add(-1)
+			requestsInFlight.Dec()
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Count returns the number of requests currently in flight.
+func (t *InFlightTracker) Count() int64 {
+	return t.count.Load()
+}
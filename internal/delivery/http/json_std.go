@@ -0,0 +1,14 @@
+//go:build !gojson
+
+package http
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// newJSONEncoder returns the encoder used for all responses. Build with
+// -tags gojson to switch to the faster goccy/go-json implementation.
+func newJSONEncoder(w io.Writer) *json.Encoder {
+	return json.NewEncoder(w)
+}
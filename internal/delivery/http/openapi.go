@@ -0,0 +1,459 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	apikeyapp "usermanagement/internal/application/apikey"
+	"usermanagement/internal/application/auth"
+	"usermanagement/internal/application/comment"
+	"usermanagement/internal/application/pagination"
+	"usermanagement/internal/application/post"
+	app "usermanagement/internal/application/user"
+	"usermanagement/internal/application/webhook"
+	"usermanagement/internal/domain/apikey"
+	"usermanagement/internal/infra/logger"
+)
+
+// OpenAPIPath serves the OpenAPI document describing the API.
+const OpenAPIPath = "/api/v1/openapi.json"
+
+// authMode says whether an operation needs a bearer access token.
+type authMode int
+
+const (
+	authNone authMode = iota
+	authOptional
+	authRequired
+)
+
+// apiParam is a query parameter of an operation.
+type apiParam struct {
+	Name        string
+	Description string
+	Schema      map[string]any
+}
+
+// apiOperation documents one route. Request and response bodies are
+// described by the DTO values handlers decode and encode, so their schemas
+// follow the structs instead of being maintained by hand.
+type apiOperation struct {
+	Method   string
+	Path     string
+	Tag      string
+	Summary  string
+	Auth     authMode
+	Query    []apiParam
+	Request  any
+	Status   int
+	Response any
+}
+
+var pageParams = []apiParam{
+	{Name: "limit", Description: "Page size, at most " + strconv.Itoa(pagination.MaxLimit), Schema: map[string]any{"type": "integer", "minimum": 1, "maximum": pagination.MaxLimit, "default": pagination.DefaultLimit}},
+	{Name: "offset", Description: "Results to skip", Schema: map[string]any{"type": "integer", "minimum": 0, "default": 0}},
+}
+
+// dryRunParams document the DryRun convention of destructive routes.
+var dryRunParams = []apiParam{
+	{Name: "dry_run", Description: "Check the request and answer 200 with the changes it would make, without making them", Schema: map[string]any{"type": "boolean", "default": false}},
+}
+
+// idempotencyParam documents IdempotencyHeader.
+var idempotencyParam = map[string]any{
+	"name": IdempotencyHeader, "in": "header",
+	"description": "Makes the request safe to retry: a retry with the same key and request gets the first response back, marked " + IdempotentReplayHeader + ", instead of running again",
+	"schema":      map[string]any{"type": "string", "maxLength": maxIdempotencyKeyLen},
+}
+
+var cursorParam = apiParam{Name: "cursor", Description: "Resume after the page that returned this next_cursor; offset is ignored", Schema: map[string]any{"type": "string"}}
+
+// apiOperations lists every /api/v1 route. NewRouter warns at startup about
+// routes missing from it, and the tests fail on them.
+var apiOperations = []apiOperation{
+	{Method: http.MethodPost, Path: "/api/v1/auth/login", Tag: "auth", Summary: "Log in with email and password",
+		Request: app.LoginUserInput{}, Status: http.StatusOK, Response: auth.Tokens{}},
+	{Method: http.MethodPost, Path: "/api/v1/auth/refresh", Tag: "auth", Summary: "Exchange a refresh token for new tokens",
+		Request: app.RefreshTokenInput{}, Status: http.StatusOK, Response: auth.Tokens{}},
+
+	{Method: http.MethodPost, Path: "/api/v1/signup", Tag: "users", Summary: "Sign up, with a CAPTCHA token; rate limited per IP",
+		Request: app.SignupInput{}, Status: http.StatusCreated, Response: app.UserOutput{}},
+	{Method: http.MethodPost, Path: "/api/v1/signup/activate", Tag: "users", Summary: "Activate a signup with the token mailed to it, when signups need activation",
+		Request: app.ActivateUserInput{}, Status: http.StatusOK, Response: app.UserOutput{}},
+	{Method: http.MethodPost, Path: "/api/v1/users", Tag: "users", Summary: "Create a user (admins only); the public way in is /signup", Auth: authRequired,
+		Request: app.CreateUserInput{}, Status: http.StatusCreated, Response: app.UserOutput{}},
+	{Method: http.MethodPost, Path: "/api/v1/users/create-or-get", Tag: "users", Summary: "Create a user, or return it if a previous attempt already did (200); admins only", Auth: authRequired,
+		Request: app.CreateUserInput{}, Status: http.StatusCreated, Response: app.UserOutput{}},
+	{Method: http.MethodPost, Path: "/api/v1/users/bulk", Tag: "users", Summary: "Register up to " + strconv.Itoa(app.MaxBulkUsers) + " users (admins only); also accepts NDJSON. Items fail individually", Auth: authRequired,
+		Request: []app.CreateUserInput{}, Status: http.StatusOK, Response: app.BulkCreateUsersOutput{}},
+	{Method: http.MethodGet, Path: "/api/v1/users", Tag: "users", Summary: "List users, newest first", Auth: authRequired,
+		Query: append(pageParams[:len(pageParams):len(pageParams)], cursorParam), Status: http.StatusOK, Response: pagination.Page[app.UserOutput]{}},
+	{Method: http.MethodGet, Path: "/api/v1/users/search", Tag: "users", Summary: "Search users by name or email, best match first", Auth: authRequired,
+		Query: append([]apiParam{{
+			Name: "q", Description: "Words to find; partial words and small typos match too",
+			Schema: map[string]any{"type": "string", "minLength": 1, "maxLength": 200},
+		}}, pageParams...), Status: http.StatusOK, Response: pagination.Page[app.UserOutput]{}},
+	{Method: http.MethodGet, Path: "/api/v1/users/{id}", Tag: "users", Summary: "Get a user; its ETag is the user's version, and If-None-Match with it answers 304", Auth: authRequired,
+		Status: http.StatusOK, Response: app.UserOutput{}},
+	{Method: http.MethodPut, Path: "/api/v1/users/{id}", Tag: "users", Summary: "Update a user; with If-Match, only if still at that version (412 otherwise)", Auth: authRequired,
+		Request: app.UpdateUserInput{}, Status: http.StatusOK, Response: app.UserOutput{}},
+	{Method: http.MethodDelete, Path: "/api/v1/users/{id}", Tag: "users", Summary: "Delete a user (admins only); with If-Match, only if still at that version (412 otherwise)", Auth: authRequired,
+		Query: dryRunParams, Status: http.StatusNoContent},
+
+	{Method: http.MethodGet, Path: "/api/v1/posts", Tag: "posts", Summary: "List published posts, and the caller's drafts", Auth: authOptional,
+		Query: pageParams, Status: http.StatusOK, Response: pagination.Page[post.PostOutput]{}},
+	{Method: http.MethodGet, Path: "/api/v1/posts/{id}", Tag: "posts", Summary: "Get a post", Auth: authOptional,
+		Status: http.StatusOK, Response: post.PostOutput{}},
+	{Method: http.MethodPost, Path: "/api/v1/posts", Tag: "posts", Summary: "Create a draft post", Auth: authRequired,
+		Request: post.CreatePostInput{}, Status: http.StatusCreated, Response: post.PostOutput{}},
+	{Method: http.MethodPut, Path: "/api/v1/posts/{id}", Tag: "posts", Summary: "Update or publish a post", Auth: authRequired,
+		Request: post.UpdatePostInput{}, Status: http.StatusOK, Response: post.PostOutput{}},
+	{Method: http.MethodDelete, Path: "/api/v1/posts/{id}", Tag: "posts", Summary: "Delete a post", Auth: authRequired,
+		Query: dryRunParams, Status: http.StatusNoContent},
+
+	{Method: http.MethodGet, Path: "/api/v1/posts/{id}/comments", Tag: "comments", Summary: "List comments on a post", Auth: authOptional,
+		Query: append(pageParams[:len(pageParams):len(pageParams)], apiParam{
+			Name: "status", Description: "Moderators may list other statuses",
+			Schema: map[string]any{"type": "string", "enum": []string{"pending", "approved", "spam"}, "default": "approved"},
+		}), Status: http.StatusOK, Response: pagination.Page[comment.CommentOutput]{}},
+	{Method: http.MethodPost, Path: "/api/v1/posts/{id}/comments", Tag: "comments", Summary: "Comment on a post", Auth: authRequired,
+		Request: comment.CreateCommentInput{}, Status: http.StatusCreated, Response: comment.CommentOutput{}},
+	{Method: http.MethodPut, Path: "/api/v1/posts/{id}/comments/{commentID}/status", Tag: "comments", Summary: "Moderate a comment", Auth: authRequired,
+		Request: comment.ModerateCommentInput{}, Status: http.StatusOK, Response: comment.CommentOutput{}},
+	{Method: http.MethodDelete, Path: "/api/v1/posts/{id}/comments/{commentID}", Tag: "comments", Summary: "Delete a comment", Auth: authRequired,
+		Query: dryRunParams, Status: http.StatusNoContent},
+
+	{Method: http.MethodPost, Path: "/api/v1/webhooks", Tag: "webhooks", Summary: "Register a webhook for user events (admins only); the signing secret is only returned here", Auth: authRequired,
+		Request: webhook.CreateWebhookInput{}, Status: http.StatusCreated, Response: webhook.CreatedWebhookOutput{}},
+	{Method: http.MethodGet, Path: "/api/v1/webhooks", Tag: "webhooks", Summary: "List the caller's webhooks, newest first", Auth: authRequired,
+		Query: pageParams, Status: http.StatusOK, Response: pagination.Page[webhook.WebhookOutput]{}},
+	{Method: http.MethodDelete, Path: "/api/v1/webhooks/{id}", Tag: "webhooks", Summary: "Delete a webhook and its delivery log", Auth: authRequired,
+		Query: dryRunParams, Status: http.StatusNoContent},
+	{Method: http.MethodGet, Path: "/api/v1/webhooks/{id}/deliveries", Tag: "webhooks", Summary: "List a webhook's deliveries, newest first, with their last attempt", Auth: authRequired,
+		Query: pageParams, Status: http.StatusOK, Response: pagination.Page[webhook.DeliveryOutput]{}},
+
+	{Method: http.MethodPost, Path: "/api/v1/api-keys", Tag: "api-keys", Summary: "Issue an API key for backend integrations (admins only); the key is only returned here", Auth: authRequired,
+		Request: apikeyapp.CreateAPIKeyInput{}, Status: http.StatusCreated, Response: apikeyapp.CreatedAPIKeyOutput{}},
+	{Method: http.MethodGet, Path: "/api/v1/api-keys", Tag: "api-keys", Summary: "List the caller's API keys, newest first, revoked ones included", Auth: authRequired,
+		Query: pageParams, Status: http.StatusOK, Response: pagination.Page[apikeyapp.APIKeyOutput]{}},
+	{Method: http.MethodDelete, Path: "/api/v1/api-keys/{id}", Tag: "api-keys", Summary: "Revoke an API key", Auth: authRequired,
+		Query: dryRunParams, Status: http.StatusNoContent},
+
+	{Method: http.MethodGet, Path: OpenAPIPath, Tag: "meta", Summary: "This document",
+		Status: http.StatusOK, Response: map[string]any{}},
+}
+
+// newOpenAPIDocument builds the OpenAPI 3 document for ops.
+func newOpenAPIDocument(ops []apiOperation) map[string]any {
+	schemas := make(map[string]any)
+	errorRef := schemaFor(reflect.TypeOf(problem{}), schemas)
+
+	paths := make(map[string]map[string]any)
+	for _, op := range ops {
+		operation := map[string]any{
+			"tags":        []string{op.Tag},
+			"summary":     op.Summary,
+			"operationId": operationID(op),
+		}
+
+		var params []map[string]any
+		for _, segment := range strings.Split(op.Path, "/") {
+			if name, ok := strings.CutPrefix(segment, "{"); ok {
+				params = append(params, map[string]any{
+					"name": strings.TrimSuffix(name, "}"), "in": "path", "required": true,
+					"schema": map[string]any{"type": "string", "format": "uuid"},
+				})
+			}
+		}
+		for _, p := range op.Query {
+			params = append(params, map[string]any{"name": p.Name, "in": "query", "description": p.Description, "schema": p.Schema})
+		}
+		// Writes honour Idempotency-Key, except logins: tokens are never
+		// replayed.
+		if op.Method != http.MethodGet && op.Tag != "auth" {
+			params = append(params, idempotencyParam)
+		}
+		if len(params) > 0 {
+			operation["parameters"] = params
+		}
+
+		if op.Request != nil {
+			operation["requestBody"] = map[string]any{
+				"required": true,
+				"content":  map[string]any{"application/json": map[string]any{"schema": schemaFor(reflect.TypeOf(op.Request), schemas)}},
+			}
+		}
+
+		success := map[string]any{"description": http.StatusText(op.Status)}
+		if op.Response != nil {
+			success["content"] = map[string]any{"application/json": map[string]any{"schema": schemaFor(reflect.TypeOf(op.Response), schemas)}}
+		}
+		operation["responses"] = map[string]any{
+			strconv.Itoa(op.Status): success,
+			"default": map[string]any{
+				"description": "Error",
+				"content":     map[string]any{ProblemContentType: map[string]any{"schema": errorRef}},
+			},
+		}
+
+		var security []map[string][]string
+		switch op.Auth {
+		case authRequired:
+			security = []map[string][]string{{"bearerAuth": {}}}
+		case authOptional:
+			security = []map[string][]string{{}, {"bearerAuth": {}}}
+		}
+		if scope := apiKeyScope(op); scope != "" {
+			security = append(security, map[string][]string{"apiKeyAuth": {}})
+			operation["x-api-key-scope"] = scope
+		}
+		if security != nil {
+			operation["security"] = security
+		}
+
+		if paths[op.Path] == nil {
+			paths[op.Path] = make(map[string]any)
+		}
+		paths[op.Path][strings.ToLower(op.Method)] = operation
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   "User Management API",
+			"version": "1.0.0",
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": schemas,
+			"securitySchemes": map[string]any{
+				"bearerAuth": map[string]any{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+				"apiKeyAuth": map[string]any{
+					"type": "apiKey", "in": "header", "name": "Authorization",
+					"description": "ApiKey <key>, as issued by POST /api/v1/api-keys. A key acts for the admin who issued it, on operations within its scopes (x-api-key-scope) only",
+				},
+			},
+		},
+	}
+}
+
+// apiKeyScope returns the scope an API key needs to call op, as guarded by
+// RequireScope in NewRouter, or "" when keys cannot call it.
+func apiKeyScope(op apiOperation) apikey.Scope {
+	if op.Auth == authNone {
+		return ""
+	}
+	access := "write"
+	if op.Method == http.MethodGet {
+		access = "read"
+	}
+	scope := apikey.Scope(op.Tag + ":" + access)
+	if !slices.Contains(apikey.Scopes, scope) {
+		return ""
+	}
+	return scope
+}
+
+// operationID derives a stable identifier such as "getUsersId".
+func operationID(op apiOperation) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(op.Method))
+	for _, part := range strings.FieldsFunc(strings.TrimPrefix(op.Path, "/api/v1"), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	uuidType = reflect.TypeOf(uuid.UUID{})
+	rawType  = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemaFor returns the JSON schema of t as encoding/json renders it. Named
+// structs are registered in schemas and referenced; generic ones, like
+// pagination.Page, are inlined.
+func schemaFor(t reflect.Type, schemas map[string]any) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case uuidType:
+		return map[string]any{"type": "string", "format": "uuid"}
+	case rawType:
+		return map[string]any{}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": schemaFor(t.Elem(), schemas)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaFor(t.Elem(), schemas)}
+	case reflect.Struct:
+	default:
+		return map[string]any{}
+	}
+
+	name := t.Name()
+	if name == "" || strings.Contains(name, "[") {
+		return structSchema(t, schemas)
+	}
+	name = strings.ToUpper(name[:1]) + name[1:]
+	if _, ok := schemas[name]; !ok {
+		schemas[name] = nil // placeholder against recursive types
+		schemas[name] = structSchema(t, schemas)
+	}
+	return map[string]any{"$ref": "#/components/schemas/" + name}
+}
+
+// structSchema describes a struct's JSON fields. Validation tags become
+// constraints, and a field is required when validation demands it or, for
+// responses, when it is never omitted.
+func structSchema(t reflect.Type, schemas map[string]any) map[string]any {
+	properties := make(map[string]any)
+	var required []string
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" && field.Anonymous && field.Type.Kind() == reflect.Struct {
+			// Embedded structs contribute their fields, as in encoding/json.
+			embedded := structSchema(field.Type, schemas)
+			for k, v := range embedded["properties"].(map[string]any) {
+				properties[k] = v
+			}
+			if r, ok := embedded["required"].([]string); ok {
+				required = append(required, r...)
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema := schemaFor(field.Type, schemas)
+		rules, validated := field.Tag.Lookup("validate")
+		// Rules after "dive" constrain the items of a slice.
+		target, inItems := schema, false
+		for _, rule := range strings.Split(rules, ",") {
+			key, arg, _ := strings.Cut(rule, "=")
+			switch key {
+			case "dive":
+				if items, ok := schema["items"].(map[string]any); ok {
+					target, inItems = items, true
+				}
+			case "required", "notblank":
+				if !inItems {
+					required = append(required, name)
+				}
+			case "email":
+				target["format"] = "email"
+			case "url":
+				target["format"] = "uri"
+			case "oneof":
+				target["enum"] = strings.Fields(arg)
+			case "min", "max":
+				n, err := strconv.Atoi(arg)
+				if err != nil {
+					continue
+				}
+				switch target["type"] {
+				case "string":
+					target[key+"Length"] = n
+				case "array":
+					target[key+"Items"] = n
+				}
+			}
+		}
+		if !validated && !strings.Contains(opts, "omitempty") && field.Type.Kind() != reflect.Pointer {
+			required = append(required, name)
+		}
+		properties[name] = schema
+	}
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// serveOpenAPI serves the OpenAPI document, built once at startup.
+func serveOpenAPI() http.HandlerFunc {
+	doc, err := json.Marshal(newOpenAPIDocument(apiOperations))
+	if err != nil {
+		panic("failed to encode OpenAPI document: " + err.Error())
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(doc)
+	}
+}
+
+// docsPage renders the OpenAPI document with Swagger UI.
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>User Management API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5.11.0/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5.11.0/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "` + OpenAPIPath + `", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`
+
+// serveDocs serves the Swagger UI page.
+func serveDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(docsPage))
+}
+
+// warnUndocumentedRoutes logs /api/v1 routes missing from apiOperations, so
+// the document can't silently fall behind the router.
+func warnUndocumentedRoutes(r chi.Routes, logger *logger.Logger) {
+	documented := make(map[string]bool, len(apiOperations))
+	for _, op := range apiOperations {
+		documented[op.Method+" "+op.Path] = true
+	}
+
+	chi.Walk(r, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		route = strings.TrimSuffix(strings.ReplaceAll(route, "/*/", "/"), "/")
+		if strings.HasPrefix(route, "/api/v1/") && !documented[method+" "+route] {
+			logger.Warn("route missing from OpenAPI document", zap.String("method", method), zap.String("route", route))
+		}
+		return nil
+	})
+}
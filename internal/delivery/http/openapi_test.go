@@ -0,0 +1,187 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	app "usermanagement/internal/application/user"
+	"usermanagement/internal/infra/logger"
+)
+
+// TestOpenAPIDocumentCoversRouter fails when a /api/v1 route is missing from
+// the served OpenAPI document, or the document describes a route the router
+// does not serve.
+func TestOpenAPIDocumentCoversRouter(t *testing.T) {
+	router := newDocumentedRouter()
+	doc := servedOpenAPIDocument(t, router)
+	paths := doc["paths"].(map[string]any)
+
+	served := make(map[string]bool)
+	err := chi.Walk(router, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		route = strings.TrimSuffix(strings.ReplaceAll(route, "/*/", "/"), "/")
+		if !strings.HasPrefix(route, "/api/v1/") {
+			return nil
+		}
+		served[method+" "+route] = true
+
+		operations, _ := paths[route].(map[string]any)
+		if _, ok := operations[strings.ToLower(method)]; !ok {
+			t.Errorf("%s %s is missing from the OpenAPI document", method, route)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for path, operations := range paths {
+		for method := range operations.(map[string]any) {
+			if !served[strings.ToUpper(method)+" "+path] {
+				t.Errorf("the OpenAPI document describes %s %s, which the router does not serve", strings.ToUpper(method), path)
+			}
+		}
+	}
+}
+
+// TestOpenAPIDocumentCoversDTOs fails when a JSON field of a request or
+// response DTO, or of anything nested in one, is missing from the schema the
+// served OpenAPI document gives it.
+func TestOpenAPIDocumentCoversDTOs(t *testing.T) {
+	doc := servedOpenAPIDocument(t, newDocumentedRouter())
+	paths := doc["paths"].(map[string]any)
+	schemas := doc["components"].(map[string]any)["schemas"].(map[string]any)
+
+	for _, op := range apiOperations {
+		name := op.Method + " " + op.Path
+		operation, ok := paths[op.Path].(map[string]any)[strings.ToLower(op.Method)].(map[string]any)
+		if !ok {
+			t.Errorf("%s is missing from the OpenAPI document", name)
+			continue
+		}
+
+		if op.Request != nil {
+			body, _ := operation["requestBody"].(map[string]any)
+			checkSchema(t, name+" request", reflect.TypeOf(op.Request), jsonSchemaOf(body), schemas, map[reflect.Type]bool{})
+		}
+		if op.Response != nil {
+			responses := operation["responses"].(map[string]any)
+			response, _ := responses[strconv.Itoa(op.Status)].(map[string]any)
+			checkSchema(t, name+" response", reflect.TypeOf(op.Response), jsonSchemaOf(response), schemas, map[reflect.Type]bool{})
+		}
+	}
+}
+
+// newDocumentedRouter builds the router with every optional API enabled.
+// Only its routes are used, so the handlers are left empty.
+func newDocumentedRouter() *chi.Mux {
+	log := &logger.Logger{Logger: zap.NewNop()}
+	return NewRouter(&UserHandler{}, &PostHandler{}, &CommentHandler{}, &AuthHandler{}, &SignupHandler{activateUC: &app.ActivateUserUseCase{}}, &WebhookHandler{}, &APIKeyHandler{},
+		nil, nil, NewDeprecationRegistry(log), RouterOptions{}, log)
+}
+
+// servedOpenAPIDocument fetches the OpenAPI document from router.
+func servedOpenAPIDocument(t *testing.T, router http.Handler) map[string]any {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, OpenAPIPath, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET %s = %d", OpenAPIPath, rec.Code)
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid OpenAPI document: %v", err)
+	}
+	return doc
+}
+
+// jsonSchemaOf returns the application/json schema of a request body or
+// response object, or nil.
+func jsonSchemaOf(object map[string]any) map[string]any {
+	content, _ := object["content"].(map[string]any)
+	media, _ := content["application/json"].(map[string]any)
+	schema, _ := media["schema"].(map[string]any)
+	return schema
+}
+
+// checkSchema reports the JSON fields of t, and of the types nested in it,
+// that schema does not describe. seen guards against recursive types.
+func checkSchema(t *testing.T, where string, typ reflect.Type, schema, schemas map[string]any, seen map[reflect.Type]bool) {
+	t.Helper()
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if schema == nil {
+		t.Errorf("%s: no schema for %s", where, typ)
+		return
+	}
+	if ref, ok := schema["$ref"].(string); ok {
+		schema, _ = schemas[strings.TrimPrefix(ref, "#/components/schemas/")].(map[string]any)
+		if schema == nil {
+			t.Errorf("%s: %s refers to a missing schema", where, ref)
+			return
+		}
+	}
+
+	switch typ {
+	case timeType, uuidType, rawType:
+		return
+	}
+	switch typ.Kind() {
+	case reflect.Slice, reflect.Array:
+		items, _ := schema["items"].(map[string]any)
+		checkSchema(t, where+"[]", typ.Elem(), items, schemas, seen)
+	case reflect.Map:
+		values, _ := schema["additionalProperties"].(map[string]any)
+		checkSchema(t, where+"{}", typ.Elem(), values, schemas, seen)
+	case reflect.Struct:
+		if seen[typ] {
+			return
+		}
+		seen[typ] = true
+		properties, _ := schema["properties"].(map[string]any)
+		for name, field := range dtoFields(typ) {
+			property, ok := properties[name].(map[string]any)
+			if !ok {
+				t.Errorf("%s: field %q of %s is missing from the OpenAPI document", where, name, typ)
+				continue
+			}
+			checkSchema(t, where+"."+name, field, property, schemas, seen)
+		}
+	}
+}
+
+// dtoFields returns the fields encoding/json reads and writes for the
+// struct t, by name, with embedded structs' fields promoted.
+func dtoFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			for k, v := range dtoFields(field.Type) {
+				fields[k] = v
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
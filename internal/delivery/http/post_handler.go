@@ -0,0 +1,132 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"usermanagement/internal/application/pagination"
+	app "usermanagement/internal/application/post"
+	"usermanagement/internal/application/usecase"
+	"usermanagement/internal/domain/errcode"
+	"usermanagement/internal/infra/logger"
+)
+
+// PostHandler handles HTTP requests for blog posts.
+type PostHandler struct {
+	createUC usecase.UseCase[app.CreatePostInput, *app.PostOutput]
+	getUC    usecase.UseCase[uuid.UUID, *app.PostOutput]
+	listUC   usecase.UseCase[pagination.Params, *pagination.Page[app.PostOutput]]
+	updateUC usecase.UseCase[app.UpdatePostInput, *app.PostOutput]
+	deleteUC usecase.Command[uuid.UUID]
+	ids      PublicIDs
+	logger   *logger.Logger
+}
+
+// NewPostHandler creates a new HTTP handler with injected use cases.
+func NewPostHandler(
+	createUC usecase.UseCase[app.CreatePostInput, *app.PostOutput],
+	getUC usecase.UseCase[uuid.UUID, *app.PostOutput],
+	listUC usecase.UseCase[pagination.Params, *pagination.Page[app.PostOutput]],
+	updateUC usecase.UseCase[app.UpdatePostInput, *app.PostOutput],
+	deleteUC usecase.Command[uuid.UUID],
+	ids PublicIDs,
+	logger *logger.Logger,
+) *PostHandler {
+	return &PostHandler{
+		createUC: createUC,
+		getUC:    getUC,
+		listUC:   listUC,
+		updateUC: updateUC,
+		deleteUC: deleteUC,
+		ids:      ids,
+		logger:   logger,
+	}
+}
+
+// Create handles POST /posts.
+func (h *PostHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input app.CreatePostInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, r, http.StatusBadRequest, errcode.InvalidRequest, "invalid request body")
+		return
+	}
+
+	output, err := h.createUC.Execute(r.Context(), input)
+	if err != nil {
+		handleDomainError(w, r, err, h.logger)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, h.ids.post(output))
+}
+
+// GetByID handles GET /posts/{id}.
+func (h *PostHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(h.ids.Posts, chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, errcode.InvalidRequest, "invalid post id format")
+		return
+	}
+
+	output, err := h.getUC.Execute(r.Context(), id)
+	if err != nil {
+		handleDomainError(w, r, err, h.logger)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, h.ids.post(output))
+}
+
+// List handles GET /posts?limit=&offset=.
+func (h *PostHandler) List(w http.ResponseWriter, r *http.Request) {
+	page, err := h.listUC.Execute(r.Context(), parsePagination(r))
+	if err != nil {
+		handleDomainError(w, r, err, h.logger)
+		return
+	}
+
+	respondPage(w, mapPage(page, h.ids.post))
+}
+
+// Update handles PUT /posts/{id}.
+func (h *PostHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(h.ids.Posts, chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, errcode.InvalidRequest, "invalid post id format")
+		return
+	}
+
+	var input app.UpdatePostInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, r, http.StatusBadRequest, errcode.InvalidRequest, "invalid request body")
+		return
+	}
+	input.ID = id
+
+	output, err := h.updateUC.Execute(r.Context(), input)
+	if err != nil {
+		handleDomainError(w, r, err, h.logger)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, h.ids.post(output))
+}
+
+// Delete handles DELETE /posts/{id}.
+func (h *PostHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := parseID(h.ids.Posts, chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, errcode.InvalidRequest, "invalid post id format")
+		return
+	}
+
+	if err := h.deleteUC.Execute(r.Context(), id); err != nil {
+		handleDomainError(w, r, err, h.logger)
+		return
+	}
+
+	respondDeleted(w, r, h.ids, h.logger)
+}
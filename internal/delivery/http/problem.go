@@ -0,0 +1,58 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+
+	"usermanagement/internal/application/validation"
+	"usermanagement/internal/domain/errcode"
+)
+
+// ProblemContentType is the media type of error responses, RFC 7807
+// problem details.
+const ProblemContentType = "application/problem+json"
+
+// problemTypePrefix starts the type URI of every problem; the error code,
+// in kebab case, completes it.
+const problemTypePrefix = "urn:usermanagement:problem:"
+
+// problem is the body of every error answer. Code, RequestID and Fields
+// extend RFC 7807's members.
+type problem struct {
+	Type      string                  `json:"type"`
+	Title     string                  `json:"title"`
+	Status    int                     `json:"status"`
+	Detail    string                  `json:"detail"`
+	Instance  string                  `json:"instance"`
+	Code      errcode.Code            `json:"code"`
+	RequestID string                  `json:"request_id,omitempty"`
+	Fields    []validation.FieldError `json:"fields,omitempty"`
+}
+
+// newProblem describes an error answered to r.
+func newProblem(r *http.Request, status int, code errcode.Code, detail string) problem {
+	return problem{
+		Type:      problemType(code),
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  r.URL.Path,
+		Code:      code,
+		RequestID: middleware.GetReqID(r.Context()),
+	}
+}
+
+// problemType is the type URI of problems with code, so VERSION_CONFLICT
+// becomes urn:usermanagement:problem:version-conflict.
+func problemType(code errcode.Code) string {
+	return problemTypePrefix + strings.ReplaceAll(strings.ToLower(string(code)), "_", "-")
+}
+
+// respondProblem writes p as problem+json.
+func respondProblem(w http.ResponseWriter, p problem) {
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(p.Status)
+	newJSONEncoder(w).Encode(p)
+}
@@ -0,0 +1,114 @@
+package http
+
+import (
+	"github.com/google/uuid"
+
+	"usermanagement/internal/application/comment"
+	"usermanagement/internal/application/pagination"
+	"usermanagement/internal/application/post"
+	"usermanagement/internal/application/usecase"
+	app "usermanagement/internal/application/user"
+)
+
+// IDCodec translates between internal UUIDs and the IDs clients see.
+type IDCodec interface {
+	Encode(id uuid.UUID) string
+	Decode(s string) (uuid.UUID, error)
+}
+
+// PublicIDs translates user and post IDs at the API boundary, in URLs and
+// response bodies, so the application layer keeps working with UUIDs.
+// Comment IDs are always UUIDs.
+type PublicIDs struct {
+	Users IDCodec
+	Posts IDCodec
+}
+
+// parseID decodes an ID from a URL. Raw UUIDs are accepted too, so links
+// made before public IDs were enabled keep working.
+func parseID(codec IDCodec, s string) (uuid.UUID, error) {
+	if id, err := codec.Decode(s); err == nil {
+		return id, nil
+	}
+	return uuid.Parse(s)
+}
+
+// userResponse is a user with its public ID, which shadows the UUID when
+// encoded.
+type userResponse struct {
+	ID string `json:"id"`
+	*app.UserOutput
+}
+
+type postResponse struct {
+	ID       string `json:"id"`
+	AuthorID string `json:"author_id"`
+	*post.PostOutput
+}
+
+type commentResponse struct {
+	PostID   string `json:"post_id"`
+	AuthorID string `json:"author_id"`
+	*comment.CommentOutput
+}
+
+// bulkUsersResponse is a bulk creation with public user IDs.
+type bulkUsersResponse struct {
+	Created int                `json:"created"`
+	Failed  int                `json:"failed"`
+	Results []bulkUserResponse `json:"results"`
+}
+
+type bulkUserResponse struct {
+	Index int                `json:"index"`
+	User  *userResponse      `json:"user,omitempty"`
+	Error *app.BulkUserError `json:"error,omitempty"`
+}
+
+func (p PublicIDs) user(u *app.UserOutput) userResponse {
+	return userResponse{ID: p.Users.Encode(u.ID), UserOutput: u}
+}
+
+func (p PublicIDs) bulkUsers(o *app.BulkCreateUsersOutput) bulkUsersResponse {
+	results := make([]bulkUserResponse, len(o.Results))
+	for i, r := range o.Results {
+		results[i] = bulkUserResponse{Index: r.Index, Error: r.Error}
+		if r.User != nil {
+			u := p.user(r.User)
+			results[i].User = &u
+		}
+	}
+	return bulkUsersResponse{Created: o.Created, Failed: o.Failed, Results: results}
+}
+
+func (p PublicIDs) post(o *post.PostOutput) postResponse {
+	return postResponse{ID: p.Posts.Encode(o.ID), AuthorID: p.Users.Encode(o.AuthorID), PostOutput: o}
+}
+
+func (p PublicIDs) comment(c *comment.CommentOutput) commentResponse {
+	return commentResponse{PostID: p.Posts.Encode(c.PostID), AuthorID: p.Users.Encode(c.AuthorID), CommentOutput: c}
+}
+
+// changes rewrites the IDs of dry-run changes to users and posts.
+func (p PublicIDs) changes(changes []usecase.Change) []usecase.Change {
+	codecs := map[string]IDCodec{"user": p.Users, "post": p.Posts}
+	for i, c := range changes {
+		codec, ok := codecs[c.Resource]
+		if !ok {
+			continue
+		}
+		if id, err := uuid.Parse(c.ID); err == nil {
+			changes[i].ID = codec.Encode(id)
+		}
+	}
+	return changes
+}
+
+// mapPage converts the items of a page for the response.
+func mapPage[T, R any](page *pagination.Page[T], convert func(*T) R) *pagination.Page[R] {
+	items := make([]R, len(page.Items))
+	for i := range page.Items {
+		items[i] = convert(&page.Items[i])
+	}
+	return &pagination.Page[R]{Items: items, Meta: page.Meta}
+}
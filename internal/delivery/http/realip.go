@@ -0,0 +1,99 @@
+package http
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ParseTrustedProxies parses the addresses and CIDR ranges of the reverse
+// proxies whose forwarding headers RealIP believes.
+func ParseTrustedProxies(proxies []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(proxies))
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			addr, err := netip.ParseAddr(proxy)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// RealIP replaces r.RemoteAddr with the client address forwarded by a trusted
+// proxy in X-Forwarded-For or X-Real-IP. Forwarding headers from any other
+// peer are ignored, so clients cannot choose the address per-IP limits see.
+//
+// X-Forwarded-For is read from the right, skipping trusted proxies, since
+// everything left of the first untrusted hop may have been made up.
+func RealIP(trusted []netip.Prefix) func(http.Handler) http.Handler {
+	isTrusted := func(addr netip.Addr) bool {
+		addr = addr.Unmap()
+		for _, prefix := range trusted {
+			if prefix.Contains(addr) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if len(trusted) > 0 {
+				if peer, ok := parseIP(r.RemoteAddr); ok && isTrusted(peer) {
+					if client, ok := forwardedClient(r.Header, isTrusted); ok {
+						r.RemoteAddr = client.String()
+					}
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// forwardedClient returns the client address reported by the proxies in
+// front of a trusted peer.
+func forwardedClient(h http.Header, isTrusted func(netip.Addr) bool) (netip.Addr, bool) {
+	var hops []string
+	for _, value := range h.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(value, ",")...)
+	}
+	if len(hops) > 0 {
+		var client netip.Addr
+		for i := len(hops) - 1; i >= 0; i-- {
+			addr, ok := parseIP(strings.TrimSpace(hops[i]))
+			if !ok {
+				// A hop we cannot read ends what can be believed.
+				break
+			}
+			client = addr
+			if !isTrusted(addr) {
+				break
+			}
+		}
+		return client, client.IsValid()
+	}
+	return parseIP(strings.TrimSpace(h.Get("X-Real-IP")))
+}
+
+// parseIP reads an address with or without a port.
+func parseIP(s string) (netip.Addr, bool) {
+	if host, _, err := net.SplitHostPort(s); err == nil {
+		s = host
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
@@ -0,0 +1,90 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	deliveryhttp "usermanagement/internal/delivery/http"
+)
+
+func TestRealIP(t *testing.T) {
+	trusted, err := deliveryhttp.ParseTrustedProxies([]string{"10.0.0.0/8", "192.0.2.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		peer   string
+		header map[string]string
+		want   string
+	}{
+		{
+			name:   "untrusted peer cannot claim an address",
+			peer:   "203.0.113.7:4711",
+			header: map[string]string{"X-Forwarded-For": "198.51.100.1", "X-Real-IP": "198.51.100.2"},
+			want:   "203.0.113.7:4711",
+		},
+		{
+			name:   "trusted proxy forwards the client",
+			peer:   "10.1.2.3:80",
+			header: map[string]string{"X-Forwarded-For": "198.51.100.1"},
+			want:   "198.51.100.1",
+		},
+		{
+			name:   "spoofed hops left of the client are ignored",
+			peer:   "10.1.2.3:80",
+			header: map[string]string{"X-Forwarded-For": "1.1.1.1, 198.51.100.1, 10.9.9.9"},
+			want:   "198.51.100.1",
+		},
+		{
+			name:   "single trusted address",
+			peer:   "192.0.2.1:80",
+			header: map[string]string{"X-Real-IP": "198.51.100.2"},
+			want:   "198.51.100.2",
+		},
+		{
+			name:   "unreadable forwarded address keeps the peer",
+			peer:   "10.1.2.3:80",
+			header: map[string]string{"X-Forwarded-For": "not-an-ip"},
+			want:   "10.1.2.3:80",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := deliveryhttp.RealIP(trusted)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				got = r.RemoteAddr
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.peer
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if got != tt.want {
+				t.Fatalf("RemoteAddr = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRealIPWithoutTrustedProxies(t *testing.T) {
+	var got string
+	handler := deliveryhttp.RealIP(nil)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got = r.RemoteAddr
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "203.0.113.7:4711"
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if got != "203.0.113.7:4711" {
+		t.Fatalf("RemoteAddr = %q, want the peer address", got)
+	}
+}
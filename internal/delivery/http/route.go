@@ -2,31 +2,82 @@ package http
 
 import (
 	"net/http"
+	"net/netip"
+	"slices"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
 
+	"usermanagement/internal/application/auth"
+	"usermanagement/internal/domain/apikey"
+	"usermanagement/internal/domain/user"
+	"usermanagement/internal/infra/health"
 	"usermanagement/internal/infra/logger"
 )
 
-// NewRouter creates and configures the HTTP router.
-func NewRouter(handler *UserHandler, logger *logger.Logger) *chi.Mux {
+// RouterOptions holds optional router behaviour configured at startup.
+type RouterOptions struct {
+	// FaultRules enables fault injection for resilience testing when non-empty.
+	FaultRules []FaultRule
+	// DebugDump enables request/response body dumping.
+	DebugDump DebugDumpOptions
+	// Origins lists the origins allowed to make cross-origin requests; nil
+	// allows none.
+	Origins *OriginPolicy
+	// RequestTimeout bounds each API request; zero leaves requests unbounded.
+	RequestTimeout time.Duration
+	// InFlight counts requests being served when set.
+	InFlight *InFlightTracker
+	// Readiness serves /ready from its dependency checks when set.
+	Readiness *health.Checker
+	// ClientProfiles are the serialization profiles clients can select
+	// with ClientProfileHeader.
+	ClientProfiles map[string]SerializationProfile
+	// Idempotency replays responses to writes retried with an
+	// Idempotency-Key when set.
+	Idempotency *IdempotencyOptions
+	// TrustedProxies are the reverse proxies whose forwarding headers name
+	// the client address; see RealIP.
+	TrustedProxies []netip.Prefix
+}
+
+// NewRouter creates and configures the HTTP router. A nil webhookHandler
+// leaves out the webhook API.
+func NewRouter(handler *UserHandler, postHandler *PostHandler, commentHandler *CommentHandler, authHandler *AuthHandler, signupHandler *SignupHandler, webhookHandler *WebhookHandler, apiKeyHandler *APIKeyHandler, tokens auth.TokenIssuer, apiKeys auth.APIKeyVerifier, deprecations *DeprecationRegistry, opts RouterOptions, logger *logger.Logger) *chi.Mux {
 	r := chi.NewRouter()
 
 	// Global middleware
+	if opts.InFlight != nil {
+		r.Use(opts.InFlight.Middleware)
+	}
 	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
+	r.Use(RealIP(opts.TrustedProxies))
+	r.Use(LimitBody)
 	r.Use(LoggingMiddleware(logger))
+	if opts.DebugDump.Global || opts.DebugDump.Secret != "" {
+		r.Use(DebugDumpMiddleware(opts.DebugDump, logger))
+	}
 	r.Use(middleware.Recoverer)
+	origins := opts.Origins
+	if origins == nil {
+		origins, _ = NewOriginPolicy(nil)
+	}
 	r.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   []string{"*"},
+		AllowOriginFunc:  origins.Allowed,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", EnvelopeHeader, ClientProfileHeader, IdempotencyHeader, "If-Match", "If-None-Match"},
+		ExposedHeaders:   []string{"Link", "Deprecation", "Sunset", "X-Total-Count", EnvelopeHeader, ClientProfileHeader, IdempotentReplayHeader, "ETag"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
+	if len(opts.FaultRules) > 0 {
+		logger.Warn("fault injection is enabled", zap.Int("rules", len(opts.FaultRules)))
+		r.Use(FaultInjectionMiddleware(opts.FaultRules, logger))
+	}
 
 	// Health check
 	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -34,15 +85,141 @@ func NewRouter(handler *UserHandler, logger *logger.Logger) *chi.Mux {
 		w.Write([]byte(`{"status":"healthy"}`))
 	})
 
+	// Readiness check, for load balancers and orchestrators
+	if opts.Readiness != nil {
+		r.Get("/ready", func(w http.ResponseWriter, r *http.Request) {
+			ready, checks := opts.Readiness.Check(r.Context())
+			status, state := http.StatusOK, "ready"
+			if !ready {
+				status, state = http.StatusServiceUnavailable, "not ready"
+			}
+			respondJSON(w, status, map[string]any{"status": state, "checks": checks})
+		})
+	}
+
+	// Metrics
+	r.Handle("/metrics", promhttp.Handler())
+
+	// API reference, rendering the document served at OpenAPIPath
+	r.Get("/docs", serveDocs)
+
+	// Deprecated routes, so clients can discover upcoming removals
+	r.Get("/api/deprecations", func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(w, http.StatusOK, deprecations.Routes())
+	})
+
 	// API routes
+	// Retire an endpoint by wrapping it with deprecations.Deprecate, e.g.
+	// r.With(deprecations.Deprecate(http.MethodGet, "/api/v1/users/{id}", Deprecation{...})).Get(...)
+	// Reads stop as soon as the client goes away; writes are detached from
+	// the client so a disconnect can't cancel them midway. Both are bounded
+	// by the request timeout.
+	read, write := chi.Chain(), chi.Chain(Detach)
+	if opts.RequestTimeout > 0 {
+		read = append(read, RouteTimeout(opts.RequestTimeout))
+		write = append(write, RouteTimeout(opts.RequestTimeout))
+	}
+	// Token responses are never stored for replay, so logins skip
+	// idempotency.
+	login := slices.Clip(write)
+	if opts.Idempotency != nil {
+		write = append(login, Idempotency(*opts.Idempotency, logger))
+	}
+
 	r.Route("/api/v1", func(r chi.Router) {
+		r.Use(ResponseEnvelope)
+		// v1 is snake_case with RFC 3339 timestamps unless a client
+		// profile says otherwise.
+		r.Use(ClientProfiles(opts.ClientProfiles, SerializationProfile{}))
+		r.Use(TimestampHints)
+		r.Use(DryRun)
+
+		r.Get("/openapi.json", serveOpenAPI())
+
+		r.Route("/auth", func(r chi.Router) {
+			r.With(login...).Post("/login", authHandler.Login)
+			r.With(login...).Post("/refresh", authHandler.Refresh)
+		})
+
+		// Public self-registration, hardened against automated signups
+		r.With(write...).Post("/signup", signupHandler.Signup)
+		if signupHandler.activateUC != nil {
+			r.With(write...).Post("/signup/activate", signupHandler.Activate)
+		}
+
+		// The public way in is /signup; creating users directly is for
+		// admins, since it skips the signup abuse checks.
 		r.Route("/users", func(r chi.Router) {
-			r.Post("/", handler.Create)
-			r.Get("/{id}", handler.GetByID)
-			r.Put("/{id}", handler.Update)
-			r.Delete("/{id}", handler.Delete)
+			r.Group(func(r chi.Router) {
+				r.Use(AuthenticateMiddleware(tokens, apiKeys, logger))
+				r.Use(RequireScope(apikey.ScopeUsersRead, apikey.ScopeUsersWrite))
+				r.With(RequireRole(user.RoleAdmin)).With(write...).Post("/", handler.Create)
+				r.With(RequireRole(user.RoleAdmin)).With(write...).Post("/create-or-get", handler.CreateOrGet)
+				r.With(read...).Get("/", handler.List)
+				r.With(read...).Get("/search", handler.Search)
+				r.With(read...).Get("/{id}", handler.GetByID)
+				r.With(write...).Put("/{id}", handler.Update)
+				r.With(RequireRole(user.RoleAdmin)).With(write...).Post("/bulk", handler.BulkCreate)
+				r.With(RequireRole(user.RoleAdmin)).With(write...).Delete("/{id}", handler.Delete)
+			})
+		})
+
+		r.Route("/posts", func(r chi.Router) {
+			// Published posts are public; drafts show up for their author.
+			r.Group(func(r chi.Router) {
+				r.Use(OptionalAuthenticateMiddleware(tokens, apiKeys, logger))
+				r.Use(RequireScope(apikey.ScopePostsRead, apikey.ScopePostsWrite))
+				r.With(read...).Get("/", postHandler.List)
+				r.With(read...).Get("/{id}", postHandler.GetByID)
+			})
+
+			r.Group(func(r chi.Router) {
+				r.Use(AuthenticateMiddleware(tokens, apiKeys, logger))
+				r.Use(RequireScope(apikey.ScopePostsRead, apikey.ScopePostsWrite))
+				r.With(write...).Post("/", postHandler.Create)
+				r.With(write...).Put("/{id}", postHandler.Update)
+				r.With(write...).Delete("/{id}", postHandler.Delete)
+			})
+
+			// Approved comments are public; moderation queues are not.
+			r.Route("/{id}/comments", func(r chi.Router) {
+				commentScopes := RequireScope(apikey.ScopeCommentsRead, apikey.ScopeCommentsWrite)
+				r.With(OptionalAuthenticateMiddleware(tokens, apiKeys, logger), commentScopes).With(read...).Get("/", commentHandler.List)
+
+				r.Group(func(r chi.Router) {
+					r.Use(AuthenticateMiddleware(tokens, apiKeys, logger))
+					r.Use(commentScopes)
+					r.With(write...).Post("/", commentHandler.Create)
+					r.With(write...).Put("/{commentID}/status", commentHandler.Moderate)
+					r.With(write...).Delete("/{commentID}", commentHandler.Delete)
+				})
+			})
+		})
+
+		// Events carry every user's profile, so webhooks are for admins.
+		if webhookHandler != nil {
+			r.Route("/webhooks", func(r chi.Router) {
+				r.Use(AuthenticateMiddleware(tokens, apiKeys, logger))
+				r.Use(RequireRole(user.RoleAdmin))
+				r.Use(RequireScope(apikey.ScopeWebhooksRead, apikey.ScopeWebhooksWrite))
+				r.With(write...).Post("/", webhookHandler.Create)
+				r.With(read...).Get("/", webhookHandler.List)
+				r.With(write...).Delete("/{id}", webhookHandler.Delete)
+				r.With(read...).Get("/{id}/deliveries", webhookHandler.Deliveries)
+			})
+		}
+
+		// API keys are issued by admins for backend integrations; keys
+		// themselves cannot manage keys.
+		r.Route("/api-keys", func(r chi.Router) {
+			r.Use(AuthenticateMiddleware(tokens, apiKeys, logger))
+			r.Use(RequireRole(user.RoleAdmin))
+			r.With(write...).Post("/", apiKeyHandler.Create)
+			r.With(read...).Get("/", apiKeyHandler.List)
+			r.With(write...).Delete("/{id}", apiKeyHandler.Revoke)
 		})
 	})
 
+	warnUndocumentedRoutes(r, logger)
 	return r
-}
\ No newline at end of file
+}
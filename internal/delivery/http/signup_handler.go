@@ -0,0 +1,65 @@
+package http
+
+import (
+	"net"
+	"net/http"
+
+	"usermanagement/internal/application/usecase"
+	app "usermanagement/internal/application/user"
+	"usermanagement/internal/infra/logger"
+)
+
+// SignupHandler handles public self-registration.
+type SignupHandler struct {
+	signupUC usecase.UseCase[app.SignupInput, *app.UserOutput]
+	// activateUC is nil when signups are active at once.
+	activateUC usecase.UseCase[app.ActivateUserInput, *app.UserOutput]
+	ids        PublicIDs
+	logger     *logger.Logger
+}
+
+// NewSignupHandler creates a new HTTP handler with injected use cases.
+// activateUC may be nil, when signups need no activation.
+func NewSignupHandler(signupUC usecase.UseCase[app.SignupInput, *app.UserOutput], activateUC usecase.UseCase[app.ActivateUserInput, *app.UserOutput], ids PublicIDs, logger *logger.Logger) *SignupHandler {
+	return &SignupHandler{signupUC: signupUC, activateUC: activateUC, ids: ids, logger: logger}
+}
+
+// Signup handles POST /signup.
+func (h *SignupHandler) Signup(w http.ResponseWriter, r *http.Request) {
+	var input app.SignupInput
+	if err := decodeStrict(r.Body, &input); err != nil {
+		respondBodyError(w, r, err)
+		return
+	}
+	// RemoteAddr is the peer address, or the client address a trusted
+	// proxy forwarded (see RealIP); never a header the client chose.
+	input.RemoteIP = r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		input.RemoteIP = host
+	}
+
+	output, err := h.signupUC.Execute(r.Context(), input)
+	if err != nil {
+		handleDomainError(w, r, err, h.logger)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, h.ids.user(output))
+}
+
+// Activate handles POST /signup/activate.
+func (h *SignupHandler) Activate(w http.ResponseWriter, r *http.Request) {
+	var input app.ActivateUserInput
+	if err := decodeStrict(r.Body, &input); err != nil {
+		respondBodyError(w, r, err)
+		return
+	}
+
+	output, err := h.activateUC.Execute(r.Context(), input)
+	if err != nil {
+		handleDomainError(w, r, err, h.logger)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, h.ids.user(output))
+}
@@ -0,0 +1,87 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // time zones for ?tz= on hosts without a zoneinfo database
+
+	"usermanagement/internal/domain/errcode"
+)
+
+// TimestampHints adds formatting hints next to every timestamp in JSON
+// responses when the client opts in with ?time_hints=true, so thin clients
+// can show dates without a date library. A "created_at" field gains
+// "created_at_epoch" (Unix seconds) and, when ?tz= names an IANA time zone,
+// "created_at_local" (RFC 3339 in that zone). Users have no stored time
+// zone, so the client supplies it.
+func TimestampHints(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if enabled, _ := strconv.ParseBool(query.Get("time_hints")); !enabled {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var loc *time.Location
+		if tz := query.Get("tz"); tz != "" {
+			var err error
+			if loc, err = time.LoadLocation(tz); err != nil {
+				respondError(w, r, http.StatusBadRequest, errcode.InvalidRequest, "invalid tz: expected an IANA time zone such as Europe/Berlin")
+				return
+			}
+		}
+
+		bw := &bufferedWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(bw, r)
+
+		body := bw.body.Bytes()
+		var payload any
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+		if !isJSON(w.Header()) || len(body) == 0 || dec.Decode(&payload) != nil {
+			w.WriteHeader(bw.status)
+			w.Write(body)
+			return
+		}
+
+		addTimeHints(payload, loc)
+		w.Header().Del("Content-Length")
+		w.WriteHeader(bw.status)
+		newJSONEncoder(w).Encode(payload)
+	})
+}
+
+// addTimeHints walks a decoded JSON value and adds hints next to every
+// "*_at" field holding an RFC 3339 timestamp.
+func addTimeHints(v any, loc *time.Location) {
+	switch t := v.(type) {
+	case map[string]any:
+		hints := make(map[string]any)
+		for key, val := range t {
+			s, ok := val.(string)
+			if !ok || !strings.HasSuffix(key, "_at") {
+				addTimeHints(val, loc)
+				continue
+			}
+			ts, err := time.Parse(time.RFC3339Nano, s)
+			if err != nil {
+				continue
+			}
+			hints[key+"_epoch"] = ts.Unix()
+			if loc != nil {
+				hints[key+"_local"] = ts.In(loc).Format(time.RFC3339)
+			}
+		}
+		for key, val := range hints {
+			t[key] = val
+		}
+	case []any:
+		for _, item := range t {
+			addTimeHints(item, loc)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// RouteTimeout bounds a route's handling by d. The deadline travels with the
+// request context into the use cases and repositories, which abort their
+// queries once it passes.
+func RouteTimeout(d time.Duration) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Detach keeps a request's work running when the client disconnects, so a
+// dropped connection cannot abandon a write halfway. Request-scoped values
+// are kept. It also drops any deadline, so place RouteTimeout after it.
+func Detach(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithoutCancel(r.Context())))
+	})
+}
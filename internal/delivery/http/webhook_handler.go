@@ -0,0 +1,109 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"usermanagement/internal/application/pagination"
+	"usermanagement/internal/application/usecase"
+	app "usermanagement/internal/application/webhook"
+	"usermanagement/internal/domain/errcode"
+	"usermanagement/internal/infra/logger"
+)
+
+// WebhookHandler handles HTTP requests for webhook subscriptions.
+type WebhookHandler struct {
+	createUC     usecase.UseCase[app.CreateWebhookInput, *app.CreatedWebhookOutput]
+	listUC       usecase.UseCase[pagination.Params, *pagination.Page[app.WebhookOutput]]
+	deleteUC     usecase.Command[uuid.UUID]
+	deliveriesUC usecase.UseCase[app.ListDeliveriesInput, *pagination.Page[app.DeliveryOutput]]
+	ids          PublicIDs
+	logger       *logger.Logger
+}
+
+// NewWebhookHandler creates a new HTTP handler with injected use cases.
+func NewWebhookHandler(
+	createUC usecase.UseCase[app.CreateWebhookInput, *app.CreatedWebhookOutput],
+	listUC usecase.UseCase[pagination.Params, *pagination.Page[app.WebhookOutput]],
+	deleteUC usecase.Command[uuid.UUID],
+	deliveriesUC usecase.UseCase[app.ListDeliveriesInput, *pagination.Page[app.DeliveryOutput]],
+	ids PublicIDs,
+	logger *logger.Logger,
+) *WebhookHandler {
+	return &WebhookHandler{
+		createUC:     createUC,
+		listUC:       listUC,
+		deleteUC:     deleteUC,
+		deliveriesUC: deliveriesUC,
+		ids:          ids,
+		logger:       logger,
+	}
+}
+
+// Create handles POST /webhooks. The response holds the signing secret,
+// which is not shown again.
+func (h *WebhookHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var input app.CreateWebhookInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, r, http.StatusBadRequest, errcode.InvalidRequest, "invalid request body")
+		return
+	}
+
+	output, err := h.createUC.Execute(r.Context(), input)
+	if err != nil {
+		handleDomainError(w, r, err, h.logger)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, output)
+}
+
+// List handles GET /webhooks?limit=&offset=.
+func (h *WebhookHandler) List(w http.ResponseWriter, r *http.Request) {
+	page, err := h.listUC.Execute(r.Context(), parsePagination(r))
+	if err != nil {
+		handleDomainError(w, r, err, h.logger)
+		return
+	}
+
+	respondPage(w, page)
+}
+
+// Delete handles DELETE /webhooks/{id}.
+func (h *WebhookHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, errcode.InvalidRequest, "invalid webhook id format")
+		return
+	}
+
+	if err := h.deleteUC.Execute(r.Context(), id); err != nil {
+		handleDomainError(w, r, err, h.logger)
+		return
+	}
+
+	respondDeleted(w, r, h.ids, h.logger)
+}
+
+// Deliveries handles GET /webhooks/{id}/deliveries?limit=&offset=.
+func (h *WebhookHandler) Deliveries(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, errcode.InvalidRequest, "invalid webhook id format")
+		return
+	}
+
+	page, err := h.deliveriesUC.Execute(r.Context(), app.ListDeliveriesInput{
+		WebhookID: id,
+		Page:      parsePagination(r),
+	})
+	if err != nil {
+		handleDomainError(w, r, err, h.logger)
+		return
+	}
+
+	respondPage(w, page)
+}
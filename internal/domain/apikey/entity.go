@@ -0,0 +1,164 @@
+// Package apikey models the keys backend integrations authenticate with
+// instead of user tokens. A key acts for the admin who issued it, limited to
+// the scopes it was issued with.
+package apikey
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"usermanagement/internal/domain/errcode"
+)
+
+// Scope grants a key read or write access to one resource.
+type Scope string
+
+// Scopes keys may be issued with. Reads are GET requests; writes are
+// everything else.
+const (
+	ScopeUsersRead     Scope = "users:read"
+	ScopeUsersWrite    Scope = "users:write"
+	ScopePostsRead     Scope = "posts:read"
+	ScopePostsWrite    Scope = "posts:write"
+	ScopeCommentsRead  Scope = "comments:read"
+	ScopeCommentsWrite Scope = "comments:write"
+	ScopeWebhooksRead  Scope = "webhooks:read"
+	ScopeWebhooksWrite Scope = "webhooks:write"
+)
+
+// Scopes lists every scope, in documentation order.
+var Scopes = []Scope{
+	ScopeUsersRead, ScopeUsersWrite,
+	ScopePostsRead, ScopePostsWrite,
+	ScopeCommentsRead, ScopeCommentsWrite,
+	ScopeWebhooksRead, ScopeWebhooksWrite,
+}
+
+// Key is an issued API key. Only a hash of its secret is kept: the key
+// itself is shown once, when it is issued.
+type Key struct {
+	id     uuid.UUID
+	owner  uuid.UUID
+	name   string
+	prefix string
+	hash   []byte
+	scopes []Scope
+	// createdAt and revokedAt bound the key's validity; revokedAt is nil
+	// while it is valid.
+	createdAt time.Time
+	revokedAt *time.Time
+}
+
+// Domain errors
+var (
+	ErrInvalidName   = errcode.New(errcode.ValidationFailed, "name must be 1 to 100 characters")
+	ErrInvalidScopes = errcode.New(errcode.ValidationFailed, "scopes must list one or more known scopes")
+	ErrKeyNotFound   = errcode.New(errcode.APIKeyNotFound, "API key not found")
+)
+
+// New creates a key issued by owner. prefix identifies the key in lookups
+// and listings; hash is the hash of its secret.
+func New(id, owner uuid.UUID, name, prefix string, hash []byte, scopes []Scope) (*Key, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > 100 {
+		return nil, ErrInvalidName
+	}
+
+	if len(scopes) == 0 {
+		return nil, ErrInvalidScopes
+	}
+	selected := make([]Scope, 0, len(scopes))
+	for _, s := range scopes {
+		if !slices.Contains(Scopes, s) {
+			return nil, ErrInvalidScopes
+		}
+		if !slices.Contains(selected, s) {
+			selected = append(selected, s)
+		}
+	}
+
+	return &Key{
+		id:        id,
+		owner:     owner,
+		name:      name,
+		prefix:    prefix,
+		hash:      hash,
+		scopes:    selected,
+		createdAt: time.Now().UTC(),
+	}, nil
+}
+
+// Reconstruct rebuilds a Key from the persistence layer without validation.
+func Reconstruct(id, owner uuid.UUID, name, prefix string, hash []byte, scopes []Scope, createdAt time.Time, revokedAt *time.Time) *Key {
+	return &Key{
+		id:        id,
+		owner:     owner,
+		name:      name,
+		prefix:    prefix,
+		hash:      hash,
+		scopes:    scopes,
+		createdAt: createdAt,
+		revokedAt: revokedAt,
+	}
+}
+
+// Allows reports whether the key was issued with scope.
+func (k *Key) Allows(scope Scope) bool {
+	return slices.Contains(k.scopes, scope)
+}
+
+// Revoke invalidates the key. Revoking it again keeps the first time.
+func (k *Key) Revoke() {
+	if k.revokedAt == nil {
+		now := time.Now().UTC()
+		k.revokedAt = &now
+	}
+}
+
+// Revoked reports whether the key was revoked.
+func (k *Key) Revoked() bool {
+	return k.revokedAt != nil
+}
+
+// ID returns the key's unique identifier.
+func (k *Key) ID() uuid.UUID {
+	return k.id
+}
+
+// OwnerID returns the ID of the admin the key acts for.
+func (k *Key) OwnerID() uuid.UUID {
+	return k.owner
+}
+
+// Name returns the label the key was issued with.
+func (k *Key) Name() string {
+	return k.name
+}
+
+// Prefix returns the public part of the key.
+func (k *Key) Prefix() string {
+	return k.prefix
+}
+
+// Hash returns the hash of the key's secret.
+func (k *Key) Hash() []byte {
+	return k.hash
+}
+
+// Scopes returns the scopes the key was issued with.
+func (k *Key) Scopes() []Scope {
+	return k.scopes
+}
+
+// CreatedAt returns the creation timestamp.
+func (k *Key) CreatedAt() time.Time {
+	return k.createdAt
+}
+
+// RevokedAt returns when the key was revoked, or nil.
+func (k *Key) RevokedAt() *time.Time {
+	return k.revokedAt
+}
@@ -0,0 +1,8 @@
+package apikey
+
+import "usermanagement/internal/domain/errcode"
+
+// Repository errors for infrastructure to use
+var (
+	ErrRepositoryInternal = errcode.New(errcode.Internal, "internal repository error")
+)
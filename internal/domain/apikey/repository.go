@@ -0,0 +1,28 @@
+package apikey
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Repository defines the contract for API key persistence.
+type Repository interface {
+	// Save persists a new key.
+	Save(ctx context.Context, k *Key) error
+
+	// FindByID retrieves a key by its unique ID.
+	FindByID(ctx context.Context, id uuid.UUID) (*Key, error)
+
+	// FindByPrefix retrieves a key by its public prefix.
+	FindByPrefix(ctx context.Context, prefix string) (*Key, error)
+
+	// FindByOwner retrieves paginated keys of a user, newest first.
+	FindByOwner(ctx context.Context, ownerID uuid.UUID, limit, offset int) ([]*Key, error)
+
+	// CountByOwner returns the number of keys of a user.
+	CountByOwner(ctx context.Context, ownerID uuid.UUID) (int64, error)
+
+	// Revoke stores the revocation of a key.
+	Revoke(ctx context.Context, k *Key) error
+}
@@ -0,0 +1,141 @@
+package comment
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"usermanagement/internal/domain/errcode"
+)
+
+// Status is the moderation state of a comment.
+type Status string
+
+// Comment statuses. Only approved comments are shown to readers.
+const (
+	StatusPending  Status = "pending"
+	StatusApproved Status = "approved"
+	StatusSpam     Status = "spam"
+)
+
+// Comment is a reader's response to a post, optionally replying to another
+// comment on the same post.
+type Comment struct {
+	id        uuid.UUID
+	postID    uuid.UUID
+	authorID  uuid.UUID
+	parentID  *uuid.UUID
+	body      string
+	status    Status
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+// Domain errors
+var (
+	ErrEmptyBody       = errcode.New(errcode.ValidationFailed, "comment body cannot be empty")
+	ErrInvalidStatus   = errcode.New(errcode.ValidationFailed, "status must be pending, approved or spam")
+	ErrInvalidParent   = errcode.New(errcode.ValidationFailed, "parent comment must belong to the same post")
+	ErrCommentNotFound = errcode.New(errcode.CommentNotFound, "comment not found")
+)
+
+// New creates a pending comment on a post. parentID is nil for top-level
+// comments.
+func New(id, postID, authorID uuid.UUID, parentID *uuid.UUID, body string) (*Comment, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, ErrEmptyBody
+	}
+
+	now := time.Now().UTC()
+	return &Comment{
+		id:        id,
+		postID:    postID,
+		authorID:  authorID,
+		parentID:  parentID,
+		body:      body,
+		status:    StatusPending,
+		createdAt: now,
+		updatedAt: now,
+	}, nil
+}
+
+// Reconstruct rebuilds a Comment from the persistence layer without validation.
+func Reconstruct(id, postID, authorID uuid.UUID, parentID *uuid.UUID, body string, status Status, createdAt, updatedAt time.Time) *Comment {
+	return &Comment{
+		id:        id,
+		postID:    postID,
+		authorID:  authorID,
+		parentID:  parentID,
+		body:      body,
+		status:    status,
+		createdAt: createdAt,
+		updatedAt: updatedAt,
+	}
+}
+
+// ParseStatus converts a string to a Status.
+func ParseStatus(s string) (Status, error) {
+	switch status := Status(s); status {
+	case StatusPending, StatusApproved, StatusSpam:
+		return status, nil
+	default:
+		return "", ErrInvalidStatus
+	}
+}
+
+// Moderate moves the comment to the given moderation state.
+func (c *Comment) Moderate(status Status) error {
+	if _, err := ParseStatus(string(status)); err != nil {
+		return err
+	}
+	c.status = status
+	c.updatedAt = time.Now().UTC()
+	return nil
+}
+
+// IsApproved reports whether the comment is visible to readers.
+func (c *Comment) IsApproved() bool {
+	return c.status == StatusApproved
+}
+
+// ID returns the comment's unique identifier.
+func (c *Comment) ID() uuid.UUID {
+	return c.id
+}
+
+// PostID returns the ID of the post the comment belongs to.
+func (c *Comment) PostID() uuid.UUID {
+	return c.postID
+}
+
+// AuthorID returns the ID of the user who wrote the comment.
+func (c *Comment) AuthorID() uuid.UUID {
+	return c.authorID
+}
+
+// ParentID returns the ID of the comment this one replies to, or nil.
+func (c *Comment) ParentID() *uuid.UUID {
+	return c.parentID
+}
+
+// Body returns the comment's text.
+func (c *Comment) Body() string {
+	return c.body
+}
+
+// Status returns the comment's moderation state.
+func (c *Comment) Status() Status {
+	return c.status
+}
+
+// CreatedAt returns the creation timestamp.
+func (c *Comment) CreatedAt() time.Time {
+	return c.createdAt
+}
+
+// UpdatedAt returns the last update timestamp.
+func (c *Comment) UpdatedAt() time.Time {
+	return c.updatedAt
+}
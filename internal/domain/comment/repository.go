@@ -0,0 +1,33 @@
+package comment
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// CommentRepository defines the contract for comment persistence.
+type CommentRepository interface {
+	// Save persists a new comment.
+	Save(ctx context.Context, comment *Comment) error
+
+	// FindByID retrieves a comment by its unique ID.
+	FindByID(ctx context.Context, id uuid.UUID) (*Comment, error)
+
+	// FindByPost retrieves paginated comments on a post in the given status,
+	// oldest first so threads read in order.
+	FindByPost(ctx context.Context, postID uuid.UUID, status Status, limit, offset int) ([]*Comment, error)
+
+	// CountByPost returns the number of comments on a post in the given status.
+	CountByPost(ctx context.Context, postID uuid.UUID, status Status) (int64, error)
+
+	// CountByStatus returns the number of comments in the given status
+	// across all posts.
+	CountByStatus(ctx context.Context, status Status) (int64, error)
+
+	// Update modifies an existing comment.
+	Update(ctx context.Context, comment *Comment) error
+
+	// Delete removes a comment and its replies.
+	Delete(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,68 @@
+package errcode
+
+import "errors"
+
+// Code is a stable, machine-readable error identifier returned to clients.
+// Codes are part of the public API: never rename or reuse one.
+type Code string
+
+// Error code catalog.
+const (
+	// Internal is used for any error that carries no code of its own.
+	Internal           Code = "INTERNAL_ERROR"
+	Unavailable        Code = "SERVICE_UNAVAILABLE"
+	DataConflict       Code = "DATA_CONFLICT"
+	InvalidRequest     Code = "INVALID_REQUEST"
+	ValidationFailed   Code = "VALIDATION_FAILED"
+	UserNotFound       Code = "USER_NOT_FOUND"
+	EmailConflict      Code = "EMAIL_CONFLICT"
+	InjectedFault      Code = "INJECTED_FAULT"
+	Timeout            Code = "TIMEOUT"
+	Unauthorized       Code = "UNAUTHORIZED"
+	InvalidCredentials Code = "INVALID_CREDENTIALS"
+	Forbidden          Code = "FORBIDDEN"
+	PostNotFound       Code = "POST_NOT_FOUND"
+	SlugConflict       Code = "SLUG_CONFLICT"
+	CommentNotFound    Code = "COMMENT_NOT_FOUND"
+	ContentRejected    Code = "CONTENT_REJECTED"
+	RateLimited        Code = "RATE_LIMITED"
+	CaptchaFailed      Code = "CAPTCHA_FAILED"
+	WebhookNotFound    Code = "WEBHOOK_NOT_FOUND"
+	APIKeyNotFound     Code = "API_KEY_NOT_FOUND"
+	VersionConflict    Code = "VERSION_CONFLICT"
+	// AccountNotActivated is returned when a user who signed up logs in
+	// before activating their account.
+	AccountNotActivated Code = "ACCOUNT_NOT_ACTIVATED"
+	ActivationInvalid   Code = "ACTIVATION_INVALID"
+	// IdempotencyKeyReused is returned when an Idempotency-Key is sent again
+	// with a different request.
+	IdempotencyKeyReused Code = "IDEMPOTENCY_KEY_REUSED"
+	// IdempotencyKeyInUse is returned while the first request sent with an
+	// Idempotency-Key is still running.
+	IdempotencyKeyInUse Code = "IDEMPOTENCY_KEY_IN_USE"
+)
+
+// Error is a domain error carrying a stable code. Domain packages declare
+// their sentinel errors with New so callers can still use errors.Is.
+type Error struct {
+	Code    Code
+	Message string
+}
+
+// New creates a coded error.
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Of returns the code of the first coded error in err's chain, or Internal.
+func Of(err error) Code {
+	var coded *Error
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+	return Internal
+}
@@ -0,0 +1,76 @@
+// Package moderation defines the content policy port applied to
+// user-supplied text such as names, post titles and comments.
+package moderation
+
+import (
+	"context"
+	"fmt"
+
+	"usermanagement/internal/domain/errcode"
+)
+
+// Field identifies which piece of content is being checked, so policies and
+// reviewers can treat them differently.
+type Field string
+
+// Checked fields.
+const (
+	FieldUserName    Field = "user.name"
+	FieldPostTitle   Field = "post.title"
+	FieldCommentBody Field = "comment.body"
+)
+
+// Action is a policy decision, ordered from most to least permissive.
+type Action int
+
+// Policy actions.
+const (
+	// Allow accepts the content.
+	Allow Action = iota
+	// Flag accepts the content but marks it for human review.
+	Flag
+	// Reject refuses the content.
+	Reject
+)
+
+// Content is a piece of user-supplied text to check.
+type Content struct {
+	Field Field
+	Text  string
+}
+
+// Verdict is a policy's decision on a piece of content.
+type Verdict struct {
+	Action Action
+	// Reason explains the decision to reviewers; it is never shown to the
+	// author.
+	Reason string
+}
+
+// Policy decides whether content is acceptable. Implementations backed by
+// external moderation services choose whether to fail open or closed by
+// returning a verdict or an error when the service is unreachable.
+type Policy interface {
+	Check(ctx context.Context, content Content) (Verdict, error)
+}
+
+// ErrContentRejected is returned when a policy rejects content.
+var ErrContentRejected = errcode.New(errcode.ContentRejected, "content violates the content policy")
+
+// Enforce checks text against p. It returns ErrContentRejected for rejected
+// content and reports whether accepted content was flagged for review.
+func Enforce(ctx context.Context, p Policy, field Field, text string) (flagged bool, err error) {
+	verdict, err := p.Check(ctx, Content{Field: field, Text: text})
+	if err != nil {
+		return false, fmt.Errorf("failed to check %s: %w", field, err)
+	}
+
+	switch verdict.Action {
+	case Reject:
+		return false, ErrContentRejected
+	case Flag:
+		return true, nil
+	default:
+		return false, nil
+	}
+}
@@ -0,0 +1,198 @@
+package post
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"usermanagement/internal/domain/errcode"
+)
+
+// Status is the publication state of a post.
+type Status string
+
+// Post statuses.
+const (
+	StatusDraft     Status = "draft"
+	StatusPublished Status = "published"
+)
+
+// Post represents the aggregate root of the Post domain.
+type Post struct {
+	id          uuid.UUID
+	authorID    uuid.UUID
+	title       string
+	slug        string
+	body        string
+	status      Status
+	createdAt   time.Time
+	updatedAt   time.Time
+	publishedAt *time.Time
+}
+
+// Domain errors
+var (
+	ErrEmptyTitle    = errcode.New(errcode.ValidationFailed, "post title cannot be empty")
+	ErrInvalidSlug   = errcode.New(errcode.ValidationFailed, "slug must contain only lowercase letters, digits and single hyphens")
+	ErrInvalidStatus = errcode.New(errcode.ValidationFailed, "status must be draft or published")
+	ErrPostNotFound  = errcode.New(errcode.PostNotFound, "post not found")
+	ErrSlugExists    = errcode.New(errcode.SlugConflict, "slug already exists")
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// New creates a draft post. An empty slug is derived from the title.
+func New(id, authorID uuid.UUID, title, slug, body string) (*Post, error) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return nil, ErrEmptyTitle
+	}
+	if slug == "" {
+		slug = Slugify(title)
+	}
+	if !slugPattern.MatchString(slug) {
+		return nil, ErrInvalidSlug
+	}
+
+	now := time.Now().UTC()
+	return &Post{
+		id:        id,
+		authorID:  authorID,
+		title:     title,
+		slug:      slug,
+		body:      body,
+		status:    StatusDraft,
+		createdAt: now,
+		updatedAt: now,
+	}, nil
+}
+
+// Reconstruct rebuilds a Post from the persistence layer without validation.
+func Reconstruct(id, authorID uuid.UUID, title, slug, body string, status Status, createdAt, updatedAt time.Time, publishedAt *time.Time) *Post {
+	return &Post{
+		id:          id,
+		authorID:    authorID,
+		title:       title,
+		slug:        slug,
+		body:        body,
+		status:      status,
+		createdAt:   createdAt,
+		updatedAt:   updatedAt,
+		publishedAt: publishedAt,
+	}
+}
+
+// UpdateTitle changes the post's title. The slug is kept so links stay valid.
+func (p *Post) UpdateTitle(title string) error {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return ErrEmptyTitle
+	}
+	p.title = title
+	p.updatedAt = time.Now().UTC()
+	return nil
+}
+
+// UpdateSlug changes the post's slug.
+func (p *Post) UpdateSlug(slug string) error {
+	if !slugPattern.MatchString(slug) {
+		return ErrInvalidSlug
+	}
+	p.slug = slug
+	p.updatedAt = time.Now().UTC()
+	return nil
+}
+
+// UpdateBody replaces the post's body.
+func (p *Post) UpdateBody(body string) {
+	p.body = body
+	p.updatedAt = time.Now().UTC()
+}
+
+// ChangeStatus publishes or unpublishes the post. The first publication time
+// is kept when a post is republished.
+func (p *Post) ChangeStatus(status Status) error {
+	switch status {
+	case StatusDraft, StatusPublished:
+	default:
+		return ErrInvalidStatus
+	}
+
+	now := time.Now().UTC()
+	if status == StatusPublished && p.publishedAt == nil {
+		p.publishedAt = &now
+	}
+	p.status = status
+	p.updatedAt = now
+	return nil
+}
+
+// IsPublished reports whether the post is publicly visible.
+func (p *Post) IsPublished() bool {
+	return p.status == StatusPublished
+}
+
+// ID returns the post's unique identifier.
+func (p *Post) ID() uuid.UUID {
+	return p.id
+}
+
+// AuthorID returns the ID of the user who wrote the post.
+func (p *Post) AuthorID() uuid.UUID {
+	return p.authorID
+}
+
+// Title returns the post's title.
+func (p *Post) Title() string {
+	return p.title
+}
+
+// Slug returns the post's URL slug.
+func (p *Post) Slug() string {
+	return p.slug
+}
+
+// Body returns the post's content.
+func (p *Post) Body() string {
+	return p.body
+}
+
+// Status returns the post's publication state.
+func (p *Post) Status() Status {
+	return p.status
+}
+
+// CreatedAt returns the creation timestamp.
+func (p *Post) CreatedAt() time.Time {
+	return p.createdAt
+}
+
+// UpdatedAt returns the last update timestamp.
+func (p *Post) UpdatedAt() time.Time {
+	return p.updatedAt
+}
+
+// PublishedAt returns when the post was first published, or nil.
+func (p *Post) PublishedAt() *time.Time {
+	return p.publishedAt
+}
+
+// Slugify derives a URL slug from a title, e.g. "Hello, World!" becomes
+// "hello-world".
+func Slugify(title string) string {
+	var sb strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			sb.WriteRune(r)
+			hyphen = false
+		case sb.Len() > 0 && !hyphen:
+			sb.WriteByte('-')
+			hyphen = true
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "-")
+}
@@ -0,0 +1,28 @@
+package post
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// PostRepository defines the contract for post persistence.
+type PostRepository interface {
+	// Save persists a new post.
+	Save(ctx context.Context, post *Post) error
+
+	// FindByID retrieves a post by its unique ID.
+	FindByID(ctx context.Context, id uuid.UUID) (*Post, error)
+
+	// FindByStatus retrieves paginated posts in the given status, newest first.
+	FindByStatus(ctx context.Context, status Status, limit, offset int) ([]*Post, error)
+
+	// CountByStatus returns the number of posts in the given status.
+	CountByStatus(ctx context.Context, status Status) (int64, error)
+
+	// Update modifies an existing post.
+	Update(ctx context.Context, post *Post) error
+
+	// Delete removes a post by ID.
+	Delete(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,32 @@
+// Package transaction defines the unit of work port use cases use to make
+// several repository calls atomic.
+package transaction
+
+import "context"
+
+// UnitOfWork runs use case steps in one database transaction.
+type UnitOfWork interface {
+	// Do calls fn in a transaction that commits when fn returns nil and
+	// rolls back otherwise. Repository calls made with the context passed
+	// to fn join the transaction, and entities they load by ID stay locked
+	// until it ends, so read-modify-write sequences cannot lose updates.
+	// Calling Do again inside fn joins the outer transaction.
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// activeKey marks contexts running inside a unit of work.
+type activeKey struct{}
+
+// WithActive marks ctx as running inside a unit of work. Implementations of
+// UnitOfWork call it on the context they pass to fn.
+func WithActive(ctx context.Context) context.Context {
+	return context.WithValue(ctx, activeKey{}, true)
+}
+
+// Active reports whether ctx runs inside a unit of work. Repository
+// decorators that answer without the database, such as caches, must not do
+// so then: the unit of work relies on reading current, locked rows.
+func Active(ctx context.Context) bool {
+	active, _ := ctx.Value(activeKey{}).(bool)
+	return active
+}
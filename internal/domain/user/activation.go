@@ -0,0 +1,54 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"usermanagement/internal/domain/errcode"
+)
+
+// Activation errors
+var (
+	// ErrNotActivated is returned when a user who signed up logs in before
+	// activating their account.
+	ErrNotActivated = errcode.New(errcode.AccountNotActivated, "account is not activated yet; follow the link sent to your email")
+	// ErrActivationInvalid is returned for activation tokens that are
+	// unknown, already used or expired.
+	ErrActivationInvalid = errcode.New(errcode.ActivationInvalid, "activation token is invalid or expired")
+)
+
+// Activation holds back a user who signed up until they prove they own
+// their email address, by sending back the token mailed to it.
+type Activation struct {
+	UserID    uuid.UUID
+	TokenHash []byte
+	ExpiresAt time.Time
+}
+
+// ActivationRequest is the payload of user.activation_requested events:
+// what a mailer needs to send the activation link. It carries the token in
+// the clear, so only trusted consumers should receive it.
+type ActivationRequest struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ActivationRepository keeps the activations of users who signed up.
+type ActivationRepository interface {
+	// Save records a pending activation together with the event carrying
+	// request to the user, joining the unit of work in ctx.
+	Save(ctx context.Context, activation Activation, request ActivationRequest) error
+
+	// Pending reports whether the user still awaits activation.
+	Pending(ctx context.Context, userID uuid.UUID) (bool, error)
+
+	// Activate completes the activation with tokenHash and returns its
+	// user. It returns ErrActivationInvalid when there is none, or it
+	// expired before now.
+	Activate(ctx context.Context, tokenHash []byte, now time.Time) (uuid.UUID, error)
+}
@@ -1,36 +1,45 @@
 package user
 
 import (
-	"errors"
 	"net/mail"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
+
+	"usermanagement/internal/domain/errcode"
 )
 
 // User represents the aggregate root of the User domain.
 // It encapsulates business invariants and rules.
 type User struct {
-	id        uuid.UUID
-	name      string
-	email     string
-	createdAt time.Time
-	updatedAt time.Time
+	id           uuid.UUID
+	name         string
+	email        string
+	passwordHash string // empty for users without credentials
+	role         Role
+	createdAt    time.Time
+	updatedAt    time.Time
+	// version counts the updates stored; see Version.
+	version int64
 }
 
 // Domain errors - part of the ubiquitous language
 var (
-	ErrEmptyName     = errors.New("user name cannot be empty")
-	ErrInvalidEmail  = errors.New("invalid email format")
-	ErrNilUser       = errors.New("user cannot be nil")
-	ErrUserNotFound  = errors.New("user not found")
-	ErrEmailExists   = errors.New("email already exists")
+	ErrEmptyName    = errcode.New(errcode.ValidationFailed, "user name cannot be empty")
+	ErrInvalidEmail = errcode.New(errcode.ValidationFailed, "invalid email format")
+	ErrNilUser      = errcode.New(errcode.Internal, "user cannot be nil")
+	ErrUserNotFound = errcode.New(errcode.UserNotFound, "user not found")
+	ErrEmailExists  = errcode.New(errcode.EmailConflict, "email already exists")
+	// ErrVersionConflict is returned when a user changed since the version
+	// an update was based on.
+	ErrVersionConflict = errcode.New(errcode.VersionConflict, "user was modified concurrently; reload it and retry")
 )
 
 // New creates a new User with validated invariants.
 // This is the only way to create a valid User entity.
-func New(name, email string) (*User, error) {
+// The ID comes from an IDGenerator.
+func New(id uuid.UUID, name, email string) (*User, error) {
 	if strings.TrimSpace(name) == "" {
 		return nil, ErrEmptyName
 	}
@@ -41,24 +50,29 @@ func New(name, email string) (*User, error) {
 
 	now := time.Now().UTC()
 	return &User{
-		id:        uuid.New(),
+		id:        id,
 		name:      strings.TrimSpace(name),
 		email:     strings.ToLower(strings.TrimSpace(email)),
+		role:      DefaultRole,
 		createdAt: now,
 		updatedAt: now,
+		version:   1,
 	}, nil
 }
 
 // Reconstruct rebuilds a User from persistence layer.
 // Used by repositories when hydrating from database.
 // Does NOT validate - assumes data is already valid from DB.
-func Reconstruct(id uuid.UUID, name, email string, createdAt, updatedAt time.Time) *User {
+func Reconstruct(id uuid.UUID, name, email, passwordHash string, role Role, createdAt, updatedAt time.Time, version int64) *User {
 	return &User{
-		id:        id,
-		name:      name,
-		email:     email,
-		createdAt: createdAt,
-		updatedAt: updatedAt,
+		id:           id,
+		name:         name,
+		email:        email,
+		passwordHash: passwordHash,
+		role:         role,
+		createdAt:    createdAt,
+		updatedAt:    updatedAt,
+		version:      version,
 	}
 }
 
@@ -107,6 +121,27 @@ func (u *User) UpdatedAt() time.Time {
 	return u.updatedAt
 }
 
+// Version returns the version the user was loaded at. New users start at 1
+// and every stored update adds one; repositories refuse updates of a user
+// whose stored version moved on with ErrVersionConflict.
+func (u *User) Version() int64 {
+	return u.version
+}
+
+// CheckVersion returns ErrVersionConflict unless the user is at version.
+func (u *User) CheckVersion(version int64) error {
+	if u.version != version {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// AdvanceVersion moves the user to its next version. Repositories call it
+// once an update of the user was stored.
+func (u *User) AdvanceVersion() {
+	u.version++
+}
+
 func validateEmail(email string) error {
 	if strings.TrimSpace(email) == "" {
 		return ErrInvalidEmail
@@ -116,4 +151,4 @@ func validateEmail(email string) error {
 		return ErrInvalidEmail
 	}
 	return nil
-}
\ No newline at end of file
+}
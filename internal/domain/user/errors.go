@@ -1,9 +1,12 @@
 package user
 
-import "errors"
+import "usermanagement/internal/domain/errcode"
 
 // Repository errors for infrastructure to use
 var (
-	ErrRepositoryConflict = errors.New("data conflict in repository")
-	ErrRepositoryInternal = errors.New("internal repository error")
-)
\ No newline at end of file
+	ErrRepositoryConflict = errcode.New(errcode.DataConflict, "data conflict in repository")
+	ErrRepositoryInternal = errcode.New(errcode.Internal, "internal repository error")
+	// ErrRepositoryUnavailable signals the store is known to be down and the
+	// call was not attempted (e.g. an open circuit breaker).
+	ErrRepositoryUnavailable = errcode.New(errcode.Unavailable, "repository temporarily unavailable")
+)
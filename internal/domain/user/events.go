@@ -0,0 +1,16 @@
+package user
+
+// Topics of the events published when users change. Every event carries
+// the user's ID; created and updated events carry its profile too.
+const (
+	TopicCreated = "user.created"
+	TopicUpdated = "user.updated"
+	TopicDeleted = "user.deleted"
+	// TopicEmailChanged follows an email change made on the user's behalf,
+	// e.g. a domain rewrite, so they can be told at both addresses. It
+	// carries the old and new email.
+	TopicEmailChanged = "user.email_changed"
+	// TopicActivationRequested follows a signup that awaits activation. It
+	// carries an ActivationRequest, token included, for a mailer to send.
+	TopicActivationRequested = "user.activation_requested"
+)
@@ -0,0 +1,11 @@
+package user
+
+import "github.com/google/uuid"
+
+// IDGenerator defines the contract for generating new user IDs.
+// Implementations live in infrastructure so the ID scheme can change without
+// touching the domain.
+type IDGenerator interface {
+	// NewID returns a fresh, unique ID.
+	NewID() (uuid.UUID, error)
+}
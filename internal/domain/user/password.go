@@ -0,0 +1,59 @@
+package user
+
+import (
+	"fmt"
+	"time"
+
+	"usermanagement/internal/domain/errcode"
+)
+
+// Password length limits. The upper bound is bcrypt's input limit; longer
+// passwords would be silently truncated.
+const (
+	MinPasswordLength = 8
+	MaxPasswordLength = 72
+)
+
+// ErrWeakPassword is returned for passwords outside the length limits.
+var ErrWeakPassword = errcode.New(errcode.ValidationFailed,
+	fmt.Sprintf("password must be %d to %d bytes long", MinPasswordLength, MaxPasswordLength))
+
+// PasswordHasher defines the contract for one-way password hashing.
+// Implementations live in infrastructure.
+type PasswordHasher interface {
+	// Hash returns a salted hash of the password.
+	Hash(password string) (string, error)
+
+	// Compare reports whether password matches hash.
+	Compare(hash, password string) bool
+}
+
+// SetPassword replaces the user's credentials with a hash of password.
+func (u *User) SetPassword(hasher PasswordHasher, password string) error {
+	if len(password) < MinPasswordLength || len(password) > MaxPasswordLength {
+		return ErrWeakPassword
+	}
+
+	hash, err := hasher.Hash(password)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+	u.passwordHash = hash
+	u.updatedAt = time.Now().UTC()
+	return nil
+}
+
+// VerifyPassword reports whether password matches the user's credentials.
+// Users without a password never match.
+func (u *User) VerifyPassword(hasher PasswordHasher, password string) bool {
+	if u.passwordHash == "" {
+		return false
+	}
+	return hasher.Compare(u.passwordHash, password)
+}
+
+// PasswordHash returns the stored password hash, empty if none is set.
+// Only persistence should need it.
+func (u *User) PasswordHash() string {
+	return u.passwordHash
+}
@@ -2,29 +2,75 @@ package user
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+// Keyset is a user's position in listing order (newest first, ties broken
+// by ID), used to resume a listing after that user.
+type Keyset struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// Page selects a window of results.
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+// SearchHit is a user matching a search. Higher ranks match better.
+type SearchHit struct {
+	User *User
+	Rank float64
+}
+
+// SearchResult is a page of search hits, best first, and how many users
+// matched in total.
+type SearchResult struct {
+	Hits  []SearchHit
+	Total int64
+}
+
 // UserRepository defines the contract for user persistence.
 // It belongs to the domain layer - implementation details are in infrastructure.
 // This is the OUTPUT PORT in Clean Architecture terminology.
 type UserRepository interface {
 	// Save persists a new user.
 	Save(ctx context.Context, user *User) error
-	
+
+	// SaveBatch persists new users together, skipping those whose email is
+	// taken, and returns the IDs of the users it saved.
+	SaveBatch(ctx context.Context, users []*User) ([]uuid.UUID, error)
+
 	// FindByID retrieves a user by their unique ID.
 	FindByID(ctx context.Context, id uuid.UUID) (*User, error)
-	
+
 	// FindByEmail retrieves a user by email (for uniqueness checks).
 	FindByEmail(ctx context.Context, email string) (*User, error)
-	
-	// FindAll retrieves paginated users.
+
+	// FindAll retrieves paginated users, newest first.
 	FindAll(ctx context.Context, limit, offset int) ([]*User, error)
-	
-	// Update modifies an existing user.
+
+	// FindAfter retrieves up to limit users following after in FindAll's
+	// order; a nil after starts from the newest user.
+	FindAfter(ctx context.Context, after *Keyset, limit int) ([]*User, error)
+
+	// Search finds users whose name or email matches query, best match
+	// first and newest first among equals.
+	Search(ctx context.Context, query string, page Page) (*SearchResult, error)
+
+	// Count returns the total number of users.
+	Count(ctx context.Context) (int64, error)
+
+	// Update modifies an existing user if its stored version is still
+	// user's, then advances user's version. It returns ErrVersionConflict
+	// when another update was stored first.
 	Update(ctx context.Context, user *User) error
-	
-	// Delete removes a user by ID.
-	Delete(ctx context.Context, id uuid.UUID) error
-}
\ No newline at end of file
+
+	// Delete removes a user by ID if its stored version is still version;
+	// a version of 0 removes it at whatever version it is. It returns
+	// ErrVersionConflict when an update was stored since.
+	Delete(ctx context.Context, id uuid.UUID, version int64) error
+}
@@ -0,0 +1,48 @@
+package user
+
+import (
+	"time"
+
+	"usermanagement/internal/domain/errcode"
+)
+
+// Role is a user's authorization level.
+type Role string
+
+// Roles, from most to least privileged.
+const (
+	RoleAdmin  Role = "admin"
+	RoleEditor Role = "editor"
+	RoleViewer Role = "viewer"
+)
+
+// DefaultRole is assigned to newly registered users.
+const DefaultRole = RoleViewer
+
+// ErrInvalidRole is returned for unknown roles.
+var ErrInvalidRole = errcode.New(errcode.ValidationFailed, "role must be admin, editor or viewer")
+
+// ParseRole validates a role name.
+func ParseRole(s string) (Role, error) {
+	switch r := Role(s); r {
+	case RoleAdmin, RoleEditor, RoleViewer:
+		return r, nil
+	default:
+		return "", ErrInvalidRole
+	}
+}
+
+// ChangeRole assigns a new role to the user.
+func (u *User) ChangeRole(role Role) error {
+	if _, err := ParseRole(string(role)); err != nil {
+		return err
+	}
+	u.role = role
+	u.updatedAt = time.Now().UTC()
+	return nil
+}
+
+// Role returns the user's role.
+func (u *User) Role() Role {
+	return u.role
+}
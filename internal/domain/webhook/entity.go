@@ -0,0 +1,140 @@
+package webhook
+
+import (
+	"encoding/json"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"usermanagement/internal/domain/errcode"
+	"usermanagement/internal/domain/user"
+)
+
+// Events lists the event topics subscriptions may select.
+var Events = []string{user.TopicCreated, user.TopicUpdated, user.TopicDeleted, user.TopicEmailChanged, user.TopicActivationRequested}
+
+// Subscription asks for events to be POSTed to an endpoint, signed with its
+// secret.
+type Subscription struct {
+	id        uuid.UUID
+	ownerID   uuid.UUID
+	url       string
+	events    []string
+	secret    string
+	createdAt time.Time
+}
+
+// Domain errors
+var (
+	ErrInvalidURL           = errcode.New(errcode.ValidationFailed, "url must be an absolute http or https URL")
+	ErrInvalidEvents        = errcode.New(errcode.ValidationFailed, "events must list one or more of user.created, user.updated, user.deleted, user.email_changed and user.activation_requested")
+	ErrSubscriptionNotFound = errcode.New(errcode.WebhookNotFound, "webhook not found")
+)
+
+// New creates a subscription of ownerID to events at endpoint.
+func New(id, ownerID uuid.UUID, endpoint string, events []string, secret string) (*Subscription, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.User != nil {
+		return nil, ErrInvalidURL
+	}
+
+	if len(events) == 0 {
+		return nil, ErrInvalidEvents
+	}
+	selected := make([]string, 0, len(events))
+	for _, e := range events {
+		if !slices.Contains(Events, e) {
+			return nil, ErrInvalidEvents
+		}
+		if !slices.Contains(selected, e) {
+			selected = append(selected, e)
+		}
+	}
+
+	return &Subscription{
+		id:        id,
+		ownerID:   ownerID,
+		url:       u.String(),
+		events:    selected,
+		secret:    secret,
+		createdAt: time.Now().UTC(),
+	}, nil
+}
+
+// Reconstruct rebuilds a Subscription from the persistence layer without validation.
+func Reconstruct(id, ownerID uuid.UUID, endpoint string, events []string, secret string, createdAt time.Time) *Subscription {
+	return &Subscription{
+		id:        id,
+		ownerID:   ownerID,
+		url:       endpoint,
+		events:    events,
+		secret:    secret,
+		createdAt: createdAt,
+	}
+}
+
+// Wants reports whether the subscription selected events of topic.
+func (s *Subscription) Wants(topic string) bool {
+	return slices.Contains(s.events, topic)
+}
+
+// ID returns the subscription's unique identifier.
+func (s *Subscription) ID() uuid.UUID {
+	return s.id
+}
+
+// OwnerID returns the ID of the user who registered the subscription.
+func (s *Subscription) OwnerID() uuid.UUID {
+	return s.ownerID
+}
+
+// URL returns the endpoint events are POSTed to.
+func (s *Subscription) URL() string {
+	return s.url
+}
+
+// Events returns the selected event topics.
+func (s *Subscription) Events() []string {
+	return s.events
+}
+
+// Secret returns the key payloads are signed with.
+func (s *Subscription) Secret() string {
+	return s.secret
+}
+
+// CreatedAt returns the creation timestamp.
+func (s *Subscription) CreatedAt() time.Time {
+	return s.createdAt
+}
+
+// DeliveryStatus is the state of a delivery.
+type DeliveryStatus string
+
+// Delivery statuses. Pending deliveries are retried until they succeed or
+// run out of attempts.
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// Delivery records sending one event to one subscription, for debugging
+// endpoints. It is written by the delivery worker only.
+type Delivery struct {
+	ID             uuid.UUID
+	SubscriptionID uuid.UUID
+	EventID        uuid.UUID
+	Topic          string
+	Payload        json.RawMessage
+	Status         DeliveryStatus
+	Attempts       int
+	// LastStatusCode is the endpoint's answer to the last attempt; zero
+	// when it could not be reached.
+	LastStatusCode int
+	LastError      string
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
@@ -0,0 +1,36 @@
+package webhook
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// SubscriptionRepository defines the contract for webhook subscription
+// persistence.
+type SubscriptionRepository interface {
+	// Save persists a new subscription.
+	Save(ctx context.Context, s *Subscription) error
+
+	// FindByID retrieves a subscription by its unique ID.
+	FindByID(ctx context.Context, id uuid.UUID) (*Subscription, error)
+
+	// FindByOwner retrieves paginated subscriptions of a user, newest first.
+	FindByOwner(ctx context.Context, ownerID uuid.UUID, limit, offset int) ([]*Subscription, error)
+
+	// CountByOwner returns the number of subscriptions of a user.
+	CountByOwner(ctx context.Context, ownerID uuid.UUID) (int64, error)
+
+	// Delete removes a subscription and its delivery log.
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// DeliveryRepository defines read access to the delivery log.
+type DeliveryRepository interface {
+	// FindBySubscription retrieves paginated deliveries of a subscription,
+	// newest first.
+	FindBySubscription(ctx context.Context, subscriptionID uuid.UUID, limit, offset int) ([]*Delivery, error)
+
+	// CountBySubscription returns the number of deliveries of a subscription.
+	CountBySubscription(ctx context.Context, subscriptionID uuid.UUID) (int64, error)
+}
@@ -0,0 +1,134 @@
+package auth
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	appauth "usermanagement/internal/application/auth"
+	"usermanagement/internal/domain/user"
+)
+
+// minSecretLen is the shortest accepted HMAC secret (256 bits).
+const minSecretLen = 32
+
+// Token types, stored in the "typ" claim so a refresh token can never be
+// used as an access token and vice versa.
+const (
+	accessToken  = "access"
+	refreshToken = "refresh"
+)
+
+type claims struct {
+	Type string `json:"typ"`
+	// Role is only set on access tokens; refresh re-reads the current role.
+	Role string `json:"role,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// JWTIssuer implements the application TokenIssuer with HS256-signed JWTs.
+type JWTIssuer struct {
+	secret     []byte
+	issuer     string
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+// NewJWTIssuer creates an issuer signing with secret.
+func NewJWTIssuer(secret, issuer string, accessTTL, refreshTTL time.Duration) (*JWTIssuer, error) {
+	if len(secret) < minSecretLen {
+		return nil, fmt.Errorf("jwt secret must be at least %d bytes", minSecretLen)
+	}
+	return &JWTIssuer{
+		secret:     []byte(secret),
+		issuer:     issuer,
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+	}, nil
+}
+
+// Issue creates a new access/refresh token pair for the user.
+func (j *JWTIssuer) Issue(userID uuid.UUID, role user.Role) (*appauth.Tokens, error) {
+	now := time.Now()
+	access, err := j.sign(userID, accessToken, string(role), now, j.accessTTL)
+	if err != nil {
+		return nil, err
+	}
+	refresh, err := j.sign(userID, refreshToken, "", now, j.refreshTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &appauth.Tokens{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(j.accessTTL.Seconds()),
+	}, nil
+}
+
+// VerifyAccess returns the caller an access token was issued to.
+func (j *JWTIssuer) VerifyAccess(token string) (appauth.Caller, error) {
+	c, id, err := j.verify(token, accessToken)
+	if err != nil {
+		return appauth.Caller{}, err
+	}
+	role, err := user.ParseRole(c.Role)
+	if err != nil {
+		return appauth.Caller{}, fmt.Errorf("%w: %v", appauth.ErrInvalidToken, err)
+	}
+	return appauth.Caller{UserID: id, Role: role}, nil
+}
+
+// VerifyRefresh returns the user a refresh token was issued to.
+func (j *JWTIssuer) VerifyRefresh(token string) (uuid.UUID, error) {
+	_, id, err := j.verify(token, refreshToken)
+	return id, err
+}
+
+func (j *JWTIssuer) sign(userID uuid.UUID, typ, role string, now time.Time, ttl time.Duration) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims{
+		Type: typ,
+		Role: role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    j.issuer,
+			Subject:   userID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			ID:        uuid.NewString(),
+		},
+	})
+
+	signed, err := token.SignedString(j.secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign %s token: %w", typ, err)
+	}
+	return signed, nil
+}
+
+func (j *JWTIssuer) verify(token, typ string) (*claims, uuid.UUID, error) {
+	var c claims
+	_, err := jwt.ParseWithClaims(token, &c, j.key,
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(j.issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, uuid.Nil, fmt.Errorf("%w: %v", appauth.ErrInvalidToken, err)
+	}
+	if c.Type != typ {
+		return nil, uuid.Nil, fmt.Errorf("%w: got %q token, want %q", appauth.ErrInvalidToken, c.Type, typ)
+	}
+
+	id, err := uuid.Parse(c.Subject)
+	if err != nil {
+		return nil, uuid.Nil, fmt.Errorf("%w: invalid subject: %v", appauth.ErrInvalidToken, err)
+	}
+	return &c, id, nil
+}
+
+func (j *JWTIssuer) key(*jwt.Token) (any, error) {
+	return j.secret, nil
+}
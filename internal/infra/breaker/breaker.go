@@ -0,0 +1,180 @@
+package breaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+
+	"usermanagement/internal/infra/logger"
+)
+
+// ErrOpen is returned without calling the dependency while the circuit is open.
+var ErrOpen = errors.New("circuit breaker is open")
+
+// State is the position of a circuit breaker.
+type State int
+
+const (
+	StateClosed State = iota
+	StateHalfOpen
+	StateOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateHalfOpen:
+		return "half-open"
+	case StateOpen:
+		return "open"
+	default:
+		return "unknown"
+	}
+}
+
+var (
+	stateGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "circuit_breaker_state",
+		Help: "Circuit breaker state (0 closed, 1 half-open, 2 open).",
+	}, []string{"name"})
+	rejectedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "circuit_breaker_rejected_total",
+		Help: "Calls rejected because the circuit breaker was open.",
+	}, []string{"name"})
+)
+
+// Settings configures a Breaker.
+type Settings struct {
+	// FailureThreshold is the number of consecutive failures that opens the circuit.
+	FailureThreshold int
+	// OpenTimeout is how long the circuit stays open before a trial call is let through.
+	OpenTimeout time.Duration
+	// IsFailure decides whether an error counts against the dependency.
+	// Defaults to treating every non-nil error as a failure.
+	IsFailure func(err error) bool
+}
+
+// Breaker is a consecutive-failure circuit breaker, in the spirit of gobreaker.
+type Breaker struct {
+	name     string
+	settings Settings
+	logger   *logger.Logger
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	trial    bool
+}
+
+// New creates a closed breaker for the named dependency.
+func New(name string, settings Settings, logger *logger.Logger) *Breaker {
+	if settings.FailureThreshold <= 0 {
+		settings.FailureThreshold = 5
+	}
+	if settings.OpenTimeout <= 0 {
+		settings.OpenTimeout = 30 * time.Second
+	}
+	if settings.IsFailure == nil {
+		settings.IsFailure = func(err error) bool { return err != nil }
+	}
+
+	stateGauge.WithLabelValues(name).Set(float64(StateClosed))
+	return &Breaker{
+		name:     name,
+		settings: settings,
+		logger:   logger,
+	}
+}
+
+// Execute calls fn unless the circuit is open, recording its outcome.
+func (b *Breaker) Execute(fn func() error) error {
+	if !b.allow() {
+		rejectedTotal.WithLabelValues(b.name).Inc()
+		return ErrOpen
+	}
+
+	err := fn()
+	b.record(b.settings.IsFailure(err))
+	return err
+}
+
+// State returns the current state.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+func (b *Breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if time.Since(b.openedAt) < b.settings.OpenTimeout {
+			return false
+		}
+		b.setState(StateHalfOpen)
+		b.trial = true
+		return true
+	case StateHalfOpen:
+		// Only one trial call at a time while probing the dependency.
+		if b.trial {
+			return false
+		}
+		b.trial = true
+		return true
+	default:
+		return true
+	}
+}
+
+func (b *Breaker) record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateHalfOpen {
+		b.trial = false
+		if failed {
+			b.open()
+		} else {
+			b.failures = 0
+			b.setState(StateClosed)
+		}
+		return
+	}
+
+	if !failed {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.settings.FailureThreshold {
+		b.open()
+	}
+}
+
+func (b *Breaker) open() {
+	b.openedAt = time.Now()
+	b.failures = 0
+	b.setState(StateOpen)
+}
+
+func (b *Breaker) setState(state State) {
+	if b.state == state {
+		return
+	}
+	b.logger.Warn("circuit breaker state changed",
+		zap.String("breaker", b.name),
+		zap.String("from", b.state.String()),
+		zap.String("to", state.String()),
+	)
+	b.state = state
+	stateGauge.WithLabelValues(b.name).Set(float64(state))
+}
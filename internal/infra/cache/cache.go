@@ -0,0 +1,196 @@
+// Package cache keeps hot read results in process memory with
+// stale-while-revalidate semantics: fresh entries are served as is, stale
+// ones are served while a single background load refreshes them, and only
+// expired ones make the caller wait for the database.
+//
+// Entries carry surrogate keys (tags) so writes can purge every cached
+// result they affect. Purges only reach the local replica; the stale window
+// bounds how long other replicas may serve outdated results.
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/sync/singleflight"
+)
+
+var lookups = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "cache_lookups_total",
+	Help: "Cache lookups by cache and result (hit, stale, miss).",
+}, []string{"cache", "result"})
+
+// refreshTimeout bounds background revalidation, which outlives the request
+// that triggered it.
+const refreshTimeout = 10 * time.Second
+
+// Settings configures a cache.
+type Settings struct {
+	// TTL is how long an entry is served without revalidation.
+	TTL time.Duration
+	// Stale is how long past TTL an entry is still served while it is
+	// refreshed in the background.
+	Stale time.Duration
+	// MaxEntries bounds memory use; zero means unbounded.
+	MaxEntries int
+}
+
+// Purger drops cached entries by surrogate key.
+type Purger interface {
+	Purge(tags ...string)
+}
+
+type entry[V any] struct {
+	value    V
+	tags     []string
+	storedAt time.Time
+}
+
+// Cache holds values of one type. Cached values are shared between callers
+// and must not be modified.
+type Cache[V any] struct {
+	name     string
+	settings Settings
+	loads    singleflight.Group
+	// now reads the clock entries age by; tests replace it.
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*entry[V]
+	tagged  map[string]map[string]struct{}
+	// generation changes on every purge, so loads that started before a
+	// purge don't store what it just removed.
+	generation uint64
+}
+
+// New creates an empty cache reported under name in metrics.
+func New[V any](name string, settings Settings) *Cache[V] {
+	return &Cache[V]{
+		name:     name,
+		settings: settings,
+		entries:  make(map[string]*entry[V]),
+		tagged:   make(map[string]map[string]struct{}),
+		now:      time.Now,
+	}
+}
+
+// Get returns the value cached under key, calling load when it is missing
+// or expired. tags are the surrogate keys of a freshly loaded value.
+// Concurrent misses for the same key share a single load.
+func (c *Cache[V]) Get(ctx context.Context, key string, tags []string, load func(ctx context.Context) (V, error)) (V, error) {
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	c.mu.Unlock()
+
+	if ok {
+		age := c.now().Sub(e.storedAt)
+		if age < c.settings.TTL {
+			lookups.WithLabelValues(c.name, "hit").Inc()
+			return e.value, nil
+		}
+		if age < c.settings.TTL+c.settings.Stale {
+			lookups.WithLabelValues(c.name, "stale").Inc()
+			go c.refresh(context.WithoutCancel(ctx), key, tags, load)
+			return e.value, nil
+		}
+	}
+
+	lookups.WithLabelValues(c.name, "miss").Inc()
+	value, err, _ := c.loads.Do(key, func() (any, error) {
+		return c.load(ctx, key, tags, load)
+	})
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+	return value.(V), nil
+}
+
+// refresh reloads a stale entry, unless a load for it is already running.
+func (c *Cache[V]) refresh(ctx context.Context, key string, tags []string, load func(ctx context.Context) (V, error)) {
+	ctx, cancel := context.WithTimeout(ctx, refreshTimeout)
+	defer cancel()
+
+	// Errors are dropped: the stale value keeps being served until it
+	// expires, and the next miss reports the failure to its caller.
+	c.loads.Do(key, func() (any, error) {
+		return c.load(ctx, key, tags, load)
+	})
+}
+
+func (c *Cache[V]) load(ctx context.Context, key string, tags []string, load func(ctx context.Context) (V, error)) (V, error) {
+	c.mu.Lock()
+	generation := c.generation
+	c.mu.Unlock()
+
+	value, err := load(ctx)
+	if err != nil {
+		return value, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generation == generation {
+		c.store(key, &entry[V]{value: value, tags: tags, storedAt: c.now()})
+	}
+	return value, nil
+}
+
+// store adds an entry, evicting to stay within MaxEntries. Callers hold mu.
+func (c *Cache[V]) store(key string, e *entry[V]) {
+	c.remove(key)
+	if max := c.settings.MaxEntries; max > 0 && len(c.entries) >= max {
+		expiry := c.settings.TTL + c.settings.Stale
+		for k, old := range c.entries {
+			if c.now().Sub(old.storedAt) >= expiry {
+				c.remove(k)
+			}
+		}
+		// Still full of live entries: drop arbitrary ones.
+		for k := range c.entries {
+			if len(c.entries) < max {
+				break
+			}
+			c.remove(k)
+		}
+	}
+
+	c.entries[key] = e
+	for _, tag := range e.tags {
+		if c.tagged[tag] == nil {
+			c.tagged[tag] = make(map[string]struct{})
+		}
+		c.tagged[tag][key] = struct{}{}
+	}
+}
+
+// remove drops one entry and its tag index. Callers hold mu.
+func (c *Cache[V]) remove(key string) {
+	e, ok := c.entries[key]
+	if !ok {
+		return
+	}
+	delete(c.entries, key)
+	for _, tag := range e.tags {
+		delete(c.tagged[tag], key)
+		if len(c.tagged[tag]) == 0 {
+			delete(c.tagged, tag)
+		}
+	}
+}
+
+// Purge drops every entry carrying one of tags.
+func (c *Cache[V]) Purge(tags ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	for _, tag := range tags {
+		for key := range c.tagged[tag] {
+			c.remove(key)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"usermanagement/internal/application/auth"
+)
+
+// fakeClock is a clock tests move by hand.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+func newTestCache() (*Cache[string], *fakeClock) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	c := New[string]("test", Settings{TTL: time.Minute, Stale: time.Hour})
+	c.now = clock.Now
+	return c, clock
+}
+
+// value returns a load that always yields v, counting its calls.
+func value(v string, calls *atomic.Int32) func(context.Context) (string, error) {
+	return func(context.Context) (string, error) {
+		calls.Add(1)
+		return v, nil
+	}
+}
+
+func mustGet(t *testing.T, c *Cache[string], key string, tags []string, load func(context.Context) (string, error)) string {
+	t.Helper()
+	v, err := c.Get(context.Background(), key, tags, load)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestCacheServesFreshEntries(t *testing.T) {
+	c, clock := newTestCache()
+	var calls atomic.Int32
+
+	mustGet(t, c, "k", nil, value("v1", &calls))
+	clock.Advance(59 * time.Second)
+	if got := mustGet(t, c, "k", nil, value("v2", &calls)); got != "v1" || calls.Load() != 1 {
+		t.Fatalf("Get = %q after %d loads, want the cached v1 after 1", got, calls.Load())
+	}
+}
+
+func TestCacheServesStaleWhileRefreshingOnce(t *testing.T) {
+	c, clock := newTestCache()
+	var calls atomic.Int32
+	mustGet(t, c, "k", nil, value("v1", &calls))
+	clock.Advance(2 * time.Minute)
+
+	release := make(chan struct{})
+	refreshed := make(chan struct{}, 10)
+	refresh := func(context.Context) (string, error) {
+		calls.Add(1)
+		<-release
+		refreshed <- struct{}{}
+		return "v2", nil
+	}
+
+	for i := 0; i < 5; i++ {
+		if got := mustGet(t, c, "k", nil, refresh); got != "v1" {
+			t.Fatalf("stale Get = %q, want v1 without waiting", got)
+		}
+	}
+	// Give every background refresh time to reach the load.
+	time.Sleep(20 * time.Millisecond)
+	if calls.Load() != 2 {
+		t.Fatalf("%d loads while refreshing, want the initial one and one refresh", calls.Load())
+	}
+
+	close(release)
+	<-refreshed
+	deadline := time.Now().Add(time.Second)
+	for mustGet(t, c, "k", nil, value("v3", &calls)) != "v2" {
+		if time.Now().After(deadline) {
+			t.Fatal("refreshed value never served")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCacheLoadsExpiredEntries(t *testing.T) {
+	c, clock := newTestCache()
+	var calls atomic.Int32
+	mustGet(t, c, "k", nil, value("v1", &calls))
+	clock.Advance(time.Minute + time.Hour)
+
+	if got := mustGet(t, c, "k", nil, value("v2", &calls)); got != "v2" {
+		t.Fatalf("expired Get = %q, want the newly loaded v2", got)
+	}
+}
+
+func TestCacheSharesConcurrentMisses(t *testing.T) {
+	c, _ := newTestCache()
+	var calls atomic.Int32
+	release := make(chan struct{})
+	load := func(context.Context) (string, error) {
+		calls.Add(1)
+		<-release
+		return "v", nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Get(context.Background(), "k", nil, load)
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Fatalf("%d loads for concurrent misses, want 1", calls.Load())
+	}
+}
+
+func TestCacheDropsLoadsRacingAPurge(t *testing.T) {
+	c, _ := newTestCache()
+	started, release := make(chan struct{}), make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.Get(context.Background(), "k", []string{"user:1"}, func(context.Context) (string, error) {
+			close(started)
+			<-release
+			return "before purge", nil
+		})
+	}()
+
+	<-started
+	c.Purge("user:1")
+	close(release)
+	<-done
+
+	var calls atomic.Int32
+	if got := mustGet(t, c, "k", nil, value("after purge", &calls)); got != "after purge" {
+		t.Fatalf("Get = %q, want the value loaded after the purge", got)
+	}
+}
+
+func TestCachePurgesByTag(t *testing.T) {
+	c, _ := newTestCache()
+	var calls atomic.Int32
+	mustGet(t, c, "a", []string{"user:1", "users"}, value("a1", &calls))
+	mustGet(t, c, "b", []string{"user:2", "users"}, value("b1", &calls))
+
+	c.Purge("user:1")
+	if got := mustGet(t, c, "a", nil, value("a2", &calls)); got != "a2" {
+		t.Fatalf("purged entry = %q, want a2", got)
+	}
+	if got := mustGet(t, c, "b", nil, value("b2", &calls)); got != "b1" {
+		t.Fatalf("other entry = %q, want b1", got)
+	}
+
+	c.Purge("users")
+	if got := mustGet(t, c, "b", nil, value("b3", &calls)); got != "b3" {
+		t.Fatalf("entry after purging a shared tag = %q, want b3", got)
+	}
+}
+
+func TestUseCaseBypassesTheCacheForCallers(t *testing.T) {
+	c, _ := newTestCache()
+	next := &countingExecutor{}
+	cached := UseCase(c, func(input string) (string, []string) { return input, nil }, next)
+
+	anonymous := context.Background()
+	cached.Execute(anonymous, "k")
+	cached.Execute(anonymous, "k")
+	if next.calls.Load() != 1 {
+		t.Fatalf("anonymous calls reached the use case %d times, want 1", next.calls.Load())
+	}
+
+	caller := auth.WithCaller(context.Background(), auth.Caller{})
+	cached.Execute(caller, "k")
+	cached.Execute(caller, "k")
+	if next.calls.Load() != 3 {
+		t.Fatalf("use case ran %d times, want every authenticated call to reach it", next.calls.Load())
+	}
+}
+
+// countingExecutor is a read use case counting its calls.
+type countingExecutor struct{ calls atomic.Int32 }
+
+func (u *countingExecutor) Execute(context.Context, string) (string, error) {
+	u.calls.Add(1)
+	return "result", nil
+}
@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"context"
+
+	"usermanagement/internal/application/auth"
+	"usermanagement/internal/application/usecase"
+)
+
+// Key derives the cache key and surrogate keys of a use case input.
+type Key[I any] func(input I) (key string, tags []string)
+
+// UseCase decorates a read use case so anonymous calls are served from c.
+// Authenticated calls always reach next: their results may depend on the
+// caller, such as drafts visible to their author.
+func UseCase[I, O any](c *Cache[O], key Key[I], next usecase.UseCase[I, O]) usecase.UseCase[I, O] {
+	return &cachedUseCase[I, O]{cache: c, key: key, next: next}
+}
+
+type cachedUseCase[I, O any] struct {
+	cache *Cache[O]
+	key   Key[I]
+	next  usecase.UseCase[I, O]
+}
+
+func (u *cachedUseCase[I, O]) Execute(ctx context.Context, input I) (O, error) {
+	if _, ok := auth.CallerFrom(ctx); ok {
+		return u.next.Execute(ctx, input)
+	}
+
+	key, tags := u.key(input)
+	return u.cache.Get(ctx, key, tags, func(ctx context.Context) (O, error) {
+		return u.next.Execute(ctx, input)
+	})
+}
+
+// Purging decorates a write use case to purge the surrogate keys tags
+// returns from every cache once it succeeds.
+func Purging[I, O any](next usecase.UseCase[I, O], tags func(input I, output O) []string, caches ...Purger) usecase.UseCase[I, O] {
+	return &purgingUseCase[I, O]{next: next, tags: tags, caches: caches}
+}
+
+// PurgingCommand decorates a result-less write use case like Purging does.
+func PurgingCommand[I any](next usecase.Command[I], tags func(input I) []string, caches ...Purger) usecase.Command[I] {
+	return &purgingCommand[I]{next: next, tags: tags, caches: caches}
+}
+
+type purgingUseCase[I, O any] struct {
+	next   usecase.UseCase[I, O]
+	tags   func(I, O) []string
+	caches []Purger
+}
+
+func (u *purgingUseCase[I, O]) Execute(ctx context.Context, input I) (O, error) {
+	output, err := u.next.Execute(ctx, input)
+	if err == nil {
+		purge(u.caches, u.tags(input, output))
+	}
+	return output, err
+}
+
+type purgingCommand[I any] struct {
+	next   usecase.Command[I]
+	tags   func(I) []string
+	caches []Purger
+}
+
+func (c *purgingCommand[I]) Execute(ctx context.Context, input I) error {
+	err := c.next.Execute(ctx, input)
+	if err == nil {
+		purge(c.caches, c.tags(input))
+	}
+	return err
+}
+
+func purge(caches []Purger, tags []string) {
+	for _, c := range caches {
+		c.Purge(tags...)
+	}
+}
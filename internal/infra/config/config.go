@@ -4,14 +4,229 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // Config holds all application configuration.
 type Config struct {
 	Environment string
 	HTTPPort    string
+	GRPCPort    string
+	// Storage is postgres; sqlite for single-binary deployments; or memory
+	// to run without a database for demos and tests (rejected in
+	// production).
+	Storage string
+	// SQLitePath is the database file of sqlite storage.
+	SQLitePath string
+	// UserStorage is where users live: Storage, or mongo to keep them in
+	// MongoDB while everything else stays on Storage.
+	UserStorage string
+	Mongo       MongoConfig
 	Database    DatabaseConfig
 	LogLevel    string
+	Breaker     BreakerConfig
+	Budgets     BudgetConfig
+	Readiness   ReadinessConfig
+	Auth        AuthConfig
+	PublicCache PublicCacheConfig
+	Cache       CacheConfig
+	Outbox      OutboxConfig
+	Messaging   MessagingConfig
+	Webhooks    WebhookConfig
+	Signup      SignupConfig
+	PublicIDs   PublicIDConfig
+	// SecretsRefreshInterval controls how often secretsmanager:// and ssm://
+	// references are re-read. Zero resolves them once at startup only.
+	SecretsRefreshInterval time.Duration
+	// FileSources maps keys whose value was read from a KEY_FILE path to
+	// that path, for live reloading.
+	FileSources map[string]string
+	// File is the config file settings were read from, if any.
+	File string
+	// FaultInjectionRules is a JSON array of fault rules for resilience
+	// testing. It is rejected in production.
+	FaultInjectionRules string
+	// ClientProfiles is a JSON object of named serialization profiles
+	// (field naming, timestamp format) for legacy API consumers.
+	ClientProfiles string
+	// BlockedEmailDomains are rejected when creating or updating users.
+	BlockedEmailDomains []string
+	// ContentRejectWords refuse user names, post titles and comments
+	// containing them.
+	ContentRejectWords []string
+	// ContentFlagWords accept such content but report it for review and
+	// hold comments for moderation.
+	ContentFlagWords []string
+	// DebugDump logs every request and response body. Rejected in production.
+	DebugDump bool
+	// DebugDumpSecret signs X-Debug-Dump headers that enable dumping per request.
+	DebugDumpSecret string
+	// BusinessMetricsInterval controls how often business gauges (user
+	// counts, ...) are refreshed from the database. Zero disables them.
+	BusinessMetricsInterval time.Duration
+	// IDVersion selects the UUID version for new IDs: "v7" (time-ordered,
+	// the default) or "v4" (random) as a fallback.
+	IDVersion string
+	// RequestTimeout bounds the handling of each API request, down to the
+	// database queries it runs. Zero disables it.
+	RequestTimeout time.Duration
+	// IdempotencyTTL is how long responses to writes sent with an
+	// Idempotency-Key are kept for replay. Zero disables replaying.
+	IdempotencyTTL time.Duration
+	// CORSAllowedOrigins are the exact origins (scheme://host[:port]) allowed
+	// to make cross-origin requests. Empty allows none.
+	CORSAllowedOrigins []string
+	// TrustedProxies are the addresses or CIDR ranges of the reverse
+	// proxies whose X-Forwarded-For and X-Real-IP headers are believed.
+	// Empty takes every client address from the connection itself.
+	TrustedProxies []string
+	// ShutdownPreStopDelay keeps serving after SIGTERM, while reporting not
+	// ready, so load balancers can deregister the instance first.
+	ShutdownPreStopDelay time.Duration
+	// ShutdownTimeout bounds how long in-flight requests may drain.
+	ShutdownTimeout time.Duration
+	// StartupTimeout bounds how long startup waits for dependencies such as
+	// Postgres to accept connections, retrying with backoff meanwhile.
+	StartupTimeout time.Duration
+}
+
+// AuthConfig holds JWT authentication settings.
+type AuthConfig struct {
+	// JWTSecret signs tokens; it must be at least 32 bytes.
+	JWTSecret       string
+	JWTIssuer       string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	// BcryptCost is the work factor for password hashes.
+	BcryptCost int
+}
+
+// ReadinessConfig holds per-dependency readiness check settings.
+type ReadinessConfig struct {
+	// DBTimeout bounds each database ping.
+	DBTimeout time.Duration
+	// DBFailureThreshold is how many consecutive failed pings are tolerated
+	// before the service reports itself not ready.
+	DBFailureThreshold int
+}
+
+// BreakerConfig holds circuit breaker settings for external dependencies.
+type BreakerConfig struct {
+	Enabled          bool
+	FailureThreshold int
+	OpenTimeout      time.Duration
+}
+
+// BudgetConfig splits what is left of each request's deadline between the
+// dependencies it calls, so a slow call leaves time for the ones after it.
+type BudgetConfig struct {
+	Database DependencyBudget
+	Cache    DependencyBudget
+	// External covers synchronous calls to third parties (CAPTCHA).
+	External DependencyBudget
+}
+
+// DependencyBudget lets each call use Share of the remaining time, but at
+// least Floor. A Share of 1 gives calls the whole remaining time.
+type DependencyBudget struct {
+	Share float64
+	Floor time.Duration
+}
+
+// SignupConfig holds the abuse protections of public signups.
+type SignupConfig struct {
+	// CaptchaProvider is hcaptcha, turnstile, or none (rejected in
+	// production).
+	CaptchaProvider string
+	CaptchaSecret   string
+	// IPLimit signups are allowed per client IP every IPWindow. Each
+	// replica counts on its own, so a client may sign up IPLimit times
+	// per replica behind a load balancer; size it for that.
+	IPLimit  int
+	IPWindow time.Duration
+	// DisposableEmailDomains are refused, with their subdomains.
+	DisposableEmailDomains []string
+	// Activation holds new users back until they activate their account
+	// with the token sent in a user.activation_requested event, valid for
+	// ActivationTTL.
+	Activation    bool
+	ActivationTTL time.Duration
+}
+
+// PublicCacheConfig holds the cache of anonymous API reads.
+type PublicCacheConfig struct {
+	// TTL is how long a result is served as fresh; zero disables the cache.
+	TTL time.Duration
+	// Stale is how long past TTL a result is served while it is refreshed.
+	Stale      time.Duration
+	MaxEntries int
+}
+
+// CacheConfig holds the Redis cache of user lookups.
+type CacheConfig struct {
+	Enabled  bool
+	RedisURL string
+	// UserTTL bounds how long a cached user may outlive a missed
+	// invalidation.
+	UserTTL time.Duration
+}
+
+// OutboxConfig holds the relay of user events from the outbox table.
+type OutboxConfig struct {
+	// Publisher is kafka, http, log (development), or none when events only
+	// feed webhooks.
+	Publisher string
+	// URL receives the events of the http publisher.
+	URL       string
+	Interval  time.Duration
+	BatchSize int
+	// MaxBackoff caps the delay between retries of a failing event.
+	MaxBackoff time.Duration
+	// Retention is how long published events are kept, or all events
+	// when nothing publishes them; zero keeps them.
+	Retention time.Duration
+}
+
+// MessagingConfig holds the message broker user events are published to.
+type MessagingConfig struct {
+	Brokers []string
+	// Topic receives every user event; the event name becomes its type.
+	Topic string
+	// Format is cloudevents or json.
+	Format string
+}
+
+// WebhookConfig holds the delivery of user events to webhook endpoints.
+type WebhookConfig struct {
+	// Enabled serves the webhook API and delivers user events to its
+	// subscriptions.
+	Enabled bool
+	// MaxAttempts is how often a delivery is tried before it is given up.
+	MaxAttempts int
+	MaxBackoff  time.Duration
+	Timeout     time.Duration
+	// Retention is how long settled deliveries stay in the delivery log.
+	Retention time.Duration
+	// AllowPrivateNetworks lets endpoints resolve to internal addresses.
+	// Rejected in production.
+	AllowPrivateNetworks bool
+}
+
+// MongoConfig holds the MongoDB deployment of mongo user storage.
+type MongoConfig struct {
+	// URI is a mongodb:// or mongodb+srv:// connection string.
+	URI      string
+	Database string
+}
+
+// PublicIDConfig selects how user and post IDs appear in URLs and responses.
+type PublicIDConfig struct {
+	// Mode is uuid, or short for IDs that hide the UUIDs and their creation
+	// order.
+	Mode string
+	// Secret keys short IDs; changing it breaks existing links.
+	Secret string
 }
 
 // DatabaseConfig holds database-specific config.
@@ -22,18 +237,271 @@ type DatabaseConfig struct {
 	Password string
 	DBName   string
 	SSLMode  string
+	// SimpleProtocol disables server-side prepared statements so the
+	// service can run behind PgBouncer in transaction pooling mode.
+	SimpleProtocol bool
+	// VerifySchema makes the server refuse to start when the live schema
+	// does not match what the repositories expect.
+	VerifySchema bool
+	// AutoMigrate applies pending migrations at startup, before the schema
+	// is verified.
+	AutoMigrate bool
+	// QueryComments prefixes SQL statements with the current request ID.
+	QueryComments bool
+	// PasswordSource, when set, supplies the password for every new
+	// connection so rotated secrets apply without a restart.
+	PasswordSource func() string
+	// DSN overrides the individual connection fields above when set.
+	DSN string
+	// ShardDSNs lists one connection string per user shard. Order matters:
+	// users are assigned to shards by hashing their ID modulo the count.
+	ShardDSNs []string
 }
 
-// Load reads configuration from environment variables.
-func Load() (*Config, error) {
+// Load reads configuration from environment variables, falling back to the
+// config file at path for variables that are not set (see applyConfigFile),
+// and validates it. An empty path reads the first of defaultConfigFiles
+// found in the working directory, if any.
+func Load(path string) (*Config, error) {
+	file, err := applyConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	fileSources, err := resolveFileEnv()
+	if err != nil {
+		return nil, err
+	}
+
 	port, err := strconv.Atoi(getEnv("DB_PORT", "5432"))
 	if err != nil {
 		return nil, fmt.Errorf("invalid DB_PORT: %w", err)
 	}
 
-	return &Config{
-		Environment: getEnv("ENV", "development"),
+	simpleProtocol, err := strconv.ParseBool(getEnv("DB_SIMPLE_PROTOCOL", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DB_SIMPLE_PROTOCOL: %w", err)
+	}
+
+	verifySchema, err := strconv.ParseBool(getEnv("DB_VERIFY_SCHEMA", "true"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DB_VERIFY_SCHEMA: %w", err)
+	}
+
+	autoMigrate, err := strconv.ParseBool(getEnv("DB_AUTO_MIGRATE", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DB_AUTO_MIGRATE: %w", err)
+	}
+
+	queryComments, err := strconv.ParseBool(getEnv("DB_QUERY_COMMENTS", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DB_QUERY_COMMENTS: %w", err)
+	}
+
+	breakerEnabled, err := strconv.ParseBool(getEnv("BREAKER_ENABLED", "true"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid BREAKER_ENABLED: %w", err)
+	}
+
+	breakerThreshold, err := strconv.Atoi(getEnv("BREAKER_FAILURE_THRESHOLD", "5"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid BREAKER_FAILURE_THRESHOLD: %w", err)
+	}
+
+	breakerTimeout, err := time.ParseDuration(getEnv("BREAKER_OPEN_TIMEOUT", "30s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid BREAKER_OPEN_TIMEOUT: %w", err)
+	}
+
+	publicCacheTTL, err := time.ParseDuration(getEnv("PUBLIC_CACHE_TTL", "5s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PUBLIC_CACHE_TTL: %w", err)
+	}
+
+	publicCacheStale, err := time.ParseDuration(getEnv("PUBLIC_CACHE_STALE", "1m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PUBLIC_CACHE_STALE: %w", err)
+	}
+
+	publicCacheMaxEntries, err := strconv.Atoi(getEnv("PUBLIC_CACHE_MAX_ENTRIES", "10000"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PUBLIC_CACHE_MAX_ENTRIES: %w", err)
+	}
+
+	cacheEnabled, err := strconv.ParseBool(getEnv("CACHE_ENABLED", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CACHE_ENABLED: %w", err)
+	}
+
+	cacheUserTTL, err := time.ParseDuration(getEnv("CACHE_USER_TTL", "5m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CACHE_USER_TTL: %w", err)
+	}
+
+	outboxInterval, err := time.ParseDuration(getEnv("OUTBOX_INTERVAL", "1s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid OUTBOX_INTERVAL: %w", err)
+	}
+
+	outboxBatchSize, err := strconv.Atoi(getEnv("OUTBOX_BATCH_SIZE", "100"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid OUTBOX_BATCH_SIZE: %w", err)
+	}
+
+	outboxMaxBackoff, err := time.ParseDuration(getEnv("OUTBOX_MAX_BACKOFF", "5m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid OUTBOX_MAX_BACKOFF: %w", err)
+	}
+
+	outboxRetention, err := time.ParseDuration(getEnv("OUTBOX_RETENTION", "168h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid OUTBOX_RETENTION: %w", err)
+	}
+
+	webhooksEnabled, err := strconv.ParseBool(getEnv("WEBHOOKS_ENABLED", "true"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid WEBHOOKS_ENABLED: %w", err)
+	}
+
+	webhookMaxAttempts, err := strconv.Atoi(getEnv("WEBHOOK_MAX_ATTEMPTS", "10"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid WEBHOOK_MAX_ATTEMPTS: %w", err)
+	}
+
+	webhookMaxBackoff, err := time.ParseDuration(getEnv("WEBHOOK_MAX_BACKOFF", "6h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid WEBHOOK_MAX_BACKOFF: %w", err)
+	}
+
+	webhookTimeout, err := time.ParseDuration(getEnv("WEBHOOK_TIMEOUT", "10s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid WEBHOOK_TIMEOUT: %w", err)
+	}
+
+	webhookRetention, err := time.ParseDuration(getEnv("WEBHOOK_RETENTION", "168h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid WEBHOOK_RETENTION: %w", err)
+	}
+
+	webhookAllowPrivate, err := strconv.ParseBool(getEnv("WEBHOOK_ALLOW_PRIVATE_NETWORKS", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid WEBHOOK_ALLOW_PRIVATE_NETWORKS: %w", err)
+	}
+
+	accessTTL, err := time.ParseDuration(getEnv("JWT_ACCESS_TTL", "15m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT_ACCESS_TTL: %w", err)
+	}
+
+	refreshTTL, err := time.ParseDuration(getEnv("JWT_REFRESH_TTL", "720h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT_REFRESH_TTL: %w", err)
+	}
+
+	bcryptCost, err := strconv.Atoi(getEnv("BCRYPT_COST", "12"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid BCRYPT_COST: %w", err)
+	}
+
+	readinessDBTimeout, err := time.ParseDuration(getEnv("READINESS_DB_TIMEOUT", "500ms"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid READINESS_DB_TIMEOUT: %w", err)
+	}
+
+	readinessDBThreshold, err := strconv.Atoi(getEnv("READINESS_DB_FAILURE_THRESHOLD", "2"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid READINESS_DB_FAILURE_THRESHOLD: %w", err)
+	}
+
+	secretsRefresh, err := time.ParseDuration(getEnv("SECRETS_REFRESH_INTERVAL", "0s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SECRETS_REFRESH_INTERVAL: %w", err)
+	}
+
+	environment := getEnv("ENV", "development")
+	faultRules := getEnv("FAULT_INJECTION_RULES", "")
+
+	debugDump, err := strconv.ParseBool(getEnv("DEBUG_DUMP", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DEBUG_DUMP: %w", err)
+	}
+
+	// DB_DRIVER is the name STORAGE was first proposed under; it is still
+	// read so DB_DRIVER=sqlite works, but STORAGE wins when both are set.
+	storage := getEnv("STORAGE", getEnv("DB_DRIVER", "postgres"))
+
+	signupIPLimit, err := strconv.Atoi(getEnv("SIGNUP_IP_LIMIT", "5"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SIGNUP_IP_LIMIT: %w", err)
+	}
+
+	signupIPWindow, err := time.ParseDuration(getEnv("SIGNUP_IP_WINDOW", "1h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SIGNUP_IP_WINDOW: %w", err)
+	}
+
+	signupActivation, err := strconv.ParseBool(getEnv("SIGNUP_ACTIVATION", "true"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SIGNUP_ACTIVATION: %w", err)
+	}
+
+	signupActivationTTL, err := time.ParseDuration(getEnv("SIGNUP_ACTIVATION_TTL", "48h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SIGNUP_ACTIVATION_TTL: %w", err)
+	}
+
+	businessMetrics, err := time.ParseDuration(getEnv("BUSINESS_METRICS_INTERVAL", "1m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid BUSINESS_METRICS_INTERVAL: %w", err)
+	}
+
+	requestTimeout, err := time.ParseDuration(getEnv("REQUEST_TIMEOUT", "10s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid REQUEST_TIMEOUT: %w", err)
+	}
+
+	idempotencyTTL, err := time.ParseDuration(getEnv("IDEMPOTENCY_TTL", "24h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid IDEMPOTENCY_TTL: %w", err)
+	}
+
+	dbBudget, err := parseBudget("DB", "0.8", "100ms")
+	if err != nil {
+		return nil, err
+	}
+
+	cacheBudget, err := parseBudget("CACHE", "0.1", "20ms")
+	if err != nil {
+		return nil, err
+	}
+
+	externalBudget, err := parseBudget("EXTERNAL", "0.5", "1s")
+	if err != nil {
+		return nil, err
+	}
+
+	preStopDelay, err := time.ParseDuration(getEnv("SHUTDOWN_PRE_STOP_DELAY", "0s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SHUTDOWN_PRE_STOP_DELAY: %w", err)
+	}
+
+	shutdownTimeout, err := time.ParseDuration(getEnv("SHUTDOWN_TIMEOUT", "30s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SHUTDOWN_TIMEOUT: %w", err)
+	}
+
+	startupTimeout, err := time.ParseDuration(getEnv("STARTUP_TIMEOUT", "2m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid STARTUP_TIMEOUT: %w", err)
+	}
+
+	cfg := &Config{
+		Environment: environment,
 		HTTPPort:    getEnv("HTTP_PORT", "5005"),
+		GRPCPort:    getEnv("GRPC_PORT", "9090"),
+		Storage:     storage,
+		SQLitePath:  getEnv("SQLITE_PATH", "usermanagement.db"),
+		UserStorage: getEnv("USER_STORAGE", storage),
 		LogLevel:    getEnv("LOG_LEVEL", "info"),
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -42,26 +510,252 @@ func Load() (*Config, error) {
 			Password: getEnv("DB_PASSWORD", "12345678"),
 			DBName:   getEnv("DB_NAME", "blog"),
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+
+			SimpleProtocol: simpleProtocol,
+			VerifySchema:   verifySchema,
+			AutoMigrate:    autoMigrate,
+			QueryComments:  queryComments,
+			ShardDSNs:      splitList(getEnv("DB_SHARDS", "")),
+		},
+		Mongo: MongoConfig{
+			URI:      getEnv("MONGO_URI", "mongodb://localhost:27017"),
+			Database: getEnv("MONGO_DATABASE", "blog"),
+		},
+		Breaker: BreakerConfig{
+			Enabled:          breakerEnabled,
+			FailureThreshold: breakerThreshold,
+			OpenTimeout:      breakerTimeout,
+		},
+		Budgets: BudgetConfig{
+			Database: dbBudget,
+			Cache:    cacheBudget,
+			External: externalBudget,
+		},
+		PublicCache: PublicCacheConfig{
+			TTL:        publicCacheTTL,
+			Stale:      publicCacheStale,
+			MaxEntries: publicCacheMaxEntries,
+		},
+		Signup: SignupConfig{
+			CaptchaProvider:        getEnv("CAPTCHA_PROVIDER", "none"),
+			CaptchaSecret:          getEnv("CAPTCHA_SECRET", ""),
+			IPLimit:                signupIPLimit,
+			IPWindow:               signupIPWindow,
+			DisposableEmailDomains: splitList(getEnv("DISPOSABLE_EMAIL_DOMAINS", defaultDisposableDomains)),
+			Activation:             signupActivation,
+			ActivationTTL:          signupActivationTTL,
+		},
+		Cache: CacheConfig{
+			Enabled:  cacheEnabled,
+			RedisURL: getEnv("REDIS_URL", "redis://localhost:6379/0"),
+			UserTTL:  cacheUserTTL,
+		},
+		Outbox: OutboxConfig{
+			Publisher:  getEnv("OUTBOX_PUBLISHER", "log"),
+			URL:        getEnv("OUTBOX_URL", ""),
+			Interval:   outboxInterval,
+			BatchSize:  outboxBatchSize,
+			MaxBackoff: outboxMaxBackoff,
+			Retention:  outboxRetention,
 		},
-	}, nil
+		Messaging: MessagingConfig{
+			Brokers: splitList(getEnv("KAFKA_BROKERS", "")),
+			Topic:   getEnv("MESSAGING_TOPIC", "user-events"),
+			Format:  getEnv("MESSAGING_FORMAT", "cloudevents"),
+		},
+		Webhooks: WebhookConfig{
+			Enabled:              webhooksEnabled,
+			MaxAttempts:          webhookMaxAttempts,
+			MaxBackoff:           webhookMaxBackoff,
+			Timeout:              webhookTimeout,
+			Retention:            webhookRetention,
+			AllowPrivateNetworks: webhookAllowPrivate,
+		},
+		PublicIDs: PublicIDConfig{
+			Mode:   getEnv("PUBLIC_IDS", "uuid"),
+			Secret: getEnv("PUBLIC_ID_SECRET", ""),
+		},
+		Auth: AuthConfig{
+			JWTSecret:       getEnv("JWT_SECRET", ""),
+			JWTIssuer:       getEnv("JWT_ISSUER", "usermanagement"),
+			AccessTokenTTL:  accessTTL,
+			RefreshTokenTTL: refreshTTL,
+			BcryptCost:      bcryptCost,
+		},
+		Readiness: ReadinessConfig{
+			DBTimeout:          readinessDBTimeout,
+			DBFailureThreshold: readinessDBThreshold,
+		},
+		SecretsRefreshInterval:  secretsRefresh,
+		FileSources:             fileSources,
+		File:                    file,
+		FaultInjectionRules:     faultRules,
+		ClientProfiles:          getEnv("CLIENT_PROFILES", ""),
+		BlockedEmailDomains:     splitList(getEnv("BLOCKED_EMAIL_DOMAINS", "")),
+		ContentRejectWords:      splitList(getEnv("CONTENT_REJECT_WORDS", "")),
+		ContentFlagWords:        splitList(getEnv("CONTENT_FLAG_WORDS", "")),
+		DebugDump:               debugDump,
+		DebugDumpSecret:         getEnv("DEBUG_DUMP_SECRET", ""),
+		BusinessMetricsInterval: businessMetrics,
+		IDVersion:               getEnv("ID_VERSION", "v7"),
+		RequestTimeout:          requestTimeout,
+		IdempotencyTTL:          idempotencyTTL,
+		CORSAllowedOrigins:      CORSOrigins(),
+		TrustedProxies:          splitList(getEnv("TRUSTED_PROXIES", "")),
+		ShutdownPreStopDelay:    preStopDelay,
+		ShutdownTimeout:         shutdownTimeout,
+		StartupTimeout:          startupTimeout,
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// Validate reports the first setting that is out of range, inconsistent
+// with another, or not allowed in the configured environment.
+func (c *Config) Validate() error {
+	switch {
+	case !validPort(c.HTTPPort):
+		return fmt.Errorf("invalid HTTP_PORT: %q is not a port number", c.HTTPPort)
+	case !validPort(c.GRPCPort):
+		return fmt.Errorf("invalid GRPC_PORT: %q is not a port number", c.GRPCPort)
+	case c.Database.Port < 1 || c.Database.Port > 65535:
+		return fmt.Errorf("invalid DB_PORT: %d is not a port number", c.Database.Port)
+	case c.Storage != "postgres" && c.Storage != "sqlite" && c.Storage != "memory":
+		return fmt.Errorf("invalid STORAGE: %q is not postgres, sqlite or memory", c.Storage)
+	case c.UserStorage != c.Storage && c.UserStorage != "mongo":
+		return fmt.Errorf("invalid USER_STORAGE: %q is not mongo or STORAGE", c.UserStorage)
+	case c.UserStorage == "mongo" && len(c.Database.ShardDSNs) > 0:
+		return fmt.Errorf("USER_STORAGE=mongo cannot be combined with DB_SHARDS")
+	case c.UserStorage == "mongo" && (c.Outbox.Publisher != "none" || c.Webhooks.Enabled):
+		// Mongo user writes emit no events, so nothing would be published.
+		return fmt.Errorf("USER_STORAGE=mongo emits no user events: set OUTBOX_PUBLISHER=none and WEBHOOKS_ENABLED=false")
+	case (c.Storage == "sqlite" || c.Storage == "memory") && (c.Outbox.Publisher != "none" || c.Webhooks.Enabled):
+		// Neither has an outbox or a webhook queue, so events would never
+		// be published and subscriptions never delivered to.
+		return fmt.Errorf("STORAGE=%s emits no user events: set OUTBOX_PUBLISHER=none and WEBHOOKS_ENABLED=false", c.Storage)
+	case c.Signup.Activation && c.Outbox.Publisher == "none" && !c.Webhooks.Enabled:
+		// The token only reaches the user through the event.
+		return fmt.Errorf("SIGNUP_ACTIVATION sends tokens as user events: set OUTBOX_PUBLISHER or WEBHOOKS_ENABLED, or SIGNUP_ACTIVATION=false")
+	case c.Signup.Activation && c.Signup.ActivationTTL <= 0:
+		return fmt.Errorf("SIGNUP_ACTIVATION_TTL must be positive")
+	case c.Auth.AccessTokenTTL <= 0:
+		return fmt.Errorf("JWT_ACCESS_TTL must be positive")
+	case c.Auth.RefreshTokenTTL <= 0:
+		return fmt.Errorf("JWT_REFRESH_TTL must be positive")
+	case c.Breaker.Enabled && c.Breaker.FailureThreshold <= 0:
+		return fmt.Errorf("BREAKER_FAILURE_THRESHOLD must be positive")
+	case c.Outbox.Interval <= 0:
+		return fmt.Errorf("OUTBOX_INTERVAL must be positive")
+	case c.Outbox.BatchSize <= 0:
+		return fmt.Errorf("OUTBOX_BATCH_SIZE must be positive")
+	case c.Webhooks.MaxAttempts <= 0:
+		return fmt.Errorf("WEBHOOK_MAX_ATTEMPTS must be positive")
+	}
+
+	if c.Environment != "production" {
+		return nil
+	}
+	switch {
+	case c.FaultInjectionRules != "":
+		return fmt.Errorf("FAULT_INJECTION_RULES must not be set in production")
+	case c.DebugDump:
+		return fmt.Errorf("DEBUG_DUMP must not be enabled in production")
+	case c.Webhooks.AllowPrivateNetworks:
+		return fmt.Errorf("WEBHOOK_ALLOW_PRIVATE_NETWORKS must not be enabled in production")
+	case c.Storage == "memory":
+		return fmt.Errorf("STORAGE=memory must not be used in production")
+	case c.Signup.CaptchaProvider == "none":
+		return fmt.Errorf("CAPTCHA_PROVIDER must be set in production")
+	}
+	return nil
+}
+
+// validPort reports whether port is a TCP port number; 0 picks a free one.
+func validPort(port string) bool {
+	n, err := strconv.Atoi(port)
+	return err == nil && n >= 0 && n <= 65535
 }
 
 // DatabaseURL returns the PostgreSQL connection string.
 func (c *Config) DatabaseURL() string {
+	return c.Database.URL()
+}
+
+// URL returns the PostgreSQL connection string for this database.
+func (d DatabaseConfig) URL() string {
+	if d.DSN != "" {
+		return d.DSN
+	}
 	return fmt.Sprintf(
 		"postgres://%s:%s@%s:%d/%s?sslmode=%s",
-		c.Database.User,
-		c.Database.Password,
-		c.Database.Host,
-		c.Database.Port,
-		c.Database.DBName,
-		c.Database.SSLMode,
+		d.User,
+		d.Password,
+		d.Host,
+		d.Port,
+		d.DBName,
+		d.SSLMode,
 	)
 }
 
+// Shards returns one DatabaseConfig per configured shard, sharing all
+// settings except the connection string. It is empty when sharding is off.
+func (d DatabaseConfig) Shards() []DatabaseConfig {
+	shards := make([]DatabaseConfig, 0, len(d.ShardDSNs))
+	for _, dsn := range d.ShardDSNs {
+		shard := d
+		shard.DSN = dsn
+		shard.ShardDSNs = nil
+		shard.PasswordSource = nil
+		shards = append(shards, shard)
+	}
+	return shards
+}
+
+// defaultDisposableDomains lists widely used throwaway mailbox providers.
+const defaultDisposableDomains = "mailinator.com,guerrillamail.com,guerrillamail.net,sharklasers.com,10minutemail.com," +
+	"temp-mail.org,tempmail.com,yopmail.com,trashmail.com,getnada.com,dispostable.com,maildrop.cc," +
+	"throwawaymail.com,mintemail.com,fakeinbox.com,mohmal.com,emailondeck.com,tempail.com"
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
+
+// parseBudget reads BUDGET_<name>_SHARE and BUDGET_<name>_FLOOR.
+func parseBudget(name, defaultShare, defaultFloor string) (DependencyBudget, error) {
+	share, err := strconv.ParseFloat(getEnv("BUDGET_"+name+"_SHARE", defaultShare), 64)
+	if err != nil {
+		return DependencyBudget{}, fmt.Errorf("invalid BUDGET_%s_SHARE: %w", name, err)
+	}
+	if share <= 0 || share > 1 {
+		return DependencyBudget{}, fmt.Errorf("invalid BUDGET_%s_SHARE: %v is not in (0, 1]", name, share)
+	}
+
+	floor, err := time.ParseDuration(getEnv("BUDGET_"+name+"_FLOOR", defaultFloor))
+	if err != nil {
+		return DependencyBudget{}, fmt.Errorf("invalid BUDGET_%s_FLOOR: %w", name, err)
+	}
+
+	return DependencyBudget{Share: share, Floor: floor}, nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// CORSOrigins reads CORS_ALLOWED_ORIGINS from the environment. It is exported
+// so the allowed origins can be re-read when the environment is reloaded.
+func CORSOrigins() []string {
+	return splitList(getEnv("CORS_ALLOWED_ORIGINS", ""))
+}
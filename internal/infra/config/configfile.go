@@ -0,0 +1,128 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultConfigFiles are looked for in the working directory, in order,
+// when no config file is given.
+var defaultConfigFiles = []string{"config.yaml", "config.yml", "config.toml"}
+
+// applyConfigFile reads the YAML or TOML config file at path and exports
+// every setting the environment leaves unset, so the environment overrides
+// the file and the file overrides defaults. It returns the file read: path,
+// or with an empty path the first of defaultConfigFiles present, if any.
+//
+// Settings are named after their environment variables. Nested tables join
+// their keys with underscores and any case is accepted, so
+//
+//	db:
+//	  host: db.internal
+//	  shards: [postgres://a, postgres://b]
+//
+// sets DB_HOST and DB_SHARDS, lists becoming comma-separated values. Values
+// are used as written: secretsmanager:// and ssm:// references are only
+// resolved in the environment, though KEY_FILE settings work as there.
+func applyConfigFile(path string) (string, error) {
+	if path == "" {
+		for _, name := range defaultConfigFiles {
+			if _, err := os.Stat(name); err == nil {
+				path = name
+				break
+			} else if !errors.Is(err, fs.ErrNotExist) {
+				return "", fmt.Errorf("failed to read config file: %w", err)
+			}
+		}
+		if path == "" {
+			return "", nil
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var doc map[string]any
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &doc)
+	case ".toml":
+		err = toml.Unmarshal(data, &doc)
+	default:
+		return "", fmt.Errorf("invalid config file %s: %q is not .yaml, .yml or .toml", path, ext)
+	}
+	if err != nil {
+		return "", fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	settings := make(map[string]string)
+	if err := flattenSettings("", doc, settings); err != nil {
+		return "", fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if os.Getenv(key) != "" {
+			continue
+		}
+		if err := os.Setenv(key, settings[key]); err != nil {
+			return "", fmt.Errorf("failed to apply %s from config file: %w", key, err)
+		}
+	}
+
+	return path, nil
+}
+
+// flattenSettings adds the settings of table to settings, naming each after
+// its environment variable under prefix.
+func flattenSettings(prefix string, table map[string]any, settings map[string]string) error {
+	for name, value := range table {
+		key := strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+		if prefix != "" {
+			key = prefix + "_" + key
+		}
+
+		var setting string
+		switch value := value.(type) {
+		case map[string]any:
+			if err := flattenSettings(key, value, settings); err != nil {
+				return err
+			}
+			continue
+		case []any:
+			items := make([]string, len(value))
+			for i, item := range value {
+				switch item.(type) {
+				case map[string]any, []any:
+					return fmt.Errorf("%s: lists may only hold plain values", key)
+				}
+				items[i] = fmt.Sprint(item)
+			}
+			setting = strings.Join(items, ",")
+		case nil:
+		default:
+			setting = fmt.Sprint(value)
+		}
+
+		// e.g. both DB_HOST and db.host
+		if _, dup := settings[key]; dup {
+			return fmt.Errorf("%s is set more than once", key)
+		}
+		settings[key] = setting
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// fileSuffix marks environment variables that point at a file holding the
+// real value, e.g. DB_PASSWORD_FILE=/etc/secrets/db-password. This is how
+// Kubernetes Secrets and ConfigMaps are usually mounted.
+const fileSuffix = "_FILE"
+
+// resolveFileEnv reads every KEY_FILE variable and exports its content as KEY.
+// It returns the KEY -> path mapping so the files can be watched.
+func resolveFileEnv() (map[string]string, error) {
+	files := make(map[string]string)
+	for _, kv := range os.Environ() {
+		name, path, _ := strings.Cut(kv, "=")
+		if !strings.HasSuffix(name, fileSuffix) || path == "" {
+			continue
+		}
+		key := strings.TrimSuffix(name, fileSuffix)
+		if _, err := loadFile(key, path); err != nil {
+			return nil, err
+		}
+		files[key] = path
+	}
+	return files, nil
+}
+
+// loadFile exports the content of path as key and reports whether it changed.
+func loadFile(key, path string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s%s: %w", key, fileSuffix, err)
+	}
+	value := strings.TrimSpace(string(data))
+	if os.Getenv(key) == value {
+		return false, nil
+	}
+	return true, os.Setenv(key, value)
+}
+
+// WatchFiles re-reads file-sourced values whenever their files change, until
+// ctx is done. Directories are watched rather than files because Kubernetes
+// updates mounted volumes by atomically swapping a symlink.
+func WatchFiles(ctx context.Context, files map[string]string, onChange func(keys []string), onError func(err error)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	dirs := make(map[string]bool)
+	for _, path := range files {
+		dir := filepath.Dir(path)
+		if dirs[dir] {
+			continue
+		}
+		if err := watcher.Add(dir); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", dir, err)
+		}
+		dirs[dir] = true
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-watcher.Errors:
+			onError(err)
+		case event := <-watcher.Events:
+			dir := filepath.Dir(event.Name)
+			var changed []string
+			for key, path := range files {
+				if filepath.Dir(path) != dir {
+					continue
+				}
+				ok, err := loadFile(key, path)
+				if err != nil {
+					// The file may be mid-swap; the next event will retry.
+					onError(err)
+					continue
+				}
+				if ok {
+					changed = append(changed, key)
+				}
+			}
+			if len(changed) > 0 {
+				sort.Strings(changed)
+				onChange(changed)
+			}
+		}
+	}
+}
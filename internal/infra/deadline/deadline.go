@@ -0,0 +1,40 @@
+// Package deadline splits what is left of a request's deadline between the
+// dependencies it calls in turn.
+//
+// Without it, every call inherits the whole request deadline, so a slow
+// first call (a cache lookup, say) can use all of it and leave the database
+// query that follows to fail too. A Budget lets each call use a share of the
+// remaining time, keeping the rest for whatever comes next, but never less
+// than a floor, so calls late in a request still get a fair chance.
+package deadline
+
+import (
+	"context"
+	"time"
+)
+
+// Budget bounds one kind of downstream call.
+type Budget struct {
+	// Share is the fraction of the remaining time a call may use; 1 lets it
+	// use all of it.
+	Share float64
+	// Floor is the least time a call gets, provided that much is left.
+	Floor time.Duration
+}
+
+// Bound returns a context for one call under b. Contexts without a deadline,
+// such as those of background jobs, come back unchanged. The returned cancel
+// must be called once the call is done.
+func (b Budget) Bound(ctx context.Context) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if !ok || b.Share >= 1 {
+		return ctx, func() {}
+	}
+
+	remaining := time.Until(deadline)
+	d := max(time.Duration(float64(remaining)*b.Share), b.Floor)
+	if d >= remaining {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, d)
+}
@@ -0,0 +1,100 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"usermanagement/internal/infra/logger"
+)
+
+// Check probes a single dependency for readiness.
+type Check struct {
+	Name  string
+	Probe func(ctx context.Context) error
+	// Timeout bounds each probe; slower answers count as failures.
+	Timeout time.Duration
+	// FailureThreshold is how many consecutive failures are tolerated before
+	// the dependency is reported as not ready. Values below 1 mean 1.
+	FailureThreshold int
+}
+
+// CheckResult is the readiness state of one dependency.
+type CheckResult struct {
+	Ready               bool   `json:"ready"`
+	ConsecutiveFailures int    `json:"consecutive_failures,omitempty"`
+	Error               string `json:"error,omitempty"`
+}
+
+// Checker runs readiness checks and debounces transient failures, so a single
+// slow ping doesn't flap readiness and trigger restarts.
+type Checker struct {
+	mu       sync.Mutex
+	checks   []Check
+	failures map[string]int
+	draining bool
+	logger   *logger.Logger
+}
+
+// NewChecker creates a checker for the given dependencies.
+func NewChecker(logger *logger.Logger, checks ...Check) *Checker {
+	return &Checker{
+		checks:   checks,
+		failures: make(map[string]int),
+		logger:   logger,
+	}
+}
+
+// Drain makes every following check report not ready, so load balancers
+// stop routing new traffic before the server shuts down.
+func (c *Checker) Drain() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.draining = true
+}
+
+// Check probes every dependency and reports whether all of them are ready.
+func (c *Checker) Check(ctx context.Context) (bool, map[string]CheckResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.draining {
+		return false, map[string]CheckResult{"shutdown": {Error: "draining"}}
+	}
+
+	ready := true
+	results := make(map[string]CheckResult, len(c.checks))
+	for _, check := range c.checks {
+		probeCtx, cancel := context.WithTimeout(ctx, check.Timeout)
+		err := check.Probe(probeCtx)
+		cancel()
+
+		if err == nil {
+			c.failures[check.Name] = 0
+			results[check.Name] = CheckResult{Ready: true}
+			continue
+		}
+
+		c.failures[check.Name]++
+		failures := c.failures[check.Name]
+		result := CheckResult{
+			Ready:               failures < max(check.FailureThreshold, 1),
+			ConsecutiveFailures: failures,
+			Error:               err.Error(),
+		}
+		if !result.Ready {
+			ready = false
+		}
+		results[check.Name] = result
+
+		c.logger.Warn("readiness check failed",
+			zap.String("check", check.Name),
+			zap.Int("consecutive_failures", failures),
+			zap.Bool("ready", result.Ready),
+			zap.Error(err),
+		)
+	}
+	return ready, results
+}
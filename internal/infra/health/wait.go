@@ -0,0 +1,61 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"go.uber.org/zap"
+
+	"usermanagement/internal/infra/logger"
+)
+
+const (
+	// waitAttemptTimeout bounds a single connection attempt, so a dependency
+	// that blackholes packets can't eat the whole startup budget at once.
+	waitAttemptTimeout = 10 * time.Second
+	waitInitialBackoff = 500 * time.Millisecond
+	waitMaxBackoff     = 10 * time.Second
+)
+
+// WaitFor calls connect until it succeeds or ctx is done, backing off
+// exponentially with jitter between attempts. It lets the service start
+// before its dependencies, as happens on orchestrated cold starts; give ctx
+// a deadline to bound the wait.
+func WaitFor[T any](ctx context.Context, name string, logger *logger.Logger, connect func(ctx context.Context) (T, error)) (T, error) {
+	start := time.Now()
+	backoff := waitInitialBackoff
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, waitAttemptTimeout)
+		dep, err := connect(attemptCtx)
+		cancel()
+		if err == nil {
+			if attempt > 1 {
+				logger.Info("dependency is up",
+					zap.String("dependency", name),
+					zap.Int("attempts", attempt),
+					zap.Duration("waited", time.Since(start)),
+				)
+			}
+			return dep, nil
+		}
+
+		// Jitter keeps replicas from retrying in lockstep.
+		delay := time.Duration(rand.Int63n(int64(backoff))) + backoff/2
+		logger.Warn("waiting for dependency",
+			zap.String("dependency", name),
+			zap.Int("attempt", attempt),
+			zap.Duration("retry_in", delay),
+			zap.Error(err),
+		)
+
+		select {
+		case <-ctx.Done():
+			var zero T
+			return zero, fmt.Errorf("%s not available after %s and %d attempts: %w", name, time.Since(start).Round(time.Second), attempt, err)
+		case <-time.After(delay):
+		}
+		backoff = min(backoff*2, waitMaxBackoff)
+	}
+}
@@ -0,0 +1,53 @@
+// Package idempotency stores the responses to requests sent with an
+// Idempotency-Key, so a client retrying a write after a timeout or dropped
+// connection gets the original result instead of repeating it.
+//
+// The first request with a key claims it; retries arriving while it runs
+// see the claim, and retries arriving after it finished see its response.
+// A claim left behind by a replica that died lapses after its lock
+// duration, so the key does not stay stuck until it expires.
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Record is what a store holds for a key.
+type Record struct {
+	// Fingerprint identifies the request that claimed the key; a retry
+	// with a different fingerprint is a different request reusing it.
+	Fingerprint string
+	// Status is zero while the first request is still running.
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// Done reports whether the request that claimed the key has finished.
+func (r *Record) Done() bool {
+	return r.Status != 0
+}
+
+// Claim asks for a key on behalf of one request.
+type Claim struct {
+	Key         string
+	Fingerprint string
+	// Lock is how long the claim holds while the request runs.
+	Lock time.Duration
+	// TTL is how long the key and its response are kept.
+	TTL time.Duration
+}
+
+// Store keeps keys and their responses.
+type Store interface {
+	// Claim takes the key when it is free, returning nil; the caller must
+	// then Complete or Release it. Otherwise it returns the key's record.
+	Claim(ctx context.Context, c Claim) (*Record, error)
+	// Complete stores the response to the claiming request.
+	Complete(ctx context.Context, key string, r Record) error
+	// Release frees a claimed key without storing a response, so the
+	// request can be retried.
+	Release(ctx context.Context, key, fingerprint string) error
+}
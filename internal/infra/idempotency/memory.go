@@ -0,0 +1,85 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepEvery is how often MemoryStore drops expired keys.
+const sweepEvery = time.Minute
+
+// MemoryStore keeps keys in process memory, for single-replica and
+// development setups; keys are lost on restart.
+type MemoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]*memoryEntry
+	lastSweep time.Time
+}
+
+type memoryEntry struct {
+	record      Record
+	lockedUntil time.Time
+	expiresAt   time.Time
+}
+
+// NewMemoryStore creates an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]*memoryEntry)}
+}
+
+// Claim takes the key when it is new, expired, or its claim lapsed.
+func (s *MemoryStore) Claim(_ context.Context, c Claim) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	if e, ok := s.entries[c.Key]; ok && now.Before(e.expiresAt) && (e.record.Done() || now.Before(e.lockedUntil)) {
+		record := e.record
+		return &record, nil
+	}
+
+	s.entries[c.Key] = &memoryEntry{
+		record:      Record{Fingerprint: c.Fingerprint},
+		lockedUntil: now.Add(c.Lock),
+		expiresAt:   now.Add(c.TTL),
+	}
+	return nil, nil
+}
+
+// Complete stores the response to the request holding the key.
+func (s *MemoryStore) Complete(_ context.Context, key string, r Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.entries[key]; ok && !e.record.Done() && e.record.Fingerprint == r.Fingerprint {
+		e.record = r
+	}
+	return nil
+}
+
+// Release frees the key if the request with fingerprint still holds it.
+func (s *MemoryStore) Release(_ context.Context, key, fingerprint string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.entries[key]; ok && !e.record.Done() && e.record.Fingerprint == fingerprint {
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+// sweep drops expired keys, at most once per sweepEvery.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepEvery {
+		return
+	}
+	s.lastSweep = now
+	for key, e := range s.entries {
+		if !now.Before(e.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+}
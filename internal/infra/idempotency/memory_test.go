@@ -0,0 +1,89 @@
+package idempotency_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"usermanagement/internal/infra/idempotency"
+)
+
+func TestMemoryStoreClaimLifecycle(t *testing.T) {
+	store := idempotency.NewMemoryStore()
+	ctx := context.Background()
+	claim := idempotency.Claim{Key: "k", Fingerprint: "a", Lock: time.Minute, TTL: time.Hour}
+
+	if stored, err := store.Claim(ctx, claim); err != nil || stored != nil {
+		t.Fatalf("first Claim = %+v, %v; want the key", stored, err)
+	}
+
+	stored, err := store.Claim(ctx, claim)
+	if err != nil || stored == nil || stored.Done() {
+		t.Fatalf("Claim while running = %+v, %v; want the pending record", stored, err)
+	}
+
+	// Only the request holding the key may release it.
+	if err := store.Release(ctx, "k", "b"); err != nil {
+		t.Fatal(err)
+	}
+	if stored, _ := store.Claim(ctx, claim); stored == nil {
+		t.Fatal("Release with another fingerprint freed the key")
+	}
+
+	if err := store.Complete(ctx, "k", idempotency.Record{Fingerprint: "a", Status: 201, Body: []byte("created")}); err != nil {
+		t.Fatal(err)
+	}
+	stored, err = store.Claim(ctx, claim)
+	if err != nil || stored == nil || stored.Status != 201 || string(stored.Body) != "created" {
+		t.Fatalf("Claim after Complete = %+v, %v; want the stored response", stored, err)
+	}
+
+	// A finished key is kept for its TTL: releasing it is a no-op.
+	if err := store.Release(ctx, "k", "a"); err != nil {
+		t.Fatal(err)
+	}
+	if stored, _ := store.Claim(ctx, claim); stored == nil || !stored.Done() {
+		t.Fatal("Release dropped a completed key")
+	}
+}
+
+func TestMemoryStoreRelease(t *testing.T) {
+	store := idempotency.NewMemoryStore()
+	ctx := context.Background()
+	claim := idempotency.Claim{Key: "k", Fingerprint: "a", Lock: time.Minute, TTL: time.Hour}
+
+	if _, err := store.Claim(ctx, claim); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Release(ctx, "k", "a"); err != nil {
+		t.Fatal(err)
+	}
+	if stored, err := store.Claim(ctx, claim); err != nil || stored != nil {
+		t.Fatalf("Claim after Release = %+v, %v; want the key", stored, err)
+	}
+}
+
+func TestMemoryStoreLapsedClaim(t *testing.T) {
+	store := idempotency.NewMemoryStore()
+	ctx := context.Background()
+
+	// The replica holding the key died; once its lock lapses a retry may
+	// take the key over, with its own fingerprint.
+	if _, err := store.Claim(ctx, idempotency.Claim{Key: "k", Fingerprint: "a", Lock: 10 * time.Millisecond, TTL: time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	retry := idempotency.Claim{Key: "k", Fingerprint: "b", Lock: time.Minute, TTL: time.Hour}
+	if stored, err := store.Claim(ctx, retry); err != nil || stored != nil {
+		t.Fatalf("Claim after the lock lapsed = %+v, %v; want the key", stored, err)
+	}
+
+	// The original request finishing late must not overwrite the retry.
+	if err := store.Complete(ctx, "k", idempotency.Record{Fingerprint: "a", Status: 200}); err != nil {
+		t.Fatal(err)
+	}
+	if stored, _ := store.Claim(ctx, retry); stored == nil || stored.Done() || stored.Fingerprint != "b" {
+		t.Fatalf("record = %+v, want the retry still pending", stored)
+	}
+}
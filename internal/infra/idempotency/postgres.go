@@ -0,0 +1,138 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"usermanagement/internal/infra/logger"
+	"usermanagement/internal/infra/persistence/postgres"
+)
+
+// PostgresStore keeps keys in the idempotency_keys table, shared by every
+// replica.
+type PostgresStore struct {
+	db     postgres.DB
+	logger *logger.Logger
+}
+
+// NewPostgresStore creates a store on db.
+func NewPostgresStore(db postgres.DB, logger *logger.Logger) *PostgresStore {
+	return &PostgresStore{db: db, logger: logger}
+}
+
+// Claim takes the key when it is new, expired, or its claim lapsed.
+// Otherwise it reads the key's record; if the key disappeared in between,
+// it tries once more.
+func (s *PostgresStore) Claim(ctx context.Context, c Claim) (*Record, error) {
+	claim := `
+		INSERT INTO idempotency_keys (key, fingerprint, locked_until, expires_at)
+		VALUES ($1, $2, now() + make_interval(secs => $3), now() + make_interval(secs => $4))
+		ON CONFLICT (key) DO UPDATE
+		SET fingerprint = EXCLUDED.fingerprint, status = NULL, header = NULL, body = NULL,
+		    locked_until = EXCLUDED.locked_until, expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= now()
+		   OR (idempotency_keys.status IS NULL AND idempotency_keys.locked_until <= now())
+		RETURNING key
+	`
+	read := `
+		SELECT fingerprint, COALESCE(status, 0), header::text, body
+		FROM idempotency_keys
+		WHERE key = $1
+	`
+
+	for attempt := 0; attempt < 2; attempt++ {
+		var key string
+		err := s.db.QueryRow(ctx, claim, c.Key, c.Fingerprint, c.Lock.Seconds(), c.TTL.Seconds()).Scan(&key)
+		if err == nil {
+			return nil, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("failed to claim idempotency key: %w", err)
+		}
+
+		var (
+			r      Record
+			header *string
+		)
+		err = s.db.QueryRow(ctx, read, c.Key).Scan(&r.Fingerprint, &r.Status, &header, &r.Body)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read idempotency key: %w", err)
+		}
+		if header != nil {
+			if err := json.Unmarshal([]byte(*header), &r.Header); err != nil {
+				return nil, fmt.Errorf("failed to decode stored response headers: %w", err)
+			}
+		}
+		return &r, nil
+	}
+	return nil, errors.New("failed to claim idempotency key: key keeps disappearing")
+}
+
+// Complete stores the response to the request holding the key.
+func (s *PostgresStore) Complete(ctx context.Context, key string, r Record) error {
+	query := `
+		UPDATE idempotency_keys SET status = $3, header = $4, body = $5
+		WHERE key = $1 AND fingerprint = $2 AND status IS NULL
+	`
+
+	header, err := json.Marshal(r.Header)
+	if err != nil {
+		return fmt.Errorf("failed to encode response headers: %w", err)
+	}
+	if _, err := s.db.Exec(ctx, query, key, r.Fingerprint, r.Status, string(header), r.Body); err != nil {
+		return fmt.Errorf("failed to store idempotent response: %w", err)
+	}
+	return nil
+}
+
+// Release frees the key if the request with fingerprint still holds it.
+func (s *PostgresStore) Release(ctx context.Context, key, fingerprint string) error {
+	query := `DELETE FROM idempotency_keys WHERE key = $1 AND fingerprint = $2 AND status IS NULL`
+
+	if _, err := s.db.Exec(ctx, query, key, fingerprint); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpired removes expired keys and reports how many it removed.
+func (s *PostgresStore) DeleteExpired(ctx context.Context) (int64, error) {
+	tag, err := s.db.Exec(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= now()`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// Run deletes expired keys every interval until ctx is done. Claims take
+// over expired keys on their own; this only keeps the table small.
+func (s *PostgresStore) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		deleted, err := s.DeleteExpired(ctx)
+		if err != nil {
+			s.logger.Warn("failed to clean up idempotency keys", zap.Error(err))
+			continue
+		}
+		if deleted > 0 {
+			s.logger.Info("deleted expired idempotency keys", zap.Int64("deleted", deleted))
+		}
+	}
+}
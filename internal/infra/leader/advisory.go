@@ -0,0 +1,81 @@
+package leader
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"usermanagement/internal/infra/logger"
+)
+
+// AdvisoryLock elects the leader with a Postgres session-level advisory lock,
+// held on a connection of its own for the whole term so it never takes a
+// connection from the pool the jobs and requests share.
+//
+// Session locks need a direct connection to Postgres: behind PgBouncer in
+// transaction pooling mode the lock would be released (or leaked) whenever
+// the server connection is handed to another client. Use LeaseLock there.
+type AdvisoryLock struct {
+	dial   func(ctx context.Context) (*pgx.Conn, error)
+	key    int64
+	logger *logger.Logger
+}
+
+// NewAdvisoryLock creates the advisory lock called name, opening a new
+// connection with dial for every attempt to take it.
+func NewAdvisoryLock(dial func(ctx context.Context) (*pgx.Conn, error), name string, logger *logger.Logger) *AdvisoryLock {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+
+	return &AdvisoryLock{
+		dial:   dial,
+		key:    int64(h.Sum64()),
+		logger: logger.WithContext(zap.String("lock", name)),
+	}
+}
+
+// TryAcquire implements Lock.
+func (l *AdvisoryLock) TryAcquire(ctx context.Context) (Term, error) {
+	conn, err := l.dial(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect: %w", err)
+	}
+
+	var acquired bool
+	if err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock($1)`, l.key).Scan(&acquired); err != nil {
+		conn.Close(context.Background())
+		return nil, fmt.Errorf("failed to try advisory lock: %w", err)
+	}
+	if !acquired {
+		conn.Close(context.Background())
+		return nil, nil
+	}
+	return &advisoryTerm{lock: l, conn: conn}, nil
+}
+
+type advisoryTerm struct {
+	lock *AdvisoryLock
+	conn *pgx.Conn
+}
+
+// Check implements Term. The lock lives as long as the session, so a broken
+// connection means another replica may already have taken over.
+func (t *advisoryTerm) Check(ctx context.Context) error {
+	return t.conn.Ping(ctx)
+}
+
+// Release implements Term. Closing the session drops the lock too, so the
+// unlock is only a courtesy to replicas waiting on it.
+func (t *advisoryTerm) Release() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := t.conn.Exec(ctx, `SELECT pg_advisory_unlock($1)`, t.lock.key); err != nil {
+		t.lock.logger.Warn("failed to release advisory lock", zap.Error(err))
+	}
+	t.conn.Close(ctx)
+}
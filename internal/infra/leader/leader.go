@@ -0,0 +1,108 @@
+package leader
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"usermanagement/internal/infra/logger"
+)
+
+// Lock is the leadership lock replicas compete for. Every replica must use
+// the same lock for the same jobs.
+type Lock interface {
+	// TryAcquire takes leadership without waiting. It returns a nil Term
+	// when another replica holds it.
+	TryAcquire(ctx context.Context) (Term, error)
+}
+
+// Term is leadership held by this replica.
+type Term interface {
+	// Check confirms leadership is still held. An error means it may have
+	// been lost and the jobs must stop.
+	Check(ctx context.Context) error
+	// Release gives leadership up. It runs after the jobs have stopped,
+	// usually with ctx already cancelled.
+	Release()
+}
+
+// Elector runs a job on exactly one replica at a time, for as long as that
+// replica holds the lock.
+type Elector struct {
+	lock          Lock
+	retryInterval time.Duration
+	checkInterval time.Duration
+	logger        *logger.Logger
+}
+
+// NewElector creates an elector competing for lock under name, which only
+// labels its logs.
+func NewElector(lock Lock, name string, logger *logger.Logger) *Elector {
+	return &Elector{
+		lock:          lock,
+		retryInterval: 15 * time.Second,
+		checkInterval: 5 * time.Second,
+		logger:        logger.WithContext(zap.String("job", name)),
+	}
+}
+
+// Run blocks until ctx is done, running fn whenever this replica holds
+// leadership. The context passed to fn is cancelled when leadership is lost.
+func (e *Elector) Run(ctx context.Context, fn func(ctx context.Context)) {
+	for {
+		if err := e.lead(ctx, fn); err != nil {
+			e.logger.Error("leader election failed", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(e.retryInterval):
+		}
+	}
+}
+
+// lead tries to become leader once and, if successful, runs fn until it
+// returns or leadership is lost.
+func (e *Elector) lead(ctx context.Context, fn func(ctx context.Context)) error {
+	term, err := e.lock.TryAcquire(ctx)
+	if err != nil || term == nil {
+		return err
+	}
+	defer term.Release()
+
+	e.logger.Info("acquired leadership")
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn(jobCtx)
+	}()
+
+	ticker := time.NewTicker(e.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			e.logger.Info("released leadership")
+			return nil
+		case <-ctx.Done():
+			<-done
+			e.logger.Info("released leadership")
+			return nil
+		case <-ticker.C:
+			// Another replica may already have taken over; stop before
+			// doing any more work.
+			if err := term.Check(ctx); err != nil {
+				e.logger.Warn("lost leadership", zap.Error(err))
+				cancel()
+				<-done
+				return nil
+			}
+		}
+	}
+}
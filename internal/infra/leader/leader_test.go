@@ -0,0 +1,117 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"usermanagement/internal/infra/logger"
+)
+
+// memLock is a Lock shared by electors in one process. Its checks fail at
+// random, as if the lock connection kept dropping.
+type memLock struct {
+	mu   sync.Mutex
+	held bool
+}
+
+func (l *memLock) TryAcquire(context.Context) (Term, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.held {
+		return nil, nil
+	}
+	l.held = true
+	return memTerm{l}, nil
+}
+
+type memTerm struct{ lock *memLock }
+
+func (t memTerm) Check(context.Context) error {
+	if rand.Intn(3) == 0 {
+		return errors.New("connection lost")
+	}
+	return nil
+}
+
+func (t memTerm) Release() {
+	t.lock.mu.Lock()
+	t.lock.held = false
+	t.lock.mu.Unlock()
+}
+
+func TestElectorsNeverRunTheJobTogether(t *testing.T) {
+	lock := &memLock{}
+	log := &logger.Logger{Logger: zap.NewNop()}
+
+	var running, runs atomic.Int32
+	var overlapped atomic.Bool
+	job := func(ctx context.Context) {
+		if running.Add(1) > 1 {
+			overlapped.Store(true)
+		}
+		runs.Add(1)
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Duration(rand.Intn(5)) * time.Millisecond):
+		}
+		running.Add(-1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		e := NewElector(lock, "job", log)
+		e.retryInterval = time.Millisecond
+		e.checkInterval = 2 * time.Millisecond
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e.Run(ctx, job)
+		}()
+	}
+	wg.Wait()
+
+	if overlapped.Load() {
+		t.Fatal("both electors ran the job at the same time")
+	}
+	if runs.Load() < 2 {
+		t.Fatalf("job ran %d times, want leadership to change hands", runs.Load())
+	}
+	if lock.held {
+		t.Fatal("leadership still held after the electors stopped")
+	}
+}
+
+func TestElectorStopsTheJobWhenLeadershipIsLost(t *testing.T) {
+	log := &logger.Logger{Logger: zap.NewNop()}
+	e := NewElector(lostLock{}, "job", log)
+	e.checkInterval = time.Millisecond
+
+	stopped := make(chan struct{})
+	go e.lead(context.Background(), func(ctx context.Context) {
+		<-ctx.Done()
+		close(stopped)
+	})
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("job kept running after leadership was lost")
+	}
+}
+
+// lostLock grants leadership that is lost at the first check.
+type lostLock struct{}
+
+func (lostLock) TryAcquire(context.Context) (Term, error) { return lostLock{}, nil }
+func (lostLock) Check(context.Context) error              { return errors.New("lease taken over") }
+func (lostLock) Release()                                 {}
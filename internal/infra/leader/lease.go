@@ -0,0 +1,71 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+
+	"usermanagement/internal/infra/lock"
+	"usermanagement/internal/infra/logger"
+)
+
+// LeaseLock elects the leader with a lease from the locks table. It needs no
+// session state, so it works behind PgBouncer in transaction pooling mode,
+// at the cost of a renewal query on every check.
+type LeaseLock struct {
+	locker *lock.Locker
+	name   string
+	ttl    time.Duration
+	logger *logger.Logger
+}
+
+// NewLeaseLock creates the lease lock called name. Its lease lasts ttl from
+// every check, which must therefore run well within ttl.
+func NewLeaseLock(locker *lock.Locker, name string, ttl time.Duration, logger *logger.Logger) *LeaseLock {
+	return &LeaseLock{
+		locker: locker,
+		name:   name,
+		ttl:    ttl,
+		logger: logger.WithContext(zap.String("lock", name)),
+	}
+}
+
+// TryAcquire implements Lock.
+func (l *LeaseLock) TryAcquire(ctx context.Context) (Term, error) {
+	lease, err := l.locker.TryAcquire(ctx, l.name, l.ttl)
+	if errors.Is(err, lock.ErrHeld) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &leaseTerm{lock: l, lease: lease}, nil
+}
+
+type leaseTerm struct {
+	lock  *LeaseLock
+	lease *lock.Lease
+}
+
+// Check implements Term. A failed renewal is retried on the next check until
+// the lease runs out locally.
+func (t *leaseTerm) Check(ctx context.Context) error {
+	err := t.lease.Renew(ctx, t.lock.ttl)
+	if err == nil || errors.Is(err, lock.ErrLost) || t.lease.Expired() {
+		return err
+	}
+	t.lock.logger.Warn("failed to renew leader lease, retrying", zap.Error(err))
+	return nil
+}
+
+// Release implements Term.
+func (t *leaseTerm) Release() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := t.lease.Release(ctx); err != nil {
+		t.lock.logger.Warn("failed to release leader lease", zap.Error(err))
+	}
+}
@@ -0,0 +1,214 @@
+// Package lock provides distributed locks shared by every replica, for
+// operations that must not run concurrently, such as bulk rewrites.
+//
+// Locks are leases stored in the locks table rather than advisory locks, so
+// they survive PgBouncer in transaction pooling mode and expire on their own
+// when the holder dies. Each acquisition returns a fencing token that grows
+// with every new holder of the same name: writers should pass it along and
+// resources should refuse tokens older than the last one they saw, since a
+// paused holder may keep working after its lease has expired. Postgres
+// writes do so with postgres.Fences.
+package lock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"usermanagement/internal/infra/logger"
+	"usermanagement/internal/infra/persistence/postgres"
+)
+
+var (
+	// ErrHeld is returned when another owner holds an unexpired lease.
+	ErrHeld = errors.New("lock is held by another owner")
+	// ErrLost is returned when a lease expired or was taken over before it
+	// could be renewed or released.
+	ErrLost = errors.New("lock was lost")
+)
+
+// Locker acquires leases on behalf of one process.
+type Locker struct {
+	db     postgres.DB
+	owner  string
+	logger *logger.Logger
+}
+
+// NewLocker creates a locker whose leases are owned by this process.
+func NewLocker(db postgres.DB, logger *logger.Logger) *Locker {
+	host, _ := os.Hostname()
+	return &Locker{
+		db:     db,
+		owner:  fmt.Sprintf("%s/%d/%s", host, os.Getpid(), uuid.NewString()),
+		logger: logger,
+	}
+}
+
+// Lease is a held lock. It is valid until it has Expired, unless renewed.
+type Lease struct {
+	Name string
+	// Token is the fencing token of this acquisition.
+	Token int64
+	// ExpiresAt is the expiry recorded by the database, on its clock.
+	ExpiresAt time.Time
+
+	locker *Locker
+	// deadline is when the lease runs out on the local monotonic clock,
+	// counted from before the statement that set it so clock skew with the
+	// database can only make it end early.
+	deadline time.Time
+}
+
+// Expired reports whether the lease has run out, without asking the
+// database.
+func (ls *Lease) Expired() bool {
+	return !time.Now().Before(ls.deadline)
+}
+
+// TryAcquire takes the named lock for ttl, or returns ErrHeld without
+// waiting when someone else holds it.
+func (l *Locker) TryAcquire(ctx context.Context, name string, ttl time.Duration) (*Lease, error) {
+	query := `
+		INSERT INTO locks (name, owner, token, expires_at)
+		VALUES ($1, $2, 1, now() + make_interval(secs => $3))
+		ON CONFLICT (name) DO UPDATE
+		SET owner = EXCLUDED.owner, token = locks.token + 1, expires_at = EXCLUDED.expires_at
+		WHERE locks.expires_at <= now()
+		RETURNING token, expires_at
+	`
+
+	lease := &Lease{Name: name, locker: l, deadline: time.Now().Add(ttl)}
+	err := l.db.QueryRow(ctx, query, name, l.owner, ttl.Seconds()).Scan(&lease.Token, &lease.ExpiresAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrHeld
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire lock %q: %w", name, err)
+	}
+	return lease, nil
+}
+
+// Acquire waits until the named lock is free and takes it for ttl, polling
+// every retry. It gives up when ctx is done.
+func (l *Locker) Acquire(ctx context.Context, name string, ttl, retry time.Duration) (*Lease, error) {
+	for {
+		lease, err := l.TryAcquire(ctx, name, ttl)
+		if !errors.Is(err, ErrHeld) {
+			return lease, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to acquire lock %q: %w", name, ctx.Err())
+		case <-time.After(retry):
+		}
+	}
+}
+
+// Renew extends the lease to ttl from now. It returns ErrLost when the lease
+// already expired, even if nobody has taken it over yet.
+func (ls *Lease) Renew(ctx context.Context, ttl time.Duration) error {
+	query := `
+		UPDATE locks SET expires_at = now() + make_interval(secs => $4)
+		WHERE name = $1 AND owner = $2 AND token = $3 AND expires_at > now()
+		RETURNING expires_at
+	`
+
+	deadline := time.Now().Add(ttl)
+	err := ls.locker.db.QueryRow(ctx, query, ls.Name, ls.locker.owner, ls.Token, ttl.Seconds()).Scan(&ls.ExpiresAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrLost
+	}
+	if err != nil {
+		return fmt.Errorf("failed to renew lock %q: %w", ls.Name, err)
+	}
+	ls.deadline = deadline
+	return nil
+}
+
+// Release gives the lock up so the next owner does not wait for expiry.
+func (ls *Lease) Release(ctx context.Context) error {
+	query := `
+		UPDATE locks SET expires_at = now()
+		WHERE name = $1 AND owner = $2 AND token = $3
+	`
+
+	tag, err := ls.locker.db.Exec(ctx, query, ls.Name, ls.locker.owner, ls.Token)
+	if err != nil {
+		return fmt.Errorf("failed to release lock %q: %w", ls.Name, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrLost
+	}
+	return nil
+}
+
+// WithLock runs fn while holding the named lock, renewing the lease every
+// third of ttl. The context passed to fn is cancelled if the lease is lost;
+// fn receives the fencing token to hand to the resources it writes. It
+// returns ErrHeld without running fn when the lock is taken.
+func (l *Locker) WithLock(ctx context.Context, name string, ttl time.Duration, fn func(ctx context.Context, token int64) error) error {
+	lease, err := l.TryAcquire(ctx, name, ttl)
+	if err != nil {
+		return err
+	}
+	log := l.logger.WithContext(zap.String("lock", name), zap.Int64("token", lease.Token))
+	log.Info("acquired lock")
+
+	fnCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(chan struct{})
+	var fnErr error
+	go func() {
+		defer close(done)
+		fnErr = fn(fnCtx, lease.Token)
+	}()
+
+	ticker := time.NewTicker(ttl / 3)
+	defer ticker.Stop()
+
+	lost := false
+renew:
+	for {
+		select {
+		case <-done:
+			break renew
+		case <-ticker.C:
+			if lost {
+				continue
+			}
+			err := lease.Renew(fnCtx, ttl)
+			switch {
+			case err == nil:
+			case errors.Is(err, ErrLost) || lease.Expired():
+				// Stop fn; another owner may take over at any moment.
+				log.Warn("lost lock", zap.Error(err))
+				lost = true
+				cancel()
+			default:
+				log.Warn("failed to renew lock, retrying", zap.Error(err))
+			}
+		}
+	}
+
+	if lost {
+		return errors.Join(fnErr, ErrLost)
+	}
+
+	// Release with its own deadline: ctx may already be cancelled.
+	releaseCtx, cancelRelease := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelRelease()
+	if err := lease.Release(releaseCtx); err != nil {
+		log.Warn("failed to release lock", zap.Error(err))
+	} else {
+		log.Info("released lock")
+	}
+	return fnErr
+}
@@ -0,0 +1,172 @@
+package lock_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+
+	"usermanagement/internal/infra/lock"
+	"usermanagement/internal/infra/logger"
+	"usermanagement/internal/infra/persistence/postgres/pgtest"
+)
+
+var nopLogger = &logger.Logger{Logger: zap.NewNop()}
+
+// skewedDB grants every lease with an expiry on a database clock that is
+// off by skew and fails every renewal, as if the database became
+// unreachable right after the lock was taken.
+type skewedDB struct {
+	skew time.Duration
+}
+
+func (d skewedDB) Exec(context.Context, string, ...any) (pgconn.CommandTag, error) {
+	return pgconn.NewCommandTag("UPDATE 1"), nil
+}
+
+func (d skewedDB) Query(context.Context, string, ...any) (pgx.Rows, error) {
+	return nil, errors.New("unexpected query")
+}
+
+func (d skewedDB) QueryRow(_ context.Context, sql string, _ ...any) pgx.Row {
+	if strings.Contains(sql, "INSERT INTO locks") {
+		return grantRow{expiresAt: time.Now().Add(d.skew)}
+	}
+	return errRow{errors.New("connection refused")}
+}
+
+type grantRow struct{ expiresAt time.Time }
+
+func (r grantRow) Scan(dest ...any) error {
+	*dest[0].(*int64) = 1
+	*dest[1].(*time.Time) = r.expiresAt
+	return nil
+}
+
+type errRow struct{ err error }
+
+func (r errRow) Scan(...any) error { return r.err }
+
+func TestLeaseExpiryUsesLocalClock(t *testing.T) {
+	for name, skew := range map[string]time.Duration{
+		"database clock behind": -time.Hour,
+		"database clock ahead":  time.Hour,
+	} {
+		t.Run(name, func(t *testing.T) {
+			locker := lock.NewLocker(skewedDB{skew: skew}, nopLogger)
+			lease, err := locker.TryAcquire(context.Background(), "job", 50*time.Millisecond)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if lease.Expired() {
+				t.Fatal("lease expired right after it was taken")
+			}
+			time.Sleep(60 * time.Millisecond)
+			if !lease.Expired() {
+				t.Fatal("lease still valid after its ttl")
+			}
+		})
+	}
+}
+
+func TestWithLockStopsWhenRenewalsFailPastTTL(t *testing.T) {
+	// The database claims the lease lasts another hour, but the holder
+	// cannot renew it and must give up once its own ttl has passed.
+	locker := lock.NewLocker(skewedDB{skew: time.Hour}, nopLogger)
+	ttl := 90 * time.Millisecond
+
+	start := time.Now()
+	err := locker.WithLock(context.Background(), "job", ttl, func(ctx context.Context, _ int64) error {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(5 * time.Second):
+			return errors.New("not stopped")
+		}
+	})
+	if !errors.Is(err, lock.ErrLost) {
+		t.Fatalf("WithLock = %v, want ErrLost", err)
+	}
+	if elapsed := time.Since(start); elapsed < ttl {
+		t.Fatalf("stopped after %v, before the ttl of %v", elapsed, ttl)
+	}
+}
+
+func TestTryAcquireContention(t *testing.T) {
+	pool := pgtest.Pool(t)
+	ctx := context.Background()
+	name := "test-" + uuid.NewString()
+	a, b := lock.NewLocker(pool, nopLogger), lock.NewLocker(pool, nopLogger)
+
+	lease, err := a.TryAcquire(ctx, name, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.TryAcquire(ctx, name, time.Minute); !errors.Is(err, lock.ErrHeld) {
+		t.Fatalf("second TryAcquire = %v, want ErrHeld", err)
+	}
+	if err := lease.Release(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.TryAcquire(ctx, name, time.Minute); err != nil {
+		t.Fatalf("TryAcquire after release = %v", err)
+	}
+}
+
+func TestRenewAfterExpiry(t *testing.T) {
+	pool := pgtest.Pool(t)
+	ctx := context.Background()
+	locker := lock.NewLocker(pool, nopLogger)
+
+	lease, err := locker.TryAcquire(ctx, "test-"+uuid.NewString(), 100*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(200 * time.Millisecond)
+
+	if err := lease.Renew(ctx, time.Minute); !errors.Is(err, lock.ErrLost) {
+		t.Fatalf("Renew = %v, want ErrLost", err)
+	}
+}
+
+func TestFencingTokensIncrease(t *testing.T) {
+	pool := pgtest.Pool(t)
+	ctx := context.Background()
+	name := "test-" + uuid.NewString()
+	a, b := lock.NewLocker(pool, nopLogger), lock.NewLocker(pool, nopLogger)
+
+	first, err := a.TryAcquire(ctx, name, 100*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(200 * time.Millisecond)
+
+	// b takes over the expired lease; a must no longer be able to act on it.
+	second, err := b.TryAcquire(ctx, name, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Token <= first.Token {
+		t.Fatalf("takeover token %d, want more than %d", second.Token, first.Token)
+	}
+	if err := first.Release(ctx); !errors.Is(err, lock.ErrLost) {
+		t.Fatalf("stale Release = %v, want ErrLost", err)
+	}
+	if err := second.Release(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	third, err := a.TryAcquire(ctx, name, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if third.Token <= second.Token {
+		t.Fatalf("token after release %d, want more than %d", third.Token, second.Token)
+	}
+}
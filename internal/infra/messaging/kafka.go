@@ -0,0 +1,76 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+
+	"usermanagement/internal/infra/outbox"
+)
+
+// KafkaPublisher writes events to a Kafka topic, keyed by aggregate ID so
+// each aggregate's events land on one partition, in order.
+type KafkaPublisher struct {
+	writer *kafka.Writer
+	encode encoder
+}
+
+// NewKafkaPublisher creates a publisher writing to settings.Topic. Writes
+// wait for every in-sync replica to acknowledge them, so an accepted event
+// survives a broker failure. The topic must exist.
+func NewKafkaPublisher(settings Settings) (*KafkaPublisher, error) {
+	if len(settings.Brokers) == 0 {
+		return nil, errors.New("kafka publisher needs at least one broker")
+	}
+	if settings.Topic == "" {
+		return nil, errors.New("kafka publisher needs a topic")
+	}
+	encode, err := newEncoder(settings.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KafkaPublisher{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(settings.Brokers...),
+			Topic:        settings.Topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+			// The relay publishes one event at a time and waits for each;
+			// the default of 1s would hold every event back for a full
+			// batch.
+			BatchTimeout: 10 * time.Millisecond,
+			WriteTimeout: 10 * time.Second,
+		},
+		encode: encode,
+	}, nil
+}
+
+// Publish implements outbox.Publisher.
+func (p *KafkaPublisher) Publish(ctx context.Context, msg outbox.Message) error {
+	body, contentType, err := p.encode(msg)
+	if err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+
+	err = p.writer.WriteMessages(ctx, kafka.Message{
+		Key:   []byte(msg.Key),
+		Value: body,
+		Headers: []kafka.Header{
+			{Key: "content-type", Value: []byte(contentType)},
+		},
+		Time: msg.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("publish to kafka: %w", err)
+	}
+	return nil
+}
+
+// Close flushes pending writes and closes broker connections.
+func (p *KafkaPublisher) Close() error {
+	return p.writer.Close()
+}
@@ -0,0 +1,78 @@
+// Package messaging publishes domain events to a message broker. Publishers
+// implement outbox.Publisher, so the outbox relay delivers through them.
+package messaging
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"usermanagement/internal/infra/outbox"
+)
+
+// Event formats a publisher can write.
+const (
+	// FormatCloudEvents writes CloudEvents 1.0 in structured mode.
+	FormatCloudEvents = "cloudevents"
+	// FormatJSON writes the outbox message as is.
+	FormatJSON = "json"
+)
+
+// source identifies this service as the CloudEvents source of its events.
+const source = "usermanagement"
+
+// Settings configure a broker publisher.
+type Settings struct {
+	// Brokers are the bootstrap addresses (host:port) of the cluster.
+	Brokers []string
+	// Topic receives every event; the event's own topic becomes its type.
+	Topic string
+	// Format is FormatCloudEvents or FormatJSON.
+	Format string
+}
+
+// cloudEvent is the structured-mode CloudEvents 1.0 envelope.
+type cloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype"`
+	Data            json.RawMessage `json:"data"`
+}
+
+// encoder serializes a message and reports its content type.
+type encoder func(msg outbox.Message) ([]byte, string, error)
+
+// newEncoder returns the encoder for format.
+func newEncoder(format string) (encoder, error) {
+	switch format {
+	case FormatCloudEvents:
+		return encodeCloudEvent, nil
+	case FormatJSON:
+		return encodeJSON, nil
+	default:
+		return nil, fmt.Errorf("unknown event format %q", format)
+	}
+}
+
+func encodeCloudEvent(msg outbox.Message) ([]byte, string, error) {
+	body, err := json.Marshal(cloudEvent{
+		SpecVersion:     "1.0",
+		ID:              msg.ID.String(),
+		Source:          source,
+		Type:            msg.Topic,
+		Subject:         msg.Key,
+		Time:            msg.CreatedAt,
+		DataContentType: "application/json",
+		Data:            msg.Payload,
+	})
+	return body, "application/cloudevents+json", err
+}
+
+func encodeJSON(msg outbox.Message) ([]byte, string, error) {
+	body, err := json.Marshal(msg)
+	return body, "application/json", err
+}
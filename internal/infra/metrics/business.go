@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	usersTotal = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "business_users_total",
+		Help: "Number of registered users.",
+	})
+	usersCreated24h = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "business_users_created_24h",
+		Help: "Number of users created in the last 24 hours.",
+	})
+	postsPublished = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "business_posts_published",
+		Help: "Number of published posts.",
+	})
+	commentsPending = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "business_comments_pending_moderation",
+		Help: "Number of comments awaiting moderation.",
+	})
+)
+
+// BusinessStats is a snapshot of the business-level counts exported as gauges.
+type BusinessStats struct {
+	TotalUsers      int64
+	UsersCreated24h int64
+	PublishedPosts  int64
+	PendingComments int64
+}
+
+// RefreshBusinessGauges sets the business gauges from stats immediately and
+// then every interval, until ctx is done. Failed refreshes keep the previous
+// values.
+func RefreshBusinessGauges(ctx context.Context, interval time.Duration, stats func(ctx context.Context) (BusinessStats, error), onError func(err error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		queryCtx, cancel := context.WithTimeout(ctx, interval)
+		s, err := stats(queryCtx)
+		cancel()
+		if err != nil {
+			onError(err)
+		} else {
+			usersTotal.Set(float64(s.TotalUsers))
+			usersCreated24h.Set(float64(s.UsersCreated24h))
+			postsPublished.Set(float64(s.PublishedPosts))
+			commentsPending.Set(float64(s.PendingComments))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
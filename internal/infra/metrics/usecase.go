@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"usermanagement/internal/application/usecase"
+	"usermanagement/internal/domain/errcode"
+)
+
+var (
+	useCaseDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "usecase_duration_seconds",
+		Help:    "Duration of application use case executions.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"usecase", "outcome"})
+	useCaseErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "usecase_errors_total",
+		Help: "Application use case failures by error type.",
+	}, []string{"usecase", "error"})
+)
+
+// UseCase decorates next so every execution is timed and failures are
+// counted under the given name.
+func UseCase[I, O any](name string, next usecase.UseCase[I, O]) usecase.UseCase[I, O] {
+	return &instrumentedUseCase[I, O]{name: name, next: next}
+}
+
+// Command decorates a result-less use case like UseCase does.
+func Command[I any](name string, next usecase.Command[I]) usecase.Command[I] {
+	return &instrumentedCommand[I]{name: name, next: next}
+}
+
+type instrumentedUseCase[I, O any] struct {
+	name string
+	next usecase.UseCase[I, O]
+}
+
+func (u *instrumentedUseCase[I, O]) Execute(ctx context.Context, input I) (O, error) {
+	start := time.Now()
+	output, err := u.next.Execute(ctx, input)
+	observe(u.name, start, err)
+	return output, err
+}
+
+type instrumentedCommand[I any] struct {
+	name string
+	next usecase.Command[I]
+}
+
+func (c *instrumentedCommand[I]) Execute(ctx context.Context, input I) error {
+	start := time.Now()
+	err := c.next.Execute(ctx, input)
+	observe(c.name, start, err)
+	return err
+}
+
+func observe(name string, start time.Time, err error) {
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+		useCaseErrors.WithLabelValues(name, errorType(err)).Inc()
+	}
+	useCaseDuration.WithLabelValues(name, outcome).Observe(time.Since(start).Seconds())
+}
+
+// errorType maps an error to a low-cardinality label: context errors by
+// kind, everything else by its error code.
+func errorType(err error) string {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	default:
+		return string(errcode.Of(err))
+	}
+}
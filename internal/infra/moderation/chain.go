@@ -0,0 +1,37 @@
+package moderation
+
+import (
+	"context"
+
+	"usermanagement/internal/domain/moderation"
+)
+
+// PolicyFunc adapts a function to moderation.Policy, e.g. to call an
+// external moderation API.
+type PolicyFunc func(ctx context.Context, content moderation.Content) (moderation.Verdict, error)
+
+// Check implements moderation.Policy.
+func (f PolicyFunc) Check(ctx context.Context, content moderation.Content) (moderation.Verdict, error) {
+	return f(ctx, content)
+}
+
+// Chain asks each policy in turn and returns the strictest verdict. It stops
+// at the first rejection, so cheap local policies should come first.
+func Chain(policies ...moderation.Policy) moderation.Policy {
+	return PolicyFunc(func(ctx context.Context, content moderation.Content) (moderation.Verdict, error) {
+		verdict := moderation.Verdict{Action: moderation.Allow}
+		for _, p := range policies {
+			v, err := p.Check(ctx, content)
+			if err != nil {
+				return moderation.Verdict{}, err
+			}
+			if v.Action > verdict.Action {
+				verdict = v
+			}
+			if verdict.Action == moderation.Reject {
+				break
+			}
+		}
+		return verdict, nil
+	})
+}
@@ -0,0 +1,48 @@
+package moderation
+
+import (
+	"context"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+
+	"usermanagement/internal/domain/moderation"
+	"usermanagement/internal/infra/logger"
+)
+
+var decisions = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "content_policy_decisions_total",
+	Help: "Content flagged or rejected by the content policy, by field and action.",
+}, []string{"field", "action"})
+
+// Reported logs and counts every flagged or rejected piece of content so
+// moderators can review it. The content itself is not logged.
+func Reported(p moderation.Policy, logger *logger.Logger) moderation.Policy {
+	return PolicyFunc(func(ctx context.Context, content moderation.Content) (moderation.Verdict, error) {
+		verdict, err := p.Check(ctx, content)
+		if err != nil {
+			return verdict, err
+		}
+
+		var action string
+		switch verdict.Action {
+		case moderation.Flag:
+			action = "flag"
+		case moderation.Reject:
+			action = "reject"
+		default:
+			return verdict, nil
+		}
+
+		decisions.WithLabelValues(string(content.Field), action).Inc()
+		logger.Info("content policy decision",
+			zap.String("field", string(content.Field)),
+			zap.String("action", action),
+			zap.String("reason", verdict.Reason),
+			zap.String("request_id", middleware.GetReqID(ctx)),
+		)
+		return verdict, nil
+	})
+}
@@ -0,0 +1,56 @@
+// Package moderation provides content policy implementations: a built-in
+// word list, a chain for combining it with external moderation services,
+// and a decorator that reports decisions for review.
+package moderation
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"usermanagement/internal/domain/moderation"
+)
+
+// WordList is a content policy matching whole words, case-insensitively,
+// against a list of rejected and a list of flagged words.
+type WordList struct {
+	reject map[string]bool
+	flag   map[string]bool
+}
+
+// NewWordList creates a word list policy. Words in reject refuse the
+// content; words in flag accept it for review. Empty lists allow everything.
+func NewWordList(reject, flag []string) *WordList {
+	return &WordList{reject: wordSet(reject), flag: wordSet(flag)}
+}
+
+// Check implements moderation.Policy.
+func (w *WordList) Check(_ context.Context, content moderation.Content) (moderation.Verdict, error) {
+	verdict := moderation.Verdict{Action: moderation.Allow}
+	for _, word := range words(content.Text) {
+		if w.reject[word] {
+			return moderation.Verdict{Action: moderation.Reject, Reason: "word list: " + word}, nil
+		}
+		if w.flag[word] && verdict.Action == moderation.Allow {
+			verdict = moderation.Verdict{Action: moderation.Flag, Reason: "word list: " + word}
+		}
+	}
+	return verdict, nil
+}
+
+func wordSet(list []string) map[string]bool {
+	set := make(map[string]bool, len(list))
+	for _, word := range list {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			set[word] = true
+		}
+	}
+	return set
+}
+
+// words splits text into lowercase words of letters and digits.
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
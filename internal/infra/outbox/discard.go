@@ -0,0 +1,41 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"usermanagement/internal/infra/logger"
+)
+
+// Discarder is an outbox nothing relays from.
+type Discarder interface {
+	DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// Discard stands in for a Relay when no publisher consumes the events of
+// store: repositories still write an event with every user change, and
+// without a relay those would pile up, with the names and emails they
+// carry, for good. Every hour until ctx is done it deletes the events
+// created longer than retention ago.
+func Discard(ctx context.Context, store Discarder, retention time.Duration, logger *logger.Logger) {
+	ticker := time.NewTicker(cleanupEvery)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := store.DeleteBefore(ctx, time.Now().Add(-retention))
+		switch {
+		case err != nil && ctx.Err() == nil:
+			logger.Error("failed to delete unrelayed outbox events", zap.Error(err))
+		case deleted > 0:
+			logger.Debug("deleted unrelayed outbox events", zap.Int64("count", deleted))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Package outbox relays events from the transactional outbox to a message
+// broker.
+//
+// Repositories insert an event in the same statement as the change it
+// describes, so no committed change lacks its event. The relay delivers
+// each event at least once: an event is marked published only after the
+// broker accepted it, so a crash in between publishes it again. A relay
+// publishes the events of an aggregate in commit order, holding later ones
+// back while an earlier one is retried, but replicas relaying the same
+// outbox or an expired claim can still reorder them. Consumers should
+// dedupe on the event ID and compare updated_at to drop stale updates.
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"usermanagement/internal/infra/logger"
+)
+
+// Message is an event as handed to a broker.
+type Message struct {
+	ID    uuid.UUID `json:"id"`
+	Topic string    `json:"topic"`
+	// Key is the ID of the changed aggregate; brokers that partition by key
+	// keep each aggregate's events in order.
+	Key       string          `json:"key"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// Publisher delivers messages to a broker. Publish returns nil only once
+// the broker has accepted the message.
+type Publisher interface {
+	Publish(ctx context.Context, msg Message) error
+}
+
+// NewPublisher returns the publisher for kind ("http", "log" or "none").
+// "http" posts every message to url; "log" only logs them, for development.
+// "none" returns a nil Publisher: events stay in the outbox.
+func NewPublisher(kind, url string, log *logger.Logger) (Publisher, error) {
+	switch kind {
+	case "none":
+		return nil, nil
+	case "log":
+		return &LogPublisher{logger: log}, nil
+	case "http":
+		if url == "" {
+			return nil, fmt.Errorf("outbox publisher %q needs a URL", kind)
+		}
+		return &HTTPPublisher{
+			url:    url,
+			client: &http.Client{Timeout: 10 * time.Second},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown outbox publisher %q", kind)
+	}
+}
+
+// HTTPPublisher posts messages as JSON to a broker's HTTP ingestion
+// endpoint or a webhook. The message ID is sent as Idempotency-Key so
+// receivers can drop redeliveries.
+type HTTPPublisher struct {
+	url    string
+	client *http.Client
+}
+
+// Publish implements Publisher. Any non-2xx response is a failure.
+func (p *HTTPPublisher) Publish(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("encode outbox message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build outbox request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", msg.ID.String())
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("publish outbox message: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("publish outbox message: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// LogPublisher logs messages instead of publishing them.
+type LogPublisher struct {
+	logger *logger.Logger
+}
+
+// Publish implements Publisher.
+func (p *LogPublisher) Publish(_ context.Context, msg Message) error {
+	p.logger.Info("outbox event",
+		zap.String("event_id", msg.ID.String()),
+		zap.String("topic", msg.Topic),
+		zap.String("key", msg.Key),
+		zap.ByteString("payload", msg.Payload),
+	)
+	return nil
+}
+
+// Fanout returns a Publisher handing every message to each of publishers in
+// turn. A failure fails the message, which the relay then retries with all
+// of them, so each must tolerate duplicates.
+func Fanout(publishers ...Publisher) Publisher {
+	return fanout(publishers)
+}
+
+type fanout []Publisher
+
+// Publish implements Publisher.
+func (f fanout) Publish(ctx context.Context, msg Message) error {
+	for _, p := range f {
+		if err := p.Publish(ctx, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+
+	"usermanagement/internal/infra/logger"
+	"usermanagement/internal/infra/persistence/postgres"
+)
+
+var published = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "outbox_publish_total",
+	Help: "Outbox publish attempts by result (published, failed).",
+}, []string{"result"})
+
+const (
+	// lease is how long claimed events are hidden from other relays. A relay
+	// that crashes mid-batch delays its events by at most this much.
+	lease = 5 * time.Minute
+	// cleanupEvery is how often published events past retention are deleted.
+	cleanupEvery = time.Hour
+	// settleTimeout bounds recording an attempt's outcome, which must not be
+	// lost to shutdown once the broker has the message.
+	settleTimeout = 5 * time.Second
+)
+
+// errEarlierEventPending postpones events whose aggregate has an earlier
+// event waiting for a retry, to keep each aggregate's events in order.
+var errEarlierEventPending = errors.New("an earlier event of this aggregate is pending")
+
+// Store is the outbox of one database.
+type Store interface {
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]postgres.OutboxEvent, error)
+	MarkPublished(ctx context.Context, seq int64) error
+	MarkFailed(ctx context.Context, seq int64, backoff time.Duration, cause error) error
+	DeletePublished(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// Settings tune a Relay.
+type Settings struct {
+	// Interval is how often the outbox is polled when idle, and the first
+	// retry delay.
+	Interval  time.Duration
+	BatchSize int
+	// MaxBackoff caps the retry delay, which doubles with every failure.
+	MaxBackoff time.Duration
+	// Retention is how long published events are kept; zero keeps them.
+	Retention time.Duration
+}
+
+// Relay publishes the events of one outbox. Several relays may share an
+// outbox; each event is claimed by one at a time.
+type Relay struct {
+	store     Store
+	publisher Publisher
+	settings  Settings
+	logger    *logger.Logger
+}
+
+// NewRelay creates a relay from store to publisher.
+func NewRelay(store Store, publisher Publisher, settings Settings, logger *logger.Logger) *Relay {
+	return &Relay{
+		store:     store,
+		publisher: publisher,
+		settings:  settings,
+		logger:    logger,
+	}
+}
+
+// Run relays events until ctx is done. Full batches are followed by the
+// next one right away, so a backlog drains without waiting for Interval.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.settings.Interval)
+	defer ticker.Stop()
+
+	lastCleanup := time.Time{}
+	for {
+		if r.settings.Retention > 0 && time.Since(lastCleanup) >= cleanupEvery {
+			r.cleanup(ctx)
+			lastCleanup = time.Now()
+		}
+
+		n := r.relayBatch(ctx)
+		if n == r.settings.BatchSize && ctx.Err() == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// relayBatch publishes one batch of due events and reports how many it
+// claimed.
+func (r *Relay) relayBatch(ctx context.Context) int {
+	events, err := r.store.Claim(ctx, r.settings.BatchSize, lease)
+	if err != nil {
+		if ctx.Err() == nil {
+			r.logger.Error("failed to claim outbox events", zap.Error(err))
+		}
+		return 0
+	}
+
+	// Stop publishing well before the lease runs out so no other relay
+	// claims the same events meanwhile.
+	publishCtx, cancel := context.WithTimeout(ctx, lease/2)
+	defer cancel()
+
+	pending := make(map[uuid.UUID]time.Duration)
+	for _, e := range events {
+		if backoff, ok := pending[e.AggregateID]; ok {
+			r.settle(ctx, e, backoff, errEarlierEventPending)
+			continue
+		}
+
+		err := publishCtx.Err()
+		if err == nil {
+			err = r.publisher.Publish(publishCtx, Message{
+				ID:        e.ID,
+				Topic:     e.Topic,
+				Key:       e.AggregateID.String(),
+				Payload:   e.Payload,
+				CreatedAt: e.CreatedAt,
+			})
+		}
+		if err != nil {
+			published.WithLabelValues("failed").Inc()
+			pending[e.AggregateID] = r.backoff(e.Attempts)
+			r.logger.Warn("failed to publish outbox event",
+				zap.String("event_id", e.ID.String()),
+				zap.String("topic", e.Topic),
+				zap.Int("attempts", e.Attempts+1),
+				zap.Error(err),
+			)
+		} else {
+			published.WithLabelValues("published").Inc()
+		}
+		r.settle(ctx, e, pending[e.AggregateID], err)
+	}
+
+	return len(events)
+}
+
+// settle records the outcome of publishing e: published when cause is nil,
+// otherwise retried after backoff.
+func (r *Relay) settle(ctx context.Context, e postgres.OutboxEvent, backoff time.Duration, cause error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), settleTimeout)
+	defer cancel()
+
+	var err error
+	if cause == nil {
+		err = r.store.MarkPublished(ctx, e.Seq)
+	} else {
+		err = r.store.MarkFailed(ctx, e.Seq, backoff, cause)
+	}
+	if err != nil {
+		// The event is claimed again once its lease expires; if it was
+		// published, consumers see a duplicate.
+		r.logger.Error("failed to settle outbox event",
+			zap.String("event_id", e.ID.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+// backoff returns the delay before retrying an event that failed attempts
+// times before: Interval, doubling per failure up to MaxBackoff.
+func (r *Relay) backoff(attempts int) time.Duration {
+	d := r.settings.Interval
+	for i := 0; i < attempts && d < r.settings.MaxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, r.settings.MaxBackoff)
+}
+
+// cleanup deletes events published longer than Retention ago.
+func (r *Relay) cleanup(ctx context.Context) {
+	deleted, err := r.store.DeletePublished(ctx, time.Now().Add(-r.settings.Retention))
+	if err != nil {
+		if ctx.Err() == nil {
+			r.logger.Error("failed to delete published outbox events", zap.Error(err))
+		}
+		return
+	}
+	if deleted > 0 {
+		r.logger.Debug("deleted published outbox events", zap.Int64("count", deleted))
+	}
+}
@@ -0,0 +1,131 @@
+package circuit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"usermanagement/internal/domain/user"
+	"usermanagement/internal/infra/breaker"
+)
+
+// UserRepository decorates a domain.UserRepository with a circuit breaker so
+// that a failing database is not hammered by every incoming request.
+type UserRepository struct {
+	next    user.UserRepository
+	breaker *breaker.Breaker
+}
+
+// NewUserRepository wraps next with the given breaker.
+func NewUserRepository(next user.UserRepository, b *breaker.Breaker) *UserRepository {
+	return &UserRepository{next: next, breaker: b}
+}
+
+// IsFailure reports whether a repository error should trip the breaker.
+// Domain outcomes such as "not found" or "email exists" are healthy answers
+// from the database and do not count.
+func IsFailure(err error) bool {
+	return errors.Is(err, user.ErrRepositoryInternal) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
+
+// Save persists a new user.
+func (r *UserRepository) Save(ctx context.Context, u *user.User) error {
+	return r.execute(func() error {
+		return r.next.Save(ctx, u)
+	})
+}
+
+// SaveBatch persists new users, skipping taken emails.
+func (r *UserRepository) SaveBatch(ctx context.Context, users []*user.User) ([]uuid.UUID, error) {
+	var saved []uuid.UUID
+	err := r.execute(func() (err error) {
+		saved, err = r.next.SaveBatch(ctx, users)
+		return err
+	})
+	return saved, err
+}
+
+// FindByID retrieves a user by ID.
+func (r *UserRepository) FindByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	var found *user.User
+	err := r.execute(func() (err error) {
+		found, err = r.next.FindByID(ctx, id)
+		return err
+	})
+	return found, err
+}
+
+// FindByEmail retrieves a user by email.
+func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*user.User, error) {
+	var found *user.User
+	err := r.execute(func() (err error) {
+		found, err = r.next.FindByEmail(ctx, email)
+		return err
+	})
+	return found, err
+}
+
+// FindAll retrieves paginated users.
+func (r *UserRepository) FindAll(ctx context.Context, limit, offset int) ([]*user.User, error) {
+	var users []*user.User
+	err := r.execute(func() (err error) {
+		users, err = r.next.FindAll(ctx, limit, offset)
+		return err
+	})
+	return users, err
+}
+
+// FindAfter retrieves users following a listing position.
+func (r *UserRepository) FindAfter(ctx context.Context, after *user.Keyset, limit int) ([]*user.User, error) {
+	var users []*user.User
+	err := r.execute(func() (err error) {
+		users, err = r.next.FindAfter(ctx, after, limit)
+		return err
+	})
+	return users, err
+}
+
+// Search finds users by name or email.
+func (r *UserRepository) Search(ctx context.Context, query string, page user.Page) (*user.SearchResult, error) {
+	var result *user.SearchResult
+	err := r.execute(func() (err error) {
+		result, err = r.next.Search(ctx, query, page)
+		return err
+	})
+	return result, err
+}
+
+// Count returns the total number of users.
+func (r *UserRepository) Count(ctx context.Context) (int64, error) {
+	var total int64
+	err := r.execute(func() (err error) {
+		total, err = r.next.Count(ctx)
+		return err
+	})
+	return total, err
+}
+
+// Update modifies an existing user.
+func (r *UserRepository) Update(ctx context.Context, u *user.User) error {
+	return r.execute(func() error {
+		return r.next.Update(ctx, u)
+	})
+}
+
+// Delete removes a user by ID.
+func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID, version int64) error {
+	return r.execute(func() error {
+		return r.next.Delete(ctx, id, version)
+	})
+}
+
+func (r *UserRepository) execute(fn func() error) error {
+	err := r.breaker.Execute(fn)
+	if errors.Is(err, breaker.ErrOpen) {
+		return fmt.Errorf("%w: %v", user.ErrRepositoryUnavailable, err)
+	}
+	return err
+}
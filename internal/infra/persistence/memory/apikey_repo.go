@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"usermanagement/internal/domain/apikey"
+)
+
+// APIKeyRepository implements apikey.Repository in memory.
+type APIKeyRepository struct {
+	mu   sync.RWMutex
+	keys map[uuid.UUID]apikey.Key
+}
+
+// NewAPIKeyRepository creates an empty API key repository.
+func NewAPIKeyRepository() *APIKeyRepository {
+	return &APIKeyRepository{keys: make(map[uuid.UUID]apikey.Key)}
+}
+
+// Save persists a new key.
+func (r *APIKeyRepository) Save(ctx context.Context, k *apikey.Key) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.keys[k.ID()] = *k
+	return nil
+}
+
+// FindByID retrieves a key by ID.
+func (r *APIKeyRepository) FindByID(ctx context.Context, id uuid.UUID) (*apikey.Key, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	k, ok := r.keys[id]
+	if !ok {
+		return nil, apikey.ErrKeyNotFound
+	}
+	return &k, nil
+}
+
+// FindByPrefix retrieves a key by its public prefix.
+func (r *APIKeyRepository) FindByPrefix(ctx context.Context, prefix string) (*apikey.Key, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, k := range r.keys {
+		if k.Prefix() == prefix {
+			return &k, nil
+		}
+	}
+	return nil, apikey.ErrKeyNotFound
+}
+
+// FindByOwner retrieves paginated keys of a user, newest first.
+func (r *APIKeyRepository) FindByOwner(ctx context.Context, ownerID uuid.UUID, limit, offset int) ([]*apikey.Key, error) {
+	r.mu.RLock()
+	keys := make([]*apikey.Key, 0)
+	for _, k := range r.keys {
+		if k.OwnerID() == ownerID {
+			k := k
+			keys = append(keys, &k)
+		}
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(keys, func(a, b *apikey.Key) int {
+		if newerFirst(a.CreatedAt(), a.ID(), b.CreatedAt(), b.ID()) {
+			return -1
+		}
+		return 1
+	})
+	return page(keys, limit, offset), nil
+}
+
+// CountByOwner returns the number of keys of a user.
+func (r *APIKeyRepository) CountByOwner(ctx context.Context, ownerID uuid.UUID) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var total int64
+	for _, k := range r.keys {
+		if k.OwnerID() == ownerID {
+			total++
+		}
+	}
+	return total, nil
+}
+
+// Revoke stores the revocation of a key, keeping an earlier one.
+func (r *APIKeyRepository) Revoke(ctx context.Context, k *apikey.Key) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.keys[k.ID()]
+	if !ok {
+		return apikey.ErrKeyNotFound
+	}
+	if !stored.Revoked() {
+		r.keys[k.ID()] = *k
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"usermanagement/internal/domain/comment"
+)
+
+// CommentRepository implements comment.CommentRepository in memory.
+type CommentRepository struct {
+	mu       sync.RWMutex
+	comments map[uuid.UUID]comment.Comment
+}
+
+// NewCommentRepository creates an empty comment repository.
+func NewCommentRepository() *CommentRepository {
+	return &CommentRepository{comments: make(map[uuid.UUID]comment.Comment)}
+}
+
+// Save persists a new comment.
+func (r *CommentRepository) Save(ctx context.Context, c *comment.Comment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.comments[c.ID()] = *c
+	return nil
+}
+
+// FindByID retrieves a comment by ID.
+func (r *CommentRepository) FindByID(ctx context.Context, id uuid.UUID) (*comment.Comment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c, ok := r.comments[id]
+	if !ok {
+		return nil, comment.ErrCommentNotFound
+	}
+	return &c, nil
+}
+
+// FindByPost retrieves paginated comments on a post in the given status,
+// oldest first.
+func (r *CommentRepository) FindByPost(ctx context.Context, postID uuid.UUID, status comment.Status, limit, offset int) ([]*comment.Comment, error) {
+	r.mu.RLock()
+	comments := make([]*comment.Comment, 0)
+	for _, c := range r.comments {
+		if c.PostID() == postID && c.Status() == status {
+			c := c
+			comments = append(comments, &c)
+		}
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(comments, func(a, b *comment.Comment) int {
+		if newerFirst(a.CreatedAt(), a.ID(), b.CreatedAt(), b.ID()) {
+			return 1
+		}
+		return -1
+	})
+	return page(comments, limit, offset), nil
+}
+
+// CountByPost returns the number of comments on a post in the given status.
+func (r *CommentRepository) CountByPost(ctx context.Context, postID uuid.UUID, status comment.Status) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var total int64
+	for _, c := range r.comments {
+		if c.PostID() == postID && c.Status() == status {
+			total++
+		}
+	}
+	return total, nil
+}
+
+// CountByStatus returns the number of comments in the given status across
+// all posts.
+func (r *CommentRepository) CountByStatus(ctx context.Context, status comment.Status) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var total int64
+	for _, c := range r.comments {
+		if c.Status() == status {
+			total++
+		}
+	}
+	return total, nil
+}
+
+// Update modifies an existing comment.
+func (r *CommentRepository) Update(ctx context.Context, c *comment.Comment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.comments[c.ID()]; !ok {
+		return comment.ErrCommentNotFound
+	}
+	r.comments[c.ID()] = *c
+	return nil
+}
+
+// Delete removes a comment and, transitively, its replies.
+func (r *CommentRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.comments[id]; !ok {
+		return comment.ErrCommentNotFound
+	}
+	r.deleteWhere(func(c comment.Comment) bool { return c.ID() == id })
+	return nil
+}
+
+// deleteByPost removes every comment on a post.
+func (r *CommentRepository) deleteByPost(postID uuid.UUID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.deleteWhere(func(c comment.Comment) bool { return c.PostID() == postID })
+}
+
+// deleteWhere removes the comments matching doomed and the replies to any
+// removed comment. Callers hold mu.
+func (r *CommentRepository) deleteWhere(doomed func(c comment.Comment) bool) {
+	deleted := make(map[uuid.UUID]bool)
+	for id, c := range r.comments {
+		if doomed(c) {
+			deleted[id] = true
+		}
+	}
+	// Replies of replies: repeat until a pass removes nothing new.
+	for grew := true; grew; {
+		grew = false
+		for id, c := range r.comments {
+			if parent := c.ParentID(); !deleted[id] && parent != nil && deleted[*parent] {
+				deleted[id] = true
+				grew = true
+			}
+		}
+	}
+	for id := range deleted {
+		delete(r.comments, id)
+	}
+}
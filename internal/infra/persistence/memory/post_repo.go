@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"usermanagement/internal/domain/post"
+)
+
+// PostRepository implements post.PostRepository in memory, keeping slugs
+// unique.
+type PostRepository struct {
+	mu     sync.RWMutex
+	posts  map[uuid.UUID]post.Post
+	bySlug map[string]uuid.UUID
+	// comments loses a post's comments when it is deleted, like the
+	// cascading foreign key in Postgres.
+	comments *CommentRepository
+}
+
+// NewPostRepository creates an empty post repository whose deletes cascade
+// to comments.
+func NewPostRepository(comments *CommentRepository) *PostRepository {
+	return &PostRepository{
+		posts:    make(map[uuid.UUID]post.Post),
+		bySlug:   make(map[string]uuid.UUID),
+		comments: comments,
+	}
+}
+
+// Save persists a new post.
+func (r *PostRepository) Save(ctx context.Context, p *post.Post) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, taken := r.bySlug[p.Slug()]; taken {
+		return post.ErrSlugExists
+	}
+	r.posts[p.ID()] = *p
+	r.bySlug[p.Slug()] = p.ID()
+	return nil
+}
+
+// FindByID retrieves a post by ID.
+func (r *PostRepository) FindByID(ctx context.Context, id uuid.UUID) (*post.Post, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, ok := r.posts[id]
+	if !ok {
+		return nil, post.ErrPostNotFound
+	}
+	return &p, nil
+}
+
+// FindByStatus retrieves paginated posts in the given status, newest first.
+func (r *PostRepository) FindByStatus(ctx context.Context, status post.Status, limit, offset int) ([]*post.Post, error) {
+	r.mu.RLock()
+	posts := make([]*post.Post, 0, len(r.posts))
+	for _, p := range r.posts {
+		if p.Status() == status {
+			p := p
+			posts = append(posts, &p)
+		}
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(posts, func(a, b *post.Post) int {
+		if newerFirst(a.CreatedAt(), a.ID(), b.CreatedAt(), b.ID()) {
+			return -1
+		}
+		return 1
+	})
+	return page(posts, limit, offset), nil
+}
+
+// CountByStatus returns the number of posts in the given status.
+func (r *PostRepository) CountByStatus(ctx context.Context, status post.Status) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var total int64
+	for _, p := range r.posts {
+		if p.Status() == status {
+			total++
+		}
+	}
+	return total, nil
+}
+
+// Update modifies an existing post.
+func (r *PostRepository) Update(ctx context.Context, p *post.Post) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	old, ok := r.posts[p.ID()]
+	if !ok {
+		return post.ErrPostNotFound
+	}
+	if owner, taken := r.bySlug[p.Slug()]; taken && owner != p.ID() {
+		return post.ErrSlugExists
+	}
+	delete(r.bySlug, old.Slug())
+	r.posts[p.ID()] = *p
+	r.bySlug[p.Slug()] = p.ID()
+	return nil
+}
+
+// Delete removes a post by ID, along with its comments.
+func (r *PostRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.posts[id]
+	if !ok {
+		return post.ErrPostNotFound
+	}
+	delete(r.posts, id)
+	delete(r.bySlug, p.Slug())
+	r.comments.deleteByPost(id)
+	return nil
+}
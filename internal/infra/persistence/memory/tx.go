@@ -0,0 +1,34 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"usermanagement/internal/domain/transaction"
+)
+
+// UnitOfWork implements transaction.UnitOfWork by running units of work one
+// at a time, which keeps their read-modify-write sequences from
+// interleaving. There is no rollback: writes made before fn fails remain.
+type UnitOfWork struct {
+	mu sync.Mutex
+}
+
+// NewUnitOfWork creates a unit of work for the memory repositories.
+func NewUnitOfWork() *UnitOfWork {
+	return &UnitOfWork{}
+}
+
+// workKey marks contexts already inside a unit of work.
+type workKey struct{}
+
+// Do implements transaction.UnitOfWork.
+func (u *UnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if ctx.Value(workKey{}) == u {
+		return fn(ctx)
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return fn(context.WithValue(transaction.WithActive(ctx), workKey{}, u))
+}
@@ -0,0 +1,254 @@
+// Package memory keeps repositories in process memory, for running the
+// service without a database in demos and for use case tests. Nothing
+// survives a restart, and replicas do not share data.
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"usermanagement/internal/domain/user"
+)
+
+// UserRepository implements user.UserRepository in memory. Like the
+// Postgres one it keeps emails unique, and entities are copied in and out so
+// callers never share state with the store.
+type UserRepository struct {
+	mu      sync.RWMutex
+	users   map[uuid.UUID]user.User
+	byEmail map[string]uuid.UUID
+}
+
+// NewUserRepository creates an empty user repository.
+func NewUserRepository() *UserRepository {
+	return &UserRepository{
+		users:   make(map[uuid.UUID]user.User),
+		byEmail: make(map[string]uuid.UUID),
+	}
+}
+
+// Save persists a new user.
+func (r *UserRepository) Save(ctx context.Context, u *user.User) error {
+	if u == nil {
+		return user.ErrNilUser
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, taken := r.byEmail[u.Email()]; taken {
+		return user.ErrEmailExists
+	}
+	r.users[u.ID()] = *u
+	r.byEmail[u.Email()] = u.ID()
+	return nil
+}
+
+// SaveBatch persists new users, skipping those whose email is taken.
+func (r *UserRepository) SaveBatch(ctx context.Context, users []*user.User) ([]uuid.UUID, error) {
+	if slices.Contains(users, nil) {
+		return nil, user.ErrNilUser
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	saved := make([]uuid.UUID, 0, len(users))
+	for _, u := range users {
+		if _, taken := r.byEmail[u.Email()]; taken {
+			continue
+		}
+		r.users[u.ID()] = *u
+		r.byEmail[u.Email()] = u.ID()
+		saved = append(saved, u.ID())
+	}
+	return saved, nil
+}
+
+// FindByID retrieves a user by ID.
+func (r *UserRepository) FindByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	u, ok := r.users[id]
+	if !ok {
+		return nil, user.ErrUserNotFound
+	}
+	return &u, nil
+}
+
+// FindByEmail retrieves a user by email.
+func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*user.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, ok := r.byEmail[email]
+	if !ok {
+		return nil, user.ErrUserNotFound
+	}
+	u := r.users[id]
+	return &u, nil
+}
+
+// FindAll retrieves paginated users, newest first.
+func (r *UserRepository) FindAll(ctx context.Context, limit, offset int) ([]*user.User, error) {
+	return page(r.sorted(nil), limit, offset), nil
+}
+
+// FindAfter retrieves up to limit users following after, newest first.
+func (r *UserRepository) FindAfter(ctx context.Context, after *user.Keyset, limit int) ([]*user.User, error) {
+	return page(r.sorted(after), limit, 0), nil
+}
+
+// sorted returns copies of the users following after in listing order.
+func (r *UserRepository) sorted(after *user.Keyset) []*user.User {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := make([]*user.User, 0, len(r.users))
+	for _, u := range r.users {
+		if after != nil && !newerFirst(after.CreatedAt, after.ID, u.CreatedAt(), u.ID()) {
+			continue
+		}
+		u := u
+		users = append(users, &u)
+	}
+	slices.SortFunc(users, func(a, b *user.User) int {
+		if newerFirst(a.CreatedAt(), a.ID(), b.CreatedAt(), b.ID()) {
+			return -1
+		}
+		return 1
+	})
+	return users
+}
+
+// Search finds users whose name or email contains every word of query,
+// ignoring case. Words found in the name rank above words only found in
+// the email. There is no fuzzy matching.
+func (r *UserRepository) Search(ctx context.Context, query string, pg user.Page) (*user.SearchResult, error) {
+	terms := strings.Fields(strings.ToLower(query))
+
+	var hits []user.SearchHit
+	for _, u := range r.sorted(nil) {
+		name, email := strings.ToLower(u.Name()), strings.ToLower(u.Email())
+		var rank float64
+		for _, term := range terms {
+			switch {
+			case strings.Contains(name, term):
+				rank += 2
+			case strings.Contains(email, term):
+				rank++
+			default:
+				rank = 0
+			}
+			if rank == 0 {
+				break
+			}
+		}
+		if rank > 0 {
+			hits = append(hits, user.SearchHit{User: u, Rank: rank})
+		}
+	}
+	// Stable, so equal ranks stay newest first.
+	slices.SortStableFunc(hits, func(a, b user.SearchHit) int {
+		return cmp.Compare(b.Rank, a.Rank)
+	})
+
+	return &user.SearchResult{Hits: page(hits, pg.Limit, pg.Offset), Total: int64(len(hits))}, nil
+}
+
+// Count returns the total number of users.
+func (r *UserRepository) Count(ctx context.Context) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return int64(len(r.users)), nil
+}
+
+// Update modifies an existing user if it is still at u's version.
+func (r *UserRepository) Update(ctx context.Context, u *user.User) error {
+	if u == nil {
+		return user.ErrNilUser
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	old, ok := r.users[u.ID()]
+	if !ok {
+		return user.ErrUserNotFound
+	}
+	if err := old.CheckVersion(u.Version()); err != nil {
+		return err
+	}
+	if owner, taken := r.byEmail[u.Email()]; taken && owner != u.ID() {
+		return user.ErrEmailExists
+	}
+	delete(r.byEmail, old.Email())
+	u.AdvanceVersion()
+	r.users[u.ID()] = *u
+	r.byEmail[u.Email()] = u.ID()
+	return nil
+}
+
+// Delete removes a user by ID if it is still at version, or at any version
+// when version is 0.
+func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID, version int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	u, ok := r.users[id]
+	if !ok {
+		return user.ErrUserNotFound
+	}
+	if version != 0 {
+		if err := u.CheckVersion(version); err != nil {
+			return err
+		}
+	}
+	delete(r.users, id)
+	delete(r.byEmail, u.Email())
+	return nil
+}
+
+// CountUsers returns the total number of users and how many were created at
+// or after since.
+func (r *UserRepository) CountUsers(ctx context.Context, since time.Time) (total, created int64, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, u := range r.users {
+		if !u.CreatedAt().Before(since) {
+			created++
+		}
+	}
+	return int64(len(r.users)), created, nil
+}
+
+// newerFirst reports whether the entity created at aAt with ID aID sorts
+// before the one at bAt with bID, newest first with ties broken by ID, like
+// ORDER BY created_at DESC, id DESC.
+func newerFirst(aAt time.Time, aID uuid.UUID, bAt time.Time, bID uuid.UUID) bool {
+	if !aAt.Equal(bAt) {
+		return aAt.After(bAt)
+	}
+	return slices.Compare(aID[:], bID[:]) > 0
+}
+
+// page applies LIMIT and OFFSET to items.
+func page[T any](items []T, limit, offset int) []T {
+	if offset >= len(items) {
+		return nil
+	}
+	items = items[offset:]
+	if limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"usermanagement/internal/domain/webhook"
+)
+
+// WebhookRepository implements webhook.SubscriptionRepository and
+// webhook.DeliveryRepository in memory. Memory storage has no outbox, so
+// nothing is ever delivered and the delivery log stays empty.
+type WebhookRepository struct {
+	mu   sync.RWMutex
+	subs map[uuid.UUID]webhook.Subscription
+}
+
+// NewWebhookRepository creates an empty webhook repository.
+func NewWebhookRepository() *WebhookRepository {
+	return &WebhookRepository{subs: make(map[uuid.UUID]webhook.Subscription)}
+}
+
+// Save persists a new subscription.
+func (r *WebhookRepository) Save(ctx context.Context, s *webhook.Subscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.subs[s.ID()] = *s
+	return nil
+}
+
+// FindByID retrieves a subscription by ID.
+func (r *WebhookRepository) FindByID(ctx context.Context, id uuid.UUID) (*webhook.Subscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s, ok := r.subs[id]
+	if !ok {
+		return nil, webhook.ErrSubscriptionNotFound
+	}
+	return &s, nil
+}
+
+// FindByOwner retrieves paginated subscriptions of a user, newest first.
+func (r *WebhookRepository) FindByOwner(ctx context.Context, ownerID uuid.UUID, limit, offset int) ([]*webhook.Subscription, error) {
+	r.mu.RLock()
+	subs := make([]*webhook.Subscription, 0)
+	for _, s := range r.subs {
+		if s.OwnerID() == ownerID {
+			s := s
+			subs = append(subs, &s)
+		}
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(subs, func(a, b *webhook.Subscription) int {
+		if newerFirst(a.CreatedAt(), a.ID(), b.CreatedAt(), b.ID()) {
+			return -1
+		}
+		return 1
+	})
+	return page(subs, limit, offset), nil
+}
+
+// CountByOwner returns the number of subscriptions of a user.
+func (r *WebhookRepository) CountByOwner(ctx context.Context, ownerID uuid.UUID) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var total int64
+	for _, s := range r.subs {
+		if s.OwnerID() == ownerID {
+			total++
+		}
+	}
+	return total, nil
+}
+
+// Delete removes a subscription.
+func (r *WebhookRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.subs[id]; !ok {
+		return webhook.ErrSubscriptionNotFound
+	}
+	delete(r.subs, id)
+	return nil
+}
+
+// FindBySubscription returns no deliveries; see WebhookRepository.
+func (r *WebhookRepository) FindBySubscription(ctx context.Context, subscriptionID uuid.UUID, limit, offset int) ([]*webhook.Delivery, error) {
+	return nil, nil
+}
+
+// CountBySubscription returns zero; see WebhookRepository.
+func (r *WebhookRepository) CountBySubscription(ctx context.Context, subscriptionID uuid.UUID) (int64, error) {
+	return 0, nil
+}
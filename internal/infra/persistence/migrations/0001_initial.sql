@@ -0,0 +1,47 @@
+CREATE TABLE IF NOT EXISTS users (
+    id            UUID PRIMARY KEY,
+    name          TEXT NOT NULL,
+    email         TEXT NOT NULL UNIQUE,
+    -- bcrypt hash; empty for users created before passwords were supported
+    password_hash TEXT NOT NULL DEFAULT '',
+    role          TEXT NOT NULL DEFAULT 'viewer',
+    created_at    TIMESTAMPTZ NOT NULL,
+    updated_at    TIMESTAMPTZ NOT NULL
+);
+
+-- Databases initialized before these columns existed
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_hash TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role TEXT NOT NULL DEFAULT 'viewer';
+
+-- Posts live on the primary database. author_id has no foreign key because
+-- users may be sharded across other databases.
+CREATE TABLE IF NOT EXISTS posts (
+    id           UUID PRIMARY KEY,
+    author_id    UUID NOT NULL,
+    title        TEXT NOT NULL,
+    slug         TEXT NOT NULL UNIQUE,
+    body         TEXT NOT NULL,
+    status       TEXT NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL,
+    updated_at   TIMESTAMPTZ NOT NULL,
+    published_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS posts_status_created_at_idx ON posts (status, created_at DESC);
+CREATE INDEX IF NOT EXISTS posts_author_id_idx ON posts (author_id);
+
+-- Comments sit next to their posts; deleting a post or comment removes its
+-- replies.
+CREATE TABLE IF NOT EXISTS comments (
+    id         UUID PRIMARY KEY,
+    post_id    UUID NOT NULL REFERENCES posts (id) ON DELETE CASCADE,
+    author_id  UUID NOT NULL,
+    parent_id  UUID REFERENCES comments (id) ON DELETE CASCADE,
+    body       TEXT NOT NULL,
+    status     TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS comments_post_id_status_created_at_idx ON comments (post_id, status, created_at);
+CREATE INDEX IF NOT EXISTS comments_parent_id_idx ON comments (parent_id);
@@ -0,0 +1,2 @@
+-- Serves user listings, including keyset pagination on (created_at, id).
+CREATE INDEX IF NOT EXISTS users_created_at_id_idx ON users (created_at DESC, id DESC);
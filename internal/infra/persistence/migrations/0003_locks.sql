@@ -0,0 +1,8 @@
+-- Leases behind internal/infra/lock. Rows are kept after release so each
+-- name's fencing token keeps increasing.
+CREATE TABLE IF NOT EXISTS locks (
+    name       TEXT PRIMARY KEY,
+    owner      TEXT NOT NULL,
+    token      BIGINT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
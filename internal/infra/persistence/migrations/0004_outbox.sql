@@ -0,0 +1,18 @@
+-- Transactional outbox: user changes insert their event here in the same
+-- statement as the change, and internal/infra/outbox relays it to the
+-- broker. Users may be sharded, so every database holding users has one.
+CREATE TABLE IF NOT EXISTS outbox (
+    seq             BIGSERIAL PRIMARY KEY,
+    event_id        UUID NOT NULL UNIQUE,
+    topic           TEXT NOT NULL,
+    aggregate_id    UUID NOT NULL,
+    payload         JSONB NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL,
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL,
+    last_error      TEXT,
+    published_at    TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS outbox_pending_idx ON outbox (next_attempt_at) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS outbox_published_at_idx ON outbox (published_at) WHERE published_at IS NOT NULL;
@@ -0,0 +1,14 @@
+-- User search. search holds the words of name and email (split at "@" and
+-- "."), with name words weighted higher; the trigram indexes catch partial
+-- words and typos. Adding the column rewrites users once. pg_trgm ships
+-- with Postgres but creating it needs the CREATE privilege on the database.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS search TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', name), 'A') ||
+    setweight(to_tsvector('simple', translate(email, '@.', '  ')), 'B')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS users_search_idx ON users USING GIN (search);
+CREATE INDEX IF NOT EXISTS users_name_trgm_idx ON users USING GIN (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS users_email_trgm_idx ON users USING GIN (email gin_trgm_ops);
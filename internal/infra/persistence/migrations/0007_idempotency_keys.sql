@@ -0,0 +1,15 @@
+-- Responses to requests sent with an Idempotency-Key, replayed when the
+-- request is retried. status is NULL while the first request is running;
+-- its claim may be taken over once locked_until passes, in case the replica
+-- serving it died.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    key          TEXT PRIMARY KEY,
+    fingerprint  TEXT NOT NULL,
+    status       INTEGER,
+    header       JSONB,
+    body         BYTEA,
+    locked_until TIMESTAMPTZ NOT NULL,
+    expires_at   TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idempotency_keys_expires_at_idx ON idempotency_keys (expires_at);
@@ -0,0 +1,15 @@
+-- API keys for service-to-service requests. Only a SHA-256 hash of each
+-- key's secret is stored; prefix is the public part requests look keys up
+-- by. Revoked keys are kept for the owner's listing.
+CREATE TABLE IF NOT EXISTS api_keys (
+    id          UUID PRIMARY KEY,
+    owner_id    UUID NOT NULL,
+    name        TEXT NOT NULL,
+    prefix      TEXT NOT NULL UNIQUE,
+    secret_hash BYTEA NOT NULL,
+    scopes      TEXT[] NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL,
+    revoked_at  TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS api_keys_owner_idx ON api_keys (owner_id, created_at DESC);
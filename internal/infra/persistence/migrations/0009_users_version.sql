@@ -0,0 +1,4 @@
+-- Optimistic concurrency: every stored update adds one to version, and
+-- updates based on an older version are refused. Existing and new users
+-- start at 1.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
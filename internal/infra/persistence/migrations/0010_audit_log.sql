@@ -0,0 +1,13 @@
+-- Audit trail of changes made to users outside the API, such as admin jobs.
+-- Entries are written in the same transaction as the change they describe,
+-- so like the outbox every database holding users has one.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id         UUID PRIMARY KEY,
+    actor      TEXT NOT NULL,
+    action     TEXT NOT NULL,
+    subject_id UUID NOT NULL,
+    details    JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_subject_idx ON audit_log (subject_id, created_at);
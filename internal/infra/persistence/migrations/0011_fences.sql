@@ -0,0 +1,9 @@
+-- Fencing tokens of the lock holders that wrote to this database, see
+-- internal/infra/lock. Each guarded write checks its token here in its own
+-- transaction, so a holder whose lease lapsed cannot commit once a newer
+-- holder has written. Like the audit log every database holding users has
+-- one.
+CREATE TABLE IF NOT EXISTS fences (
+    name  TEXT PRIMARY KEY,
+    token BIGINT NOT NULL
+);
@@ -0,0 +1,3 @@
+-- Serves the pending moderation gauge, which counts comments by status
+-- across all posts.
+CREATE INDEX IF NOT EXISTS comments_pending_idx ON comments (created_at) WHERE status = 'pending';
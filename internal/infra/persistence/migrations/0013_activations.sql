@@ -0,0 +1,9 @@
+-- Activations of users who signed up: a user with a row here may not log
+-- in until the token mailed to them comes back. Kept on the primary
+-- database, like API keys, whichever shard holds the user; tokens are
+-- stored hashed.
+CREATE TABLE IF NOT EXISTS activations (
+    user_id    UUID PRIMARY KEY,
+    token_hash BYTEA NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,40 @@
+// Package mongo implements the user repository on MongoDB, for
+// deployments whose infrastructure is document-store based. Only users live
+// there; posts, comments and the rest stay on the configured storage.
+//
+// MongoDB writes do not join the unit of work of that storage, so user
+// changes are not recorded in its outbox: no user events are published or
+// delivered to webhooks. Concurrent updates are still caught by the user's
+// version.
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"usermanagement/internal/infra/config"
+)
+
+// Connect opens a client for the configured deployment, pings it and
+// returns the configured database.
+func Connect(ctx context.Context, cfg config.MongoConfig) (*mongodriver.Database, error) {
+	opts := options.Client().ApplyURI(cfg.URI).SetAppName("usermanagement")
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid MongoDB configuration: %w", err)
+	}
+
+	client, err := mongodriver.Connect(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
+	}
+
+	if err := client.Ping(ctx, nil); err != nil {
+		client.Disconnect(context.Background())
+		return nil, fmt.Errorf("failed to ping MongoDB: %w", err)
+	}
+
+	return client.Database(cfg.Database), nil
+}
@@ -0,0 +1,418 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+
+	"usermanagement/internal/domain/user"
+	"usermanagement/internal/infra/logger"
+)
+
+// UserRepository implements domain.UserRepository using a MongoDB
+// collection. IDs are stored as standard BSON UUIDs (binary subtype 4), and
+// times to the millisecond, as BSON dates are.
+type UserRepository struct {
+	users  *mongodriver.Collection
+	logger *logger.Logger
+}
+
+// usersCollection holds one document per user.
+const usersCollection = "users"
+
+// userDocument is the stored form of a user.
+type userDocument struct {
+	ID           primitive.Binary `bson:"_id"`
+	Name         string           `bson:"name"`
+	Email        string           `bson:"email"`
+	PasswordHash string           `bson:"password_hash"`
+	Role         string           `bson:"role"`
+	CreatedAt    time.Time        `bson:"created_at"`
+	UpdatedAt    time.Time        `bson:"updated_at"`
+	Version      int64            `bson:"version"`
+}
+
+// NewUserRepository creates a new MongoDB user repository on db.
+func NewUserRepository(db *mongodriver.Database, logger *logger.Logger) *UserRepository {
+	return &UserRepository{
+		users:  db.Collection(usersCollection),
+		logger: logger,
+	}
+}
+
+// EnsureIndexes creates the indexes the repository relies on: emails are
+// unique, and listings walk (created_at, _id) newest first. Indexes that
+// exist already are left alone.
+func (r *UserRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.users.Indexes().CreateMany(ctx, []mongodriver.IndexModel{
+		{
+			Keys:    bson.D{{Key: "email", Value: 1}},
+			Options: options.Index().SetName("email_unique").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetName("created_at_id"),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("create user indexes: %w", err)
+	}
+	return nil
+}
+
+// Save persists a new user.
+func (r *UserRepository) Save(ctx context.Context, u *user.User) error {
+	if _, err := r.users.InsertOne(ctx, toDocument(u)); err != nil {
+		if mongodriver.IsDuplicateKeyError(err) {
+			return user.ErrEmailExists
+		}
+		r.logger.Error("failed to save user", zap.Error(err))
+		return fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+
+	return nil
+}
+
+// SaveBatch persists users in one unordered insert, so users whose email is
+// taken are skipped instead of failing the batch.
+func (r *UserRepository) SaveBatch(ctx context.Context, users []*user.User) ([]uuid.UUID, error) {
+	if len(users) == 0 {
+		return nil, nil
+	}
+
+	docs := make([]any, len(users))
+	for i, u := range users {
+		docs[i] = toDocument(u)
+	}
+
+	skipped := make(map[int]bool)
+	_, err := r.users.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	if err != nil {
+		var bulkErr mongodriver.BulkWriteException
+		if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil {
+			r.logger.Error("failed to save users", zap.Error(err))
+			return nil, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+		}
+		for _, writeErr := range bulkErr.WriteErrors {
+			if !mongodriver.IsDuplicateKeyError(writeErr) {
+				r.logger.Error("failed to save users", zap.Error(err))
+				return nil, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+			}
+			skipped[writeErr.Index] = true
+		}
+	}
+
+	saved := make([]uuid.UUID, 0, len(users))
+	for i, u := range users {
+		if !skipped[i] {
+			saved = append(saved, u.ID())
+		}
+	}
+	return saved, nil
+}
+
+// FindByID retrieves a user by ID.
+func (r *UserRepository) FindByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	return r.findOne(ctx, bson.D{{Key: "_id", Value: binaryUUID(id)}})
+}
+
+// FindByEmail retrieves a user by email.
+func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*user.User, error) {
+	return r.findOne(ctx, bson.D{{Key: "email", Value: email}})
+}
+
+// findOne retrieves the user matching filter.
+func (r *UserRepository) findOne(ctx context.Context, filter bson.D) (*user.User, error) {
+	var doc userDocument
+	if err := r.users.FindOne(ctx, filter).Decode(&doc); err != nil {
+		if errors.Is(err, mongodriver.ErrNoDocuments) {
+			return nil, user.ErrUserNotFound
+		}
+		r.logger.Error("failed to find user", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+
+	u, err := doc.toDomain()
+	if err != nil {
+		r.logger.Error("failed to decode user", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+	return u, nil
+}
+
+// newestFirst is the listing order, like ORDER BY created_at DESC, id DESC.
+var newestFirst = bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}
+
+// FindAll retrieves paginated users, newest first.
+func (r *UserRepository) FindAll(ctx context.Context, limit, offset int) ([]*user.User, error) {
+	opts := options.Find().SetSort(newestFirst).SetLimit(int64(limit)).SetSkip(int64(offset))
+	return r.findMany(ctx, bson.D{}, opts)
+}
+
+// FindAfter retrieves up to limit users following after, newest first.
+func (r *UserRepository) FindAfter(ctx context.Context, after *user.Keyset, limit int) ([]*user.User, error) {
+	if after == nil {
+		return r.FindAll(ctx, limit, 0)
+	}
+
+	filter := bson.D{{Key: "$or", Value: bson.A{
+		bson.D{{Key: "created_at", Value: bson.D{{Key: "$lt", Value: after.CreatedAt}}}},
+		bson.D{
+			{Key: "created_at", Value: after.CreatedAt},
+			{Key: "_id", Value: bson.D{{Key: "$lt", Value: binaryUUID(after.ID)}}},
+		},
+	}}}
+	opts := options.Find().SetSort(newestFirst).SetLimit(int64(limit))
+	return r.findMany(ctx, filter, opts)
+}
+
+// findMany runs a find and hydrates every document.
+func (r *UserRepository) findMany(ctx context.Context, filter bson.D, opts *options.FindOptions) ([]*user.User, error) {
+	cursor, err := r.users.Find(ctx, filter, opts)
+	if err != nil {
+		r.logger.Error("failed to list users", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+	defer cursor.Close(ctx)
+
+	var users []*user.User
+	for cursor.Next(ctx) {
+		var doc userDocument
+		if err := cursor.Decode(&doc); err != nil {
+			r.logger.Error("failed to decode user document", zap.Error(err))
+			return nil, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+		}
+		u, err := doc.toDomain()
+		if err != nil {
+			r.logger.Error("failed to decode user document", zap.Error(err))
+			return nil, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+		}
+
+		users = append(users, u)
+	}
+
+	if err := cursor.Err(); err != nil {
+		r.logger.Error("error iterating user documents", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+
+	return users, nil
+}
+
+// Search finds users whose name or email contains every word of query,
+// ignoring case, like memory storage does: words found in the name rank
+// twice as high as those found in the email.
+func (r *UserRepository) Search(ctx context.Context, query string, page user.Page) (*user.SearchResult, error) {
+	terms := strings.Fields(query)
+	if len(terms) == 0 {
+		return &user.SearchResult{}, nil
+	}
+
+	match := make(bson.A, len(terms))
+	rank := make(bson.A, len(terms))
+	for i, term := range terms {
+		pattern := regexp.QuoteMeta(term)
+		match[i] = bson.D{{Key: "$or", Value: bson.A{
+			bson.D{{Key: "name", Value: primitive.Regex{Pattern: pattern, Options: "i"}}},
+			bson.D{{Key: "email", Value: primitive.Regex{Pattern: pattern, Options: "i"}}},
+		}}}
+		rank[i] = bson.D{{Key: "$cond", Value: bson.A{
+			bson.D{{Key: "$regexMatch", Value: bson.D{
+				{Key: "input", Value: "$name"},
+				{Key: "regex", Value: pattern},
+				{Key: "options", Value: "i"},
+			}}},
+			2,
+			1,
+		}}}
+	}
+
+	pipeline := mongodriver.Pipeline{
+		{{Key: "$match", Value: bson.D{{Key: "$and", Value: match}}}},
+		{{Key: "$addFields", Value: bson.D{{Key: "rank", Value: bson.D{{Key: "$add", Value: rank}}}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "rank", Value: -1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}}},
+		{{Key: "$facet", Value: bson.D{
+			{Key: "hits", Value: bson.A{
+				bson.D{{Key: "$skip", Value: page.Offset}},
+				bson.D{{Key: "$limit", Value: page.Limit}},
+			}},
+			{Key: "total", Value: bson.A{bson.D{{Key: "$count", Value: "n"}}}},
+		}}},
+	}
+
+	cursor, err := r.users.Aggregate(ctx, pipeline)
+	if err != nil {
+		r.logger.Error("failed to search users", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+	defer cursor.Close(ctx)
+
+	var facets []struct {
+		Hits []struct {
+			userDocument `bson:",inline"`
+			Rank         float64 `bson:"rank"`
+		} `bson:"hits"`
+		Total []struct {
+			N int64 `bson:"n"`
+		} `bson:"total"`
+	}
+	if err := cursor.All(ctx, &facets); err != nil {
+		r.logger.Error("failed to decode user search results", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+
+	result := &user.SearchResult{}
+	if len(facets) == 0 {
+		return result, nil
+	}
+	for _, hit := range facets[0].Hits {
+		u, err := hit.toDomain()
+		if err != nil {
+			r.logger.Error("failed to decode user document", zap.Error(err))
+			return nil, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+		}
+		result.Hits = append(result.Hits, user.SearchHit{User: u, Rank: hit.Rank})
+	}
+	if len(facets[0].Total) > 0 {
+		result.Total = facets[0].Total[0].N
+	}
+
+	return result, nil
+}
+
+// Count returns the total number of users.
+func (r *UserRepository) Count(ctx context.Context) (int64, error) {
+	total, err := r.users.CountDocuments(ctx, bson.D{})
+	if err != nil {
+		r.logger.Error("failed to count users", zap.Error(err))
+		return 0, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+
+	return total, nil
+}
+
+// Update modifies an existing user if it is still at u's version.
+func (r *UserRepository) Update(ctx context.Context, u *user.User) error {
+	filter := bson.D{
+		{Key: "_id", Value: binaryUUID(u.ID())},
+		{Key: "version", Value: u.Version()},
+	}
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "name", Value: u.Name()},
+			{Key: "email", Value: u.Email()},
+			{Key: "password_hash", Value: u.PasswordHash()},
+			{Key: "role", Value: string(u.Role())},
+			{Key: "updated_at", Value: u.UpdatedAt()},
+		}},
+		{Key: "$inc", Value: bson.D{{Key: "version", Value: 1}}},
+	}
+
+	result, err := r.users.UpdateOne(ctx, filter, update)
+	if err != nil {
+		if mongodriver.IsDuplicateKeyError(err) {
+			return user.ErrEmailExists
+		}
+		r.logger.Error("failed to update user", zap.Error(err))
+		return fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+
+	if result.MatchedCount == 0 {
+		return r.updateMissed(ctx, u.ID())
+	}
+
+	u.AdvanceVersion()
+	return nil
+}
+
+// updateMissed explains an update or delete that matched no document: the
+// user is gone, or another update moved its version on.
+func (r *UserRepository) updateMissed(ctx context.Context, id uuid.UUID) error {
+	n, err := r.users.CountDocuments(ctx, bson.D{{Key: "_id", Value: binaryUUID(id)}})
+	if err != nil {
+		r.logger.Error("failed to check user existence", zap.Error(err))
+		return fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+
+	if n == 0 {
+		return user.ErrUserNotFound
+	}
+	return user.ErrVersionConflict
+}
+
+// Delete removes a user by ID if it is still at version, or at any version
+// when version is 0.
+func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID, version int64) error {
+	filter := bson.D{{Key: "_id", Value: binaryUUID(id)}}
+	if version != 0 {
+		filter = append(filter, bson.E{Key: "version", Value: version})
+	}
+	result, err := r.users.DeleteOne(ctx, filter)
+	if err != nil {
+		r.logger.Error("failed to delete user", zap.Error(err))
+		return fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+
+	if result.DeletedCount == 0 {
+		return r.updateMissed(ctx, id)
+	}
+
+	return nil
+}
+
+// CountUsers returns the total number of users and how many were created at
+// or after since.
+func (r *UserRepository) CountUsers(ctx context.Context, since time.Time) (total, created int64, err error) {
+	total, err = r.users.CountDocuments(ctx, bson.D{})
+	if err == nil {
+		created, err = r.users.CountDocuments(ctx, bson.D{{Key: "created_at", Value: bson.D{{Key: "$gte", Value: since}}}})
+	}
+	if err != nil {
+		r.logger.Error("failed to count users", zap.Error(err))
+		return 0, 0, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+
+	return total, created, nil
+}
+
+// binaryUUID encodes id as a standard BSON UUID.
+func binaryUUID(id uuid.UUID) primitive.Binary {
+	return primitive.Binary{Subtype: bson.TypeBinaryUUID, Data: id[:]}
+}
+
+// toDocument converts u to its stored form.
+func toDocument(u *user.User) userDocument {
+	return userDocument{
+		ID:           binaryUUID(u.ID()),
+		Name:         u.Name(),
+		Email:        u.Email(),
+		PasswordHash: u.PasswordHash(),
+		Role:         string(u.Role()),
+		CreatedAt:    u.CreatedAt(),
+		UpdatedAt:    u.UpdatedAt(),
+		Version:      u.Version(),
+	}
+}
+
+// toDomain hydrates a user from its stored form.
+func (d userDocument) toDomain() (*user.User, error) {
+	if d.ID.Subtype != bson.TypeBinaryUUID {
+		return nil, fmt.Errorf("user _id has binary subtype %#x, not a UUID", d.ID.Subtype)
+	}
+	id, err := uuid.FromBytes(d.ID.Data)
+	if err != nil {
+		return nil, fmt.Errorf("user _id: %w", err)
+	}
+
+	return user.Reconstruct(id, d.Name, d.Email, d.PasswordHash, user.Role(d.Role), d.CreatedAt, d.UpdatedAt, d.Version), nil
+}
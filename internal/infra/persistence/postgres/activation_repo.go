@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"usermanagement/internal/domain/user"
+	"usermanagement/internal/infra/logger"
+)
+
+// ActivationRepository implements user.ActivationRepository using
+// PostgreSQL.
+type ActivationRepository struct {
+	db     DB
+	logger *logger.Logger
+}
+
+// NewActivationRepository creates a new PostgreSQL activation repository.
+func NewActivationRepository(db DB, logger *logger.Logger) *ActivationRepository {
+	return &ActivationRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Save records a pending activation and, in the same statement, the
+// user.activation_requested event carrying request.
+func (r *ActivationRepository) Save(ctx context.Context, a user.Activation, request user.ActivationRequest) error {
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+
+	query := `
+		WITH saved AS (
+			INSERT INTO activations (user_id, token_hash, expires_at)
+			VALUES ($1, $2, $3)
+			RETURNING user_id
+		)
+		INSERT INTO outbox (event_id, topic, aggregate_id, payload, created_at, next_attempt_at)
+		SELECT $4::uuid, $5::text, user_id, $6::jsonb, $7::timestamptz, $7::timestamptz FROM saved
+	`
+
+	_, err = conn(ctx, r.db).Exec(ctx, query,
+		a.UserID,
+		a.TokenHash,
+		a.ExpiresAt,
+		uuid.New(),
+		user.TopicActivationRequested,
+		string(payload),
+		time.Now().UTC(),
+	)
+	if err != nil {
+		r.logger.Error("failed to save activation", zap.Error(err))
+		return fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+
+	return nil
+}
+
+// Pending reports whether the user still awaits activation.
+func (r *ActivationRepository) Pending(ctx context.Context, userID uuid.UUID) (bool, error) {
+	var pending bool
+	err := conn(ctx, r.db).QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM activations WHERE user_id = $1)`, userID).Scan(&pending)
+	if err != nil {
+		r.logger.Error("failed to check activation", zap.Error(err))
+		return false, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+
+	return pending, nil
+}
+
+// Activate deletes the activation with tokenHash unless it expired before
+// now, and returns its user.
+func (r *ActivationRepository) Activate(ctx context.Context, tokenHash []byte, now time.Time) (uuid.UUID, error) {
+	query := `DELETE FROM activations WHERE token_hash = $1 AND expires_at > $2 RETURNING user_id`
+
+	var id uuid.UUID
+	err := conn(ctx, r.db).QueryRow(ctx, query, tokenHash, now).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, user.ErrActivationInvalid
+	}
+	if err != nil {
+		r.logger.Error("failed to activate user", zap.Error(err))
+		return uuid.Nil, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+
+	return id, nil
+}
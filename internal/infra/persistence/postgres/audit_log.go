@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditEntry describes one change made to a user outside the API.
+type AuditEntry struct {
+	// Actor is who made the change, e.g. the operator running an admin job.
+	Actor string
+	// Action names the change, e.g. "user.rewrite_domain".
+	Action    string
+	SubjectID uuid.UUID
+	Details   map[string]any
+}
+
+// AuditLog records the audit entries of one database.
+type AuditLog struct {
+	db DB
+}
+
+// NewAuditLog creates the audit log of db.
+func NewAuditLog(db DB) *AuditLog {
+	return &AuditLog{db: db}
+}
+
+// Record stores entry, joining the unit of work in ctx so it commits with
+// the change it describes.
+func (l *AuditLog) Record(ctx context.Context, entry AuditEntry) error {
+	details, err := json.Marshal(entry.Details)
+	if err != nil {
+		return fmt.Errorf("marshal audit entry: %w", err)
+	}
+
+	query := `
+		INSERT INTO audit_log (id, actor, action, subject_id, details, created_at)
+		VALUES ($1, $2, $3, $4, $5::jsonb, $6)
+	`
+	if _, err := conn(ctx, l.db).Exec(ctx, query, uuid.New(), entry.Actor, entry.Action, entry.SubjectID, string(details), time.Now().UTC()); err != nil {
+		return fmt.Errorf("record audit entry: %w", err)
+	}
+	return nil
+}
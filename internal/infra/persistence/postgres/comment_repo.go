@@ -0,0 +1,204 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"usermanagement/internal/domain/comment"
+	"usermanagement/internal/infra/logger"
+)
+
+// CommentRepository implements comment.CommentRepository using PostgreSQL.
+type CommentRepository struct {
+	db     DB
+	logger *logger.Logger
+}
+
+// commentColumns are selected by every comment query, in scanComment order.
+var commentColumns = []string{"id", "post_id", "author_id", "parent_id", "body", "status", "created_at", "updated_at"}
+
+// NewCommentRepository creates a new PostgreSQL comment repository.
+func NewCommentRepository(db DB, logger *logger.Logger) *CommentRepository {
+	return &CommentRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Save persists a new comment.
+func (r *CommentRepository) Save(ctx context.Context, c *comment.Comment) error {
+	query := `
+		INSERT INTO comments (id, post_id, author_id, parent_id, body, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := conn(ctx, r.db).Exec(ctx, query,
+		c.ID(),
+		c.PostID(),
+		c.AuthorID(),
+		c.ParentID(),
+		c.Body(),
+		string(c.Status()),
+		c.CreatedAt(),
+		c.UpdatedAt(),
+	)
+
+	if err != nil {
+		r.logger.Error("failed to save comment", zap.Error(err))
+		return fmt.Errorf("%w: %w", comment.ErrRepositoryInternal, err)
+	}
+
+	return nil
+}
+
+// FindByID retrieves a comment by ID.
+func (r *CommentRepository) FindByID(ctx context.Context, id uuid.UUID) (*comment.Comment, error) {
+	db, inTx := joinTx(ctx, r.db)
+	query, args := selectFrom("comments", commentColumns...).
+		Where("id = ?", id).
+		ForUpdate(inTx).
+		Build()
+
+	c, err := scanComment(db.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, comment.ErrCommentNotFound
+		}
+		r.logger.Error("failed to find comment by id", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", comment.ErrRepositoryInternal, err)
+	}
+
+	return c, nil
+}
+
+// FindByPost retrieves paginated comments on a post in the given status,
+// oldest first.
+func (r *CommentRepository) FindByPost(ctx context.Context, postID uuid.UUID, status comment.Status, limit, offset int) ([]*comment.Comment, error) {
+	query, args := selectFrom("comments", commentColumns...).
+		Where("post_id = ?", postID).
+		Where("status = ?", string(status)).
+		OrderBy("created_at ASC", "id ASC").
+		Page(limit, offset).
+		Build()
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("failed to list comments", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", comment.ErrRepositoryInternal, err)
+	}
+	defer rows.Close()
+
+	var comments []*comment.Comment
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			r.logger.Error("failed to scan comment row", zap.Error(err))
+			return nil, fmt.Errorf("%w: %w", comment.ErrRepositoryInternal, err)
+		}
+
+		comments = append(comments, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error("error iterating comment rows", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", comment.ErrRepositoryInternal, err)
+	}
+
+	return comments, nil
+}
+
+// CountByPost returns the number of comments on a post in the given status.
+func (r *CommentRepository) CountByPost(ctx context.Context, postID uuid.UUID, status comment.Status) (int64, error) {
+	query, args := selectFrom("comments", "count(*)").
+		Where("post_id = ?", postID).
+		Where("status = ?", string(status)).
+		Build()
+
+	var total int64
+	if err := conn(ctx, r.db).QueryRow(ctx, query, args...).Scan(&total); err != nil {
+		r.logger.Error("failed to count comments", zap.Error(err))
+		return 0, fmt.Errorf("%w: %w", comment.ErrRepositoryInternal, err)
+	}
+
+	return total, nil
+}
+
+// CountByStatus returns the number of comments in the given status across
+// all posts.
+func (r *CommentRepository) CountByStatus(ctx context.Context, status comment.Status) (int64, error) {
+	query, args := selectFrom("comments", "count(*)").
+		Where("status = ?", string(status)).
+		Build()
+
+	var total int64
+	if err := conn(ctx, r.db).QueryRow(ctx, query, args...).Scan(&total); err != nil {
+		r.logger.Error("failed to count comments", zap.Error(err))
+		return 0, fmt.Errorf("%w: %w", comment.ErrRepositoryInternal, err)
+	}
+
+	return total, nil
+}
+
+// Update modifies an existing comment.
+func (r *CommentRepository) Update(ctx context.Context, c *comment.Comment) error {
+	query := `
+		UPDATE comments
+		SET body = $1, status = $2, updated_at = $3
+		WHERE id = $4
+	`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query,
+		c.Body(),
+		string(c.Status()),
+		c.UpdatedAt(),
+		c.ID(),
+	)
+
+	if err != nil {
+		r.logger.Error("failed to update comment", zap.Error(err))
+		return fmt.Errorf("%w: %w", comment.ErrRepositoryInternal, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return comment.ErrCommentNotFound
+	}
+
+	return nil
+}
+
+// Delete removes a comment by ID; the foreign key cascades to its replies.
+func (r *CommentRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM comments WHERE id = $1`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, id)
+	if err != nil {
+		r.logger.Error("failed to delete comment", zap.Error(err))
+		return fmt.Errorf("%w: %w", comment.ErrRepositoryInternal, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return comment.ErrCommentNotFound
+	}
+
+	return nil
+}
+
+// scanComment hydrates a comment from a row selected with commentColumns.
+func scanComment(row pgx.Row) (*comment.Comment, error) {
+	var id, postID, authorID uuid.UUID
+	var parentID *uuid.UUID
+	var body, status string
+	var createdAt, updatedAt time.Time
+
+	if err := row.Scan(&id, &postID, &authorID, &parentID, &body, &status, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+
+	return comment.Reconstruct(id, postID, authorID, parentID, body, comment.Status(status), createdAt, updatedAt), nil
+}
@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"usermanagement/internal/infra/deadline"
+)
+
+// DB is the subset of pgx used by the repositories. It is satisfied by
+// *pgxpool.Pool, pgx.Tx and the request-annotating wrapper below.
+type DB interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// requestCommentDB prefixes every statement with a /* request_id=... */
+// comment so queries seen in pg_stat_activity or pgBadger reports can be
+// traced back to the API request that issued them.
+type requestCommentDB struct {
+	db DB
+}
+
+// WithRequestComments wraps db so statements carry the current request ID.
+//
+// Every request produces distinct SQL text, so the pool should not use the
+// prepared statement cache; Connect takes care of that when
+// DatabaseConfig.QueryComments is set.
+func WithRequestComments(db DB) DB {
+	return &requestCommentDB{db: db}
+}
+
+func (d *requestCommentDB) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	return d.db.Exec(ctx, annotate(ctx, sql), args...)
+}
+
+func (d *requestCommentDB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return d.db.Query(ctx, annotate(ctx, sql), args...)
+}
+
+func (d *requestCommentDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return d.db.QueryRow(ctx, annotate(ctx, sql), args...)
+}
+
+// maxCommentLen bounds the request ID copied into SQL, since clients can
+// supply their own X-Request-Id.
+const maxCommentLen = 64
+
+func annotate(ctx context.Context, sql string) string {
+	id := sanitizeComment(middleware.GetReqID(ctx))
+	if id == "" {
+		return sql
+	}
+	return "/* request_id=" + id + " */ " + sql
+}
+
+// sanitizeComment keeps only characters that cannot terminate or nest a SQL
+// comment.
+func sanitizeComment(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if b.Len() >= maxCommentLen {
+			break
+		}
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '-', r == '_', r == '.', r == ':':
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// budgetDB bounds every statement with a deadline budget, so one slow query
+// cannot use up the rest of the request. Statements of a unit of work run
+// on its transaction and are only bounded by the request deadline.
+type budgetDB struct {
+	db     DB
+	budget deadline.Budget
+}
+
+// WithBudget wraps db so each statement gets its share of the remaining
+// request deadline.
+func WithBudget(db DB, budget deadline.Budget) DB {
+	return &budgetDB{db: db, budget: budget}
+}
+
+func (d *budgetDB) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	ctx, cancel := d.budget.Bound(ctx)
+	defer cancel()
+	return d.db.Exec(ctx, sql, args...)
+}
+
+func (d *budgetDB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	ctx, cancel := d.budget.Bound(ctx)
+	rows, err := d.db.Query(ctx, sql, args...)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// The budget covers reading the rows too.
+	return &budgetRows{Rows: rows, cancel: cancel}, nil
+}
+
+func (d *budgetDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	ctx, cancel := d.budget.Bound(ctx)
+	return &budgetRow{row: d.db.QueryRow(ctx, sql, args...), cancel: cancel}
+}
+
+type budgetRows struct {
+	pgx.Rows
+	cancel context.CancelFunc
+}
+
+func (r *budgetRows) Close() {
+	r.Rows.Close()
+	r.cancel()
+}
+
+type budgetRow struct {
+	row    pgx.Row
+	cancel context.CancelFunc
+}
+
+func (r *budgetRow) Scan(dest ...any) error {
+	defer r.cancel()
+	return r.row.Scan(dest...)
+}
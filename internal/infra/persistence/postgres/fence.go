@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrFenced is returned when a write carries a fencing token older than one
+// a newer holder of the same lock already wrote with.
+var ErrFenced = errors.New("fencing token is stale")
+
+// Fences guards the writes made under distributed locks on one database.
+type Fences struct {
+	db DB
+}
+
+// NewFences creates the fences of db.
+func NewFences(db DB) *Fences {
+	return &Fences{db: db}
+}
+
+// Check admits a write by the holder of the named lock with token, or
+// returns ErrFenced when a newer holder has already written here. It joins
+// the unit of work in ctx and must run in the transaction of the write it
+// guards: the row it locks holds other holders off until that commits.
+func (f *Fences) Check(ctx context.Context, name string, token int64) error {
+	query := `
+		INSERT INTO fences (name, token) VALUES ($1, $2)
+		ON CONFLICT (name) DO UPDATE SET token = EXCLUDED.token
+		WHERE fences.token <= EXCLUDED.token
+		RETURNING token
+	`
+
+	err := conn(ctx, f.db).QueryRow(ctx, query, name, token).Scan(&token)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrFenced
+	}
+	if err != nil {
+		return fmt.Errorf("check fence %q: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"usermanagement/internal/infra/persistence/postgres"
+	"usermanagement/internal/infra/persistence/postgres/pgtest"
+)
+
+func TestFencesRefuseStaleTokens(t *testing.T) {
+	fences := postgres.NewFences(pgtest.Pool(t))
+	ctx := context.Background()
+	name := "test-" + uuid.NewString()
+
+	for _, step := range []struct {
+		token int64
+		want  error
+	}{
+		{token: 2},
+		{token: 2},
+		{token: 1, want: postgres.ErrFenced},
+		{token: 3},
+		{token: 2, want: postgres.ErrFenced},
+	} {
+		if err := fences.Check(ctx, name, step.token); !errors.Is(err, step.want) {
+			t.Fatalf("Check(%d) = %v, want %v", step.token, err, step.want)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxEvent is an event written to the outbox table alongside the change
+// it describes.
+type OutboxEvent struct {
+	Seq         int64
+	ID          uuid.UUID
+	Topic       string
+	AggregateID uuid.UUID
+	Payload     json.RawMessage
+	CreatedAt   time.Time
+	Attempts    int
+}
+
+// OutboxStore reads and settles the events of one database's outbox.
+type OutboxStore struct {
+	db DB
+}
+
+// NewOutboxStore creates the outbox store of db.
+func NewOutboxStore(db DB) *OutboxStore {
+	return &OutboxStore{db: db}
+}
+
+// Append queues an event no repository writes for topic about aggregateID,
+// joining the unit of work in ctx so it commits with the change it follows.
+func (s *OutboxStore) Append(ctx context.Context, topic string, aggregateID uuid.UUID, payload any) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal outbox event: %w", err)
+	}
+
+	query := `
+		INSERT INTO outbox (event_id, topic, aggregate_id, payload, created_at, next_attempt_at)
+		VALUES ($1, $2, $3, $4::jsonb, $5, $5)
+	`
+	if _, err := conn(ctx, s.db).Exec(ctx, query, uuid.New(), topic, aggregateID, string(raw), time.Now().UTC()); err != nil {
+		return fmt.Errorf("append outbox event: %w", err)
+	}
+	return nil
+}
+
+// Claim returns up to limit due events in insertion order and defers their
+// next attempt by lease, so other relays skip them while they are being
+// published. Events not settled within lease are claimed again.
+func (s *OutboxStore) Claim(ctx context.Context, limit int, lease time.Duration) ([]OutboxEvent, error) {
+	query := `
+		UPDATE outbox
+		SET next_attempt_at = now() + $2::bigint * interval '1 millisecond'
+		WHERE seq IN (
+			SELECT seq FROM outbox
+			WHERE published_at IS NULL AND next_attempt_at <= now()
+			ORDER BY seq
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING seq, event_id, topic, aggregate_id, payload::text, created_at, attempts
+	`
+
+	rows, err := s.db.Query(ctx, query, limit, lease.Milliseconds())
+	if err != nil {
+		return nil, fmt.Errorf("claim outbox events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []OutboxEvent
+	for rows.Next() {
+		var (
+			e       OutboxEvent
+			payload string
+		)
+		if err := rows.Scan(&e.Seq, &e.ID, &e.Topic, &e.AggregateID, &payload, &e.CreatedAt, &e.Attempts); err != nil {
+			return nil, fmt.Errorf("scan outbox event: %w", err)
+		}
+		e.Payload = json.RawMessage(payload)
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("claim outbox events: %w", err)
+	}
+
+	// UPDATE ... RETURNING does not keep the subquery's order.
+	sort.Slice(events, func(i, j int) bool { return events[i].Seq < events[j].Seq })
+	return events, nil
+}
+
+// MarkPublished records that the event was delivered.
+func (s *OutboxStore) MarkPublished(ctx context.Context, seq int64) error {
+	query := `UPDATE outbox SET published_at = now(), last_error = NULL WHERE seq = $1`
+
+	if _, err := s.db.Exec(ctx, query, seq); err != nil {
+		return fmt.Errorf("mark outbox event published: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed records a failed delivery and schedules the next attempt after
+// backoff.
+func (s *OutboxStore) MarkFailed(ctx context.Context, seq int64, backoff time.Duration, cause error) error {
+	query := `
+		UPDATE outbox
+		SET attempts = attempts + 1, last_error = $2, next_attempt_at = now() + $3::bigint * interval '1 millisecond'
+		WHERE seq = $1
+	`
+
+	if _, err := s.db.Exec(ctx, query, seq, cause.Error(), backoff.Milliseconds()); err != nil {
+		return fmt.Errorf("mark outbox event failed: %w", err)
+	}
+	return nil
+}
+
+// DeleteBefore removes every event created before cutoff, published or
+// not, and reports how many were removed. It is for outboxes nothing relays
+// from.
+func (s *OutboxStore) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	query := `DELETE FROM outbox WHERE created_at < $1`
+
+	result, err := s.db.Exec(ctx, query, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("delete outbox events: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// DeletePublished removes events published before cutoff and reports how
+// many were removed.
+func (s *OutboxStore) DeletePublished(ctx context.Context, cutoff time.Time) (int64, error) {
+	query := `DELETE FROM outbox WHERE published_at < $1`
+
+	result, err := s.db.Exec(ctx, query, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("delete published outbox events: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"usermanagement/internal/infra/config"
+)
+
+// Connect opens a connection pool for the configured database and pings it.
+func Connect(ctx context.Context, cfg config.DatabaseConfig) (*pgxpool.Pool, error) {
+	poolCfg, err := pgxpool.ParseConfig(cfg.URL())
+	if err != nil {
+		return nil, fmt.Errorf("invalid database configuration: %w", err)
+	}
+	if cfg.SimpleProtocol {
+		// PgBouncer transaction pooling cannot keep prepared statements
+		// across transactions, so skip the statement cache entirely.
+		poolCfg.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeSimpleProtocol
+	} else if cfg.QueryComments {
+		// Request comments make every statement unique; caching them would
+		// only churn the statement cache and cost an extra round trip.
+		poolCfg.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeExec
+	}
+	if cfg.PasswordSource != nil {
+		poolCfg.BeforeConnect = func(_ context.Context, cc *pgx.ConnConfig) error {
+			cc.Password = cfg.PasswordSource()
+			return nil
+		}
+	}
+	if _, ok := poolCfg.ConnConfig.RuntimeParams["application_name"]; !ok {
+		poolCfg.ConnConfig.RuntimeParams["application_name"] = "usermanagement"
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return pool, nil
+}
+
+// Dial opens a single connection to the configured database outside any
+// pool, for session state such as advisory locks that must not be shared.
+// It honours neither SimpleProtocol nor QueryComments: callers needing a
+// session cannot go through PgBouncer anyway.
+func Dial(ctx context.Context, cfg config.DatabaseConfig) (*pgx.Conn, error) {
+	connCfg, err := pgx.ParseConfig(cfg.URL())
+	if err != nil {
+		return nil, fmt.Errorf("invalid database configuration: %w", err)
+	}
+	if cfg.PasswordSource != nil {
+		connCfg.Password = cfg.PasswordSource()
+	}
+	if _, ok := connCfg.RuntimeParams["application_name"]; !ok {
+		connCfg.RuntimeParams["application_name"] = "usermanagement"
+	}
+
+	conn, err := pgx.ConnectConfig(ctx, connCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	return conn, nil
+}
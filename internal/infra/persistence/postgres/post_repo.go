@@ -0,0 +1,198 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+
+	"usermanagement/internal/domain/post"
+	"usermanagement/internal/infra/logger"
+)
+
+// PostRepository implements post.PostRepository using PostgreSQL.
+type PostRepository struct {
+	db     DB
+	logger *logger.Logger
+}
+
+// postColumns are selected by every post query, in scanPost order.
+var postColumns = []string{"id", "author_id", "title", "slug", "body", "status", "created_at", "updated_at", "published_at"}
+
+// NewPostRepository creates a new PostgreSQL post repository.
+func NewPostRepository(db DB, logger *logger.Logger) *PostRepository {
+	return &PostRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Save persists a new post.
+func (r *PostRepository) Save(ctx context.Context, p *post.Post) error {
+	query := `
+		INSERT INTO posts (id, author_id, title, slug, body, status, created_at, updated_at, published_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err := conn(ctx, r.db).Exec(ctx, query,
+		p.ID(),
+		p.AuthorID(),
+		p.Title(),
+		p.Slug(),
+		p.Body(),
+		string(p.Status()),
+		p.CreatedAt(),
+		p.UpdatedAt(),
+		p.PublishedAt(),
+	)
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique_violation
+			return post.ErrSlugExists
+		}
+		r.logger.Error("failed to save post", zap.Error(err))
+		return fmt.Errorf("%w: %w", post.ErrRepositoryInternal, err)
+	}
+
+	return nil
+}
+
+// FindByID retrieves a post by ID.
+func (r *PostRepository) FindByID(ctx context.Context, id uuid.UUID) (*post.Post, error) {
+	db, inTx := joinTx(ctx, r.db)
+	query, args := selectFrom("posts", postColumns...).
+		Where("id = ?", id).
+		ForUpdate(inTx).
+		Build()
+
+	p, err := scanPost(db.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, post.ErrPostNotFound
+		}
+		r.logger.Error("failed to find post by id", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", post.ErrRepositoryInternal, err)
+	}
+
+	return p, nil
+}
+
+// FindByStatus retrieves paginated posts in the given status, newest first.
+func (r *PostRepository) FindByStatus(ctx context.Context, status post.Status, limit, offset int) ([]*post.Post, error) {
+	query, args := selectFrom("posts", postColumns...).
+		Where("status = ?", string(status)).
+		OrderBy("created_at DESC").
+		Page(limit, offset).
+		Build()
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("failed to list posts", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", post.ErrRepositoryInternal, err)
+	}
+	defer rows.Close()
+
+	var posts []*post.Post
+	for rows.Next() {
+		p, err := scanPost(rows)
+		if err != nil {
+			r.logger.Error("failed to scan post row", zap.Error(err))
+			return nil, fmt.Errorf("%w: %w", post.ErrRepositoryInternal, err)
+		}
+
+		posts = append(posts, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error("error iterating post rows", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", post.ErrRepositoryInternal, err)
+	}
+
+	return posts, nil
+}
+
+// CountByStatus returns the number of posts in the given status.
+func (r *PostRepository) CountByStatus(ctx context.Context, status post.Status) (int64, error) {
+	query, args := selectFrom("posts", "count(*)").
+		Where("status = ?", string(status)).
+		Build()
+
+	var total int64
+	if err := conn(ctx, r.db).QueryRow(ctx, query, args...).Scan(&total); err != nil {
+		r.logger.Error("failed to count posts", zap.Error(err))
+		return 0, fmt.Errorf("%w: %w", post.ErrRepositoryInternal, err)
+	}
+
+	return total, nil
+}
+
+// Update modifies an existing post.
+func (r *PostRepository) Update(ctx context.Context, p *post.Post) error {
+	query := `
+		UPDATE posts
+		SET title = $1, slug = $2, body = $3, status = $4, updated_at = $5, published_at = $6
+		WHERE id = $7
+	`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query,
+		p.Title(),
+		p.Slug(),
+		p.Body(),
+		string(p.Status()),
+		p.UpdatedAt(),
+		p.PublishedAt(),
+		p.ID(),
+	)
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return post.ErrSlugExists
+		}
+		r.logger.Error("failed to update post", zap.Error(err))
+		return fmt.Errorf("%w: %w", post.ErrRepositoryInternal, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return post.ErrPostNotFound
+	}
+
+	return nil
+}
+
+// Delete removes a post by ID.
+func (r *PostRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM posts WHERE id = $1`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, id)
+	if err != nil {
+		r.logger.Error("failed to delete post", zap.Error(err))
+		return fmt.Errorf("%w: %w", post.ErrRepositoryInternal, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return post.ErrPostNotFound
+	}
+
+	return nil
+}
+
+// scanPost hydrates a post from a row selected with postColumns.
+func scanPost(row pgx.Row) (*post.Post, error) {
+	var id, authorID uuid.UUID
+	var title, slug, body, status string
+	var createdAt, updatedAt time.Time
+	var publishedAt *time.Time
+
+	if err := row.Scan(&id, &authorID, &title, &slug, &body, &status, &createdAt, &updatedAt, &publishedAt); err != nil {
+		return nil, err
+	}
+
+	return post.Reconstruct(id, authorID, title, slug, body, post.Status(status), createdAt, updatedAt, publishedAt), nil
+}
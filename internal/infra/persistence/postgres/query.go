@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// selectBuilder composes SELECT statements from parts so filters, sorting
+// and scoping can be added independently. Conditions use "?" placeholders,
+// which are renumbered to $n as they are added; values always travel as
+// arguments and are never spliced into the SQL. Conditions therefore cannot
+// use the jsonb "?" operators; use jsonb_exists and friends instead.
+//
+// Table, column and ORDER BY expressions are trusted SQL fragments: pass
+// only constants, never user input.
+type selectBuilder struct {
+	columns []string
+	from    string
+	where   []string
+	orderBy []string
+	limit   string
+	offset  string
+	lock    bool
+	args    []any
+}
+
+// selectFrom starts a query selecting columns from table.
+func selectFrom(table string, columns ...string) *selectBuilder {
+	return &selectBuilder{from: table, columns: columns}
+}
+
+// Where adds a condition, ANDed with the others. Each "?" in cond binds the
+// next of args.
+func (b *selectBuilder) Where(cond string, args ...any) *selectBuilder {
+	if n := strings.Count(cond, "?"); n != len(args) {
+		panic(fmt.Sprintf("postgres: condition %q has %d placeholders but %d args", cond, n, len(args)))
+	}
+
+	var sb strings.Builder
+	for _, part := range strings.SplitAfter(cond, "?") {
+		if strings.HasSuffix(part, "?") {
+			sb.WriteString(strings.TrimSuffix(part, "?"))
+			sb.WriteString(b.bind(args[0]))
+			args = args[1:]
+			continue
+		}
+		sb.WriteString(part)
+	}
+	b.where = append(b.where, sb.String())
+	return b
+}
+
+// OrderBy appends sort expressions such as "created_at DESC".
+func (b *selectBuilder) OrderBy(exprs ...string) *selectBuilder {
+	b.orderBy = append(b.orderBy, exprs...)
+	return b
+}
+
+// Page limits the result to limit rows starting at offset.
+func (b *selectBuilder) Page(limit, offset int) *selectBuilder {
+	b.limit = b.bind(limit)
+	b.offset = b.bind(offset)
+	return b
+}
+
+// ForUpdate locks the selected rows until the end of the transaction when
+// lock is true.
+func (b *selectBuilder) ForUpdate(lock bool) *selectBuilder {
+	b.lock = lock
+	return b
+}
+
+// Build returns the SQL and its arguments.
+func (b *selectBuilder) Build() (string, []any) {
+	var sb strings.Builder
+	sb.WriteString("SELECT ")
+	sb.WriteString(strings.Join(b.columns, ", "))
+	sb.WriteString(" FROM ")
+	sb.WriteString(b.from)
+	if len(b.where) > 0 {
+		sb.WriteString(" WHERE ")
+		sb.WriteString(strings.Join(b.where, " AND "))
+	}
+	if len(b.orderBy) > 0 {
+		sb.WriteString(" ORDER BY ")
+		sb.WriteString(strings.Join(b.orderBy, ", "))
+	}
+	if b.limit != "" {
+		sb.WriteString(" LIMIT " + b.limit + " OFFSET " + b.offset)
+	}
+	if b.lock {
+		sb.WriteString(" FOR UPDATE")
+	}
+	return sb.String(), b.args
+}
+
+// bind records arg and returns its placeholder.
+func (b *selectBuilder) bind(arg any) string {
+	b.args = append(b.args, arg)
+	return "$" + strconv.Itoa(len(b.args))
+}
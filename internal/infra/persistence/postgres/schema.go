@@ -0,0 +1,201 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// expectedSchema lists the columns (and their information_schema data types)
+// the repositories rely on. Keep it in sync with the migrations.
+var expectedSchema = map[string]map[string]string{
+	"users": {
+		"id":            "uuid",
+		"name":          "text",
+		"email":         "text",
+		"password_hash": "text",
+		"role":          "text",
+		"created_at":    "timestamp with time zone",
+		"updated_at":    "timestamp with time zone",
+		"search":        "tsvector",
+		"version":       "bigint",
+	},
+	"posts": {
+		"id":           "uuid",
+		"author_id":    "uuid",
+		"title":        "text",
+		"slug":         "text",
+		"body":         "text",
+		"status":       "text",
+		"created_at":   "timestamp with time zone",
+		"updated_at":   "timestamp with time zone",
+		"published_at": "timestamp with time zone",
+	},
+	"comments": {
+		"id":         "uuid",
+		"post_id":    "uuid",
+		"author_id":  "uuid",
+		"parent_id":  "uuid",
+		"body":       "text",
+		"status":     "text",
+		"created_at": "timestamp with time zone",
+		"updated_at": "timestamp with time zone",
+	},
+	"locks": {
+		"name":       "text",
+		"owner":      "text",
+		"token":      "bigint",
+		"expires_at": "timestamp with time zone",
+	},
+	"fences": {
+		"name":  "text",
+		"token": "bigint",
+	},
+	"outbox": {
+		"seq":             "bigint",
+		"event_id":        "uuid",
+		"topic":           "text",
+		"aggregate_id":    "uuid",
+		"payload":         "jsonb",
+		"created_at":      "timestamp with time zone",
+		"attempts":        "integer",
+		"next_attempt_at": "timestamp with time zone",
+		"last_error":      "text",
+		"published_at":    "timestamp with time zone",
+	},
+	"audit_log": {
+		"id":         "uuid",
+		"actor":      "text",
+		"action":     "text",
+		"subject_id": "uuid",
+		"details":    "jsonb",
+		"created_at": "timestamp with time zone",
+	},
+	"api_keys": {
+		"id":          "uuid",
+		"owner_id":    "uuid",
+		"name":        "text",
+		"prefix":      "text",
+		"secret_hash": "bytea",
+		"scopes":      "ARRAY",
+		"created_at":  "timestamp with time zone",
+		"revoked_at":  "timestamp with time zone",
+	},
+	"activations": {
+		"user_id":    "uuid",
+		"token_hash": "bytea",
+		"expires_at": "timestamp with time zone",
+	},
+	"idempotency_keys": {
+		"key":          "text",
+		"fingerprint":  "text",
+		"status":       "integer",
+		"header":       "jsonb",
+		"body":         "bytea",
+		"locked_until": "timestamp with time zone",
+		"expires_at":   "timestamp with time zone",
+	},
+	"webhook_subscriptions": {
+		"id":         "uuid",
+		"owner_id":   "uuid",
+		"url":        "text",
+		"events":     "ARRAY",
+		"secret":     "text",
+		"created_at": "timestamp with time zone",
+	},
+	"webhook_deliveries": {
+		"id":               "uuid",
+		"subscription_id":  "uuid",
+		"event_id":         "uuid",
+		"topic":            "text",
+		"payload":          "jsonb",
+		"status":           "text",
+		"attempts":         "integer",
+		"next_attempt_at":  "timestamp with time zone",
+		"last_status_code": "integer",
+		"last_error":       "text",
+		"created_at":       "timestamp with time zone",
+		"delivered_at":     "timestamp with time zone",
+	},
+}
+
+// SchemaReport describes differences between the live and expected schema.
+type SchemaReport struct {
+	Problems []string
+}
+
+// OK reports whether the live schema matches expectations.
+func (r *SchemaReport) OK() bool {
+	return len(r.Problems) == 0
+}
+
+// String renders the report one problem per line.
+func (r *SchemaReport) String() string {
+	if r.OK() {
+		return "schema OK"
+	}
+	return "schema drift detected:\n  - " + strings.Join(r.Problems, "\n  - ")
+}
+
+// VerifySchema compares the live schema against expectedSchema.
+func VerifySchema(ctx context.Context, pool *pgxpool.Pool) (*SchemaReport, error) {
+	query := `
+		SELECT table_name, column_name, data_type
+		FROM information_schema.columns
+		WHERE table_schema = current_schema()
+	`
+
+	rows, err := pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+	defer rows.Close()
+
+	live := make(map[string]map[string]string)
+	for rows.Next() {
+		var table, column, dataType string
+		if err := rows.Scan(&table, &column, &dataType); err != nil {
+			return nil, fmt.Errorf("failed to scan schema row: %w", err)
+		}
+		if live[table] == nil {
+			live[table] = make(map[string]string)
+		}
+		live[table][column] = dataType
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+
+	report := &SchemaReport{}
+	for _, table := range sortedKeys(expectedSchema) {
+		columns, ok := live[table]
+		if !ok {
+			report.Problems = append(report.Problems, fmt.Sprintf("table %q is missing", table))
+			continue
+		}
+		for _, column := range sortedKeys(expectedSchema[table]) {
+			want := expectedSchema[table][column]
+			got, ok := columns[column]
+			switch {
+			case !ok:
+				report.Problems = append(report.Problems, fmt.Sprintf("column %s.%s is missing", table, column))
+			case got != want:
+				report.Problems = append(report.Problems, fmt.Sprintf("column %s.%s has type %q, expected %q", table, column, got, want))
+			}
+		}
+	}
+
+	return report, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
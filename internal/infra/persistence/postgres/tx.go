@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"usermanagement/internal/domain/transaction"
+	"usermanagement/internal/infra/logger"
+)
+
+// TxManager implements transaction.UnitOfWork on one database.
+//
+// Only repositories built with the same DB value join its transactions:
+// those on other databases, such as user shards, keep running their
+// statements on their own connections.
+type TxManager struct {
+	pool   *pgxpool.Pool
+	db     DB
+	logger *logger.Logger
+}
+
+// NewTxManager creates a unit of work beginning transactions on pool. db is
+// the DB the participating repositories were built with, pool itself or a
+// wrapper of it.
+func NewTxManager(pool *pgxpool.Pool, db DB, logger *logger.Logger) *TxManager {
+	return &TxManager{pool: pool, db: db, logger: logger}
+}
+
+// txKey is the context key of the running transaction.
+type txKey struct{}
+
+// activeTx is a transaction carried in a context.
+type activeTx struct {
+	// owner is the DB of the TxManager that began it.
+	owner DB
+	// db runs statements in the transaction.
+	db DB
+}
+
+// Do implements transaction.UnitOfWork.
+func (m *TxManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if t, ok := ctx.Value(txKey{}).(*activeTx); ok && t.owner == m.db {
+		return fn(ctx)
+	}
+
+	tx, err := m.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	// A no-op once committed; otherwise undoes fn's work, even when it
+	// panics.
+	defer func() {
+		if err := tx.Rollback(context.WithoutCancel(ctx)); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			m.logger.Warn("failed to roll back transaction", zap.Error(err))
+		}
+	}()
+
+	var db DB = tx
+	if _, ok := m.db.(*requestCommentDB); ok {
+		db = WithRequestComments(tx)
+	}
+	ctx = transaction.WithActive(ctx)
+	if err := fn(context.WithValue(ctx, txKey{}, &activeTx{owner: m.db, db: db})); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// conn returns where a repository built on db runs statements for ctx: the
+// transaction of a unit of work on db, or db itself.
+func conn(ctx context.Context, db DB) DB {
+	db, _ = joinTx(ctx, db)
+	return db
+}
+
+// joinTx is conn, also reporting whether ctx carries a transaction on db.
+func joinTx(ctx context.Context, db DB) (DB, bool) {
+	if t, ok := ctx.Value(txKey{}).(*activeTx); ok && t.owner == db {
+		return t.db, true
+	}
+	return db, false
+}
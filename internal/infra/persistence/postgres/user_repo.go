@@ -2,14 +2,15 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
-	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 
 	"usermanagement/internal/domain/user"
@@ -18,179 +19,433 @@ import (
 
 // UserRepository implements domain.UserRepository using PostgreSQL.
 type UserRepository struct {
-	pool   *pgxpool.Pool
+	db     DB
 	logger *logger.Logger
 }
 
+// userColumns are selected by every user query, in scanUser order.
+var userColumns = []string{"id", "name", "email", "password_hash", "role", "created_at", "updated_at", "version"}
+
+// usersEmailConstraint is the unique constraint on users.email, Postgres's
+// default name for the UNIQUE column in the initial migration. Email
+// uniqueness relies on it rather than on checking before inserting, which
+// races.
+const usersEmailConstraint = "users_email_key"
+
 // NewUserRepository creates a new PostgreSQL user repository.
-func NewUserRepository(pool *pgxpool.Pool, logger *logger.Logger) *UserRepository {
+func NewUserRepository(db DB, logger *logger.Logger) *UserRepository {
 	return &UserRepository{
-		pool:   pool,
+		db:     db,
 		logger: logger,
 	}
 }
 
 // Save persists a new user.
 func (r *UserRepository) Save(ctx context.Context, u *user.User) error {
-	query := `
-		INSERT INTO users (id, name, email, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5)
-	`
-
-	_, err := r.pool.Exec(ctx, query,
+	query, args := withEvent(`
+		INSERT INTO users (id, name, email, password_hash, role, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`, []any{
 		u.ID(),
 		u.Name(),
 		u.Email(),
+		u.PasswordHash(),
+		string(u.Role()),
 		u.CreatedAt(),
 		u.UpdatedAt(),
-	)
+	}, user.TopicCreated, profileEvent(u))
+
+	_, err := conn(ctx, r.db).Exec(ctx, query, args...)
 
 	if err != nil {
 		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique_violation
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == usersEmailConstraint { // unique_violation
 			return user.ErrEmailExists
 		}
 		r.logger.Error("failed to save user", zap.Error(err))
-		return fmt.Errorf("%w: %v", user.ErrRepositoryInternal, err)
+		return fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
 	}
 
 	return nil
 }
 
-// FindByID retrieves a user by ID.
-func (r *UserRepository) FindByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+// SaveBatch persists users and their events in one statement. Users whose
+// email is taken are skipped through ON CONFLICT instead of failing the
+// batch; the IDs of the others come back from their outbox rows.
+func (r *UserRepository) SaveBatch(ctx context.Context, users []*user.User) ([]uuid.UUID, error) {
+	n := len(users)
+	ids, eventIDs := make([]uuid.UUID, n), make([]uuid.UUID, n)
+	names, emails, hashes, roles, payloads := make([]string, n), make([]string, n), make([]string, n), make([]string, n), make([]string, n)
+	createdAt, updatedAt := make([]time.Time, n), make([]time.Time, n)
+	for i, u := range users {
+		ids[i], eventIDs[i] = u.ID(), uuid.New()
+		names[i], emails[i], hashes[i], roles[i] = u.Name(), u.Email(), u.PasswordHash(), string(u.Role())
+		createdAt[i], updatedAt[i] = u.CreatedAt(), u.UpdatedAt()
+		payloads[i] = marshalEvent(profileEvent(u))
+	}
+
 	query := `
-		SELECT id, name, email, created_at, updated_at
-		FROM users
-		WHERE id = $1
+		WITH changed AS (
+			INSERT INTO users (id, name, email, password_hash, role, created_at, updated_at)
+			SELECT * FROM unnest($1::uuid[], $2::text[], $3::text[], $4::text[], $5::text[], $6::timestamptz[], $7::timestamptz[])
+			ON CONFLICT (email) DO NOTHING
+			RETURNING id
+		)
+		INSERT INTO outbox (event_id, topic, aggregate_id, payload, created_at, next_attempt_at)
+		SELECT e.event_id, $8::text, changed.id, e.payload::jsonb, $11::timestamptz, $11::timestamptz
+		FROM changed
+		JOIN unnest($1::uuid[], $9::uuid[], $10::text[]) AS e (id, event_id, payload) ON e.id = changed.id
+		RETURNING aggregate_id
 	`
+	rows, err := conn(ctx, r.db).Query(ctx, query,
+		ids, names, emails, hashes, roles, createdAt, updatedAt,
+		user.TopicCreated, eventIDs, payloads, time.Now().UTC())
+	if err != nil {
+		r.logger.Error("failed to save users", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+
+	defer rows.Close()
 
-	row := r.pool.QueryRow(ctx, query, id)
+	saved := make([]uuid.UUID, 0, n)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			r.logger.Error("failed to scan saved user id", zap.Error(err))
+			return nil, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+		}
+		saved = append(saved, id)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.Error("failed to save users", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
 
-	var uid uuid.UUID
-	var name, email string
-	var createdAt, updatedAt time.Time
+	return saved, nil
+}
 
-	err := row.Scan(&uid, &name, &email, &createdAt, &updatedAt)
+// FindByID retrieves a user by ID.
+func (r *UserRepository) FindByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	// Inside a unit of work the row stays locked until it ends, so the
+	// caller's read-modify-write cannot lose a concurrent update.
+	db, inTx := joinTx(ctx, r.db)
+	query, args := selectFrom("users", userColumns...).
+		Where("id = ?", id).
+		ForUpdate(inTx).
+		Build()
+
+	u, err := scanUser(db.QueryRow(ctx, query, args...))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, user.ErrUserNotFound
 		}
 		r.logger.Error("failed to find user by id", zap.Error(err))
-		return nil, fmt.Errorf("%w: %v", user.ErrRepositoryInternal, err)
+		return nil, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
 	}
 
-	return user.Reconstruct(uid, name, email, createdAt, updatedAt), nil
+	return u, nil
 }
 
 // FindByEmail retrieves a user by email.
 func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*user.User, error) {
-	query := `
-		SELECT id, name, email, created_at, updated_at
-		FROM users
-		WHERE email = $1
-	`
+	query, args := selectFrom("users", userColumns...).
+		Where("email = ?", email).
+		Build()
 
-	row := r.pool.QueryRow(ctx, query, email)
-
-	var uid uuid.UUID
-	var name, dbEmail string
-	var createdAt, updatedAt time.Time
-
-	err := row.Scan(&uid, &name, &dbEmail, &createdAt, &updatedAt)
+	u, err := scanUser(conn(ctx, r.db).QueryRow(ctx, query, args...))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, user.ErrUserNotFound
 		}
 		r.logger.Error("failed to find user by email", zap.Error(err))
-		return nil, fmt.Errorf("%w: %v", user.ErrRepositoryInternal, err)
+		return nil, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
 	}
 
-	return user.Reconstruct(uid, name, dbEmail, createdAt, updatedAt), nil
+	return u, nil
 }
 
-// FindAll retrieves paginated users.
+// FindAll retrieves paginated users, newest first.
 func (r *UserRepository) FindAll(ctx context.Context, limit, offset int) ([]*user.User, error) {
-	query := `
-		SELECT id, name, email, created_at, updated_at
-		FROM users
-		ORDER BY created_at DESC
-		LIMIT $1 OFFSET $2
-	`
+	query, args := selectFrom("users", userColumns...).
+		OrderBy("created_at DESC", "id DESC").
+		Page(limit, offset).
+		Build()
+
+	return r.findMany(ctx, query, args)
+}
+
+// FindAfter retrieves up to limit users following after, newest first. The
+// row comparison lets Postgres seek straight to the position through the
+// (created_at, id) index instead of skipping over an offset.
+func (r *UserRepository) FindAfter(ctx context.Context, after *user.Keyset, limit int) ([]*user.User, error) {
+	q := selectFrom("users", userColumns...)
+	if after != nil {
+		q.Where("(created_at, id) < (?, ?)", after.CreatedAt, after.ID)
+	}
+	query, args := q.OrderBy("created_at DESC", "id DESC").
+		Page(limit, 0).
+		Build()
+
+	return r.findMany(ctx, query, args)
+}
+
+// searchFrom joins users with the parsed query, $1, for searchMatch and
+// searchRank. $2 is $1 as an ILIKE substring pattern.
+const (
+	searchFrom  = `users, websearch_to_tsquery('simple', $1) AS q`
+	searchMatch = `search @@ q OR name % $1 OR name ILIKE $2 OR email ILIKE $2`
+	searchRank  = `(ts_rank(search, q) + greatest(similarity(name, $1), similarity(email, $1)))::float8`
+)
+
+// Search finds users by the words of their name and email through the
+// search column, and by partial words and near misses through the trigram
+// indexes. Ranking adds the full-text rank to the better trigram similarity.
+func (r *UserRepository) Search(ctx context.Context, query string, page user.Page) (*user.SearchResult, error) {
+	pattern := "%" + likeEscaper.Replace(query) + "%"
+	db := conn(ctx, r.db)
+
+	rows, err := db.Query(ctx, fmt.Sprintf(`
+		SELECT %s, %s AS rank
+		FROM %s
+		WHERE %s
+		ORDER BY rank DESC, created_at DESC, id DESC
+		LIMIT $3 OFFSET $4
+	`, strings.Join(userColumns, ", "), searchRank, searchFrom, searchMatch), query, pattern, page.Limit, page.Offset)
+	if err != nil {
+		r.logger.Error("failed to search users", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+	defer rows.Close()
+
+	result := &user.SearchResult{}
+	for rows.Next() {
+		hit, err := scanSearchHit(rows)
+		if err != nil {
+			r.logger.Error("failed to scan user search row", zap.Error(err))
+			return nil, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+		}
+		result.Hits = append(result.Hits, hit)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.Error("error iterating user search rows", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+
+	countQuery := fmt.Sprintf(`SELECT count(*) FROM %s WHERE %s`, searchFrom, searchMatch)
+	if err := db.QueryRow(ctx, countQuery, query, pattern).Scan(&result.Total); err != nil {
+		r.logger.Error("failed to count user search results", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
 
-	rows, err := r.pool.Query(ctx, query, limit, offset)
+	return result, nil
+}
+
+// likeEscaper escapes LIKE wildcards, so user input matches literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// findMany runs a query selecting userColumns and hydrates every row.
+func (r *UserRepository) findMany(ctx context.Context, query string, args []any) ([]*user.User, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
 	if err != nil {
 		r.logger.Error("failed to list users", zap.Error(err))
-		return nil, fmt.Errorf("%w: %v", user.ErrRepositoryInternal, err)
+		return nil, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
 	}
 	defer rows.Close()
 
 	var users []*user.User
 	for rows.Next() {
-		var uid uuid.UUID
-		var name, email string
-		var createdAt, updatedAt time.Time
-
-		if err := rows.Scan(&uid, &name, &email, &createdAt, &updatedAt); err != nil {
+		u, err := scanUser(rows)
+		if err != nil {
 			r.logger.Error("failed to scan user row", zap.Error(err))
-			return nil, fmt.Errorf("%w: %v", user.ErrRepositoryInternal, err)
+			return nil, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
 		}
 
-		users = append(users, user.Reconstruct(uid, name, email, createdAt, updatedAt))
+		users = append(users, u)
 	}
 
 	if err := rows.Err(); err != nil {
 		r.logger.Error("error iterating user rows", zap.Error(err))
-		return nil, fmt.Errorf("%w: %v", user.ErrRepositoryInternal, err)
+		return nil, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
 	}
 
 	return users, nil
 }
 
+// Count returns the total number of users.
+func (r *UserRepository) Count(ctx context.Context) (int64, error) {
+	var total int64
+	if err := conn(ctx, r.db).QueryRow(ctx, `SELECT count(*) FROM users`).Scan(&total); err != nil {
+		r.logger.Error("failed to count users", zap.Error(err))
+		return 0, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+
+	return total, nil
+}
+
 // Update modifies an existing user.
 func (r *UserRepository) Update(ctx context.Context, u *user.User) error {
-	query := `
+	query, args := withEvent(`
 		UPDATE users
-		SET name = $1, email = $2, updated_at = $3
-		WHERE id = $4
-	`
-
-	result, err := r.pool.Exec(ctx, query,
+		SET name = $1, email = $2, password_hash = $3, role = $4, updated_at = $5, version = version + 1
+		WHERE id = $6 AND version = $7
+		RETURNING id
+	`, []any{
 		u.Name(),
 		u.Email(),
+		u.PasswordHash(),
+		string(u.Role()),
 		u.UpdatedAt(),
 		u.ID(),
-	)
+		u.Version(),
+	}, user.TopicUpdated, profileEvent(u))
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, args...)
 
 	if err != nil {
 		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == usersEmailConstraint {
 			return user.ErrEmailExists
 		}
 		r.logger.Error("failed to update user", zap.Error(err))
-		return fmt.Errorf("%w: %v", user.ErrRepositoryInternal, err)
+		return fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
 	}
 
 	if result.RowsAffected() == 0 {
-		return user.ErrUserNotFound
+		return r.updateMissed(ctx, u.ID())
 	}
 
+	u.AdvanceVersion()
 	return nil
 }
 
-// Delete removes a user by ID.
-func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM users WHERE id = $1`
+// updateMissed explains an update or delete that matched no row: the user
+// is gone, or another update moved its version on.
+func (r *UserRepository) updateMissed(ctx context.Context, id uuid.UUID) error {
+	var exists bool
+	err := conn(ctx, r.db).QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE id = $1)`, id).Scan(&exists)
+	if err != nil {
+		r.logger.Error("failed to check user existence", zap.Error(err))
+		return fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+
+	if !exists {
+		return user.ErrUserNotFound
+	}
+	return user.ErrVersionConflict
+}
+
+// Delete removes a user by ID if it is still at version, or at any version
+// when version is 0.
+func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID, version int64) error {
+	query, args := withEvent(`DELETE FROM users WHERE id = $1 AND ($2::bigint = 0 OR version = $2) RETURNING id`,
+		[]any{id, version}, user.TopicDeleted, userEvent{ID: id})
 
-	result, err := r.pool.Exec(ctx, query, id)
+	result, err := conn(ctx, r.db).Exec(ctx, query, args...)
 	if err != nil {
 		r.logger.Error("failed to delete user", zap.Error(err))
-		return fmt.Errorf("%w: %v", user.ErrRepositoryInternal, err)
+		return fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
 	}
 
 	if result.RowsAffected() == 0 {
-		return user.ErrUserNotFound
+		return r.updateMissed(ctx, id)
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// CountUsers returns the total number of users and how many were created at
+// or after since.
+func (r *UserRepository) CountUsers(ctx context.Context, since time.Time) (total, created int64, err error) {
+	query := `
+		SELECT count(*), count(*) FILTER (WHERE created_at >= $1)
+		FROM users
+	`
+
+	if err := conn(ctx, r.db).QueryRow(ctx, query, since).Scan(&total, &created); err != nil {
+		r.logger.Error("failed to count users", zap.Error(err))
+		return 0, 0, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+
+	return total, created, nil
+}
+
+// userEvent is the payload of user events. Deleted events only carry the
+// ID. Password hashes never leave the database.
+type userEvent struct {
+	ID        uuid.UUID  `json:"id"`
+	Name      string     `json:"name,omitempty"`
+	Email     string     `json:"email,omitempty"`
+	Role      string     `json:"role,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+func profileEvent(u *user.User) userEvent {
+	createdAt, updatedAt := u.CreatedAt(), u.UpdatedAt()
+	return userEvent{
+		ID:        u.ID(),
+		Name:      u.Name(),
+		Email:     u.Email(),
+		Role:      string(u.Role()),
+		CreatedAt: &createdAt,
+		UpdatedAt: &updatedAt,
+	}
+}
+
+// withEvent extends stmt, which writes users and returns their id, to also
+// insert an outbox event with payload for every row it writes. Both happen
+// in one statement, so a change never commits without its event. args are
+// stmt's arguments; the event's follow them.
+func withEvent(stmt string, args []any, topic string, payload userEvent) (string, []any) {
+	raw := marshalEvent(payload)
+
+	n := len(args)
+	query := fmt.Sprintf(`
+		WITH changed AS (%s)
+		INSERT INTO outbox (event_id, topic, aggregate_id, payload, created_at, next_attempt_at)
+		SELECT $%d::uuid, $%d::text, id, $%d::jsonb, $%d::timestamptz, $%d::timestamptz FROM changed
+	`, stmt, n+1, n+2, n+3, n+4, n+4)
+	return query, append(args, uuid.New(), topic, raw, time.Now().UTC())
+}
+
+// marshalEvent encodes an event payload.
+func marshalEvent(payload userEvent) string {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		// Plain strings, UUIDs and times always marshal.
+		panic(fmt.Sprintf("postgres: marshal user event: %v", err))
+	}
+	return string(raw)
+}
+
+// scanSearchHit hydrates a hit from a row selected with userColumns and
+// its rank.
+func scanSearchHit(row pgx.Row) (user.SearchHit, error) {
+	var uid uuid.UUID
+	var name, email, passwordHash, role string
+	var createdAt, updatedAt time.Time
+	var version int64
+	var rank float64
+
+	if err := row.Scan(&uid, &name, &email, &passwordHash, &role, &createdAt, &updatedAt, &version, &rank); err != nil {
+		return user.SearchHit{}, err
+	}
+
+	u := user.Reconstruct(uid, name, email, passwordHash, user.Role(role), createdAt, updatedAt, version)
+	return user.SearchHit{User: u, Rank: rank}, nil
+}
+
+// scanUser hydrates a user from a row selected with userColumns.
+func scanUser(row pgx.Row) (*user.User, error) {
+	var uid uuid.UUID
+	var name, email, passwordHash, role string
+	var createdAt, updatedAt time.Time
+	var version int64
+
+	if err := row.Scan(&uid, &name, &email, &passwordHash, &role, &createdAt, &updatedAt, &version); err != nil {
+		return nil, err
+	}
+
+	return user.Reconstruct(uid, name, email, passwordHash, user.Role(role), createdAt, updatedAt, version), nil
+}
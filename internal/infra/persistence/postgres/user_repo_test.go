@@ -0,0 +1,57 @@
+package postgres_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"usermanagement/internal/domain/user"
+	"usermanagement/internal/infra/logger"
+	"usermanagement/internal/infra/persistence/postgres"
+	"usermanagement/internal/infra/persistence/postgres/pgtest"
+)
+
+func TestSaveConcurrentDuplicateEmails(t *testing.T) {
+	pool := pgtest.Pool(t)
+	repo := postgres.NewUserRepository(pool, &logger.Logger{Logger: zap.NewNop()})
+	ctx := context.Background()
+	email := uuid.NewString() + "@example.com"
+	now := time.Now().UTC()
+
+	const n = 10
+	ids := make([]uuid.UUID, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := range ids {
+		ids[i] = uuid.New()
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			errs[i] = repo.Save(ctx, user.Reconstruct(ids[i], "Ada", email, "hash", user.RoleViewer, now, now, 1))
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	created := 0
+	for i, err := range errs {
+		switch {
+		case err == nil:
+			created++
+			id := ids[i]
+			t.Cleanup(func() { repo.Delete(context.Background(), id, 0) })
+		case !errors.Is(err, user.ErrEmailExists):
+			t.Errorf("Save = %v, want nil or ErrEmailExists", err)
+		}
+	}
+	if created != 1 {
+		t.Fatalf("%d of %d concurrent saves succeeded, want exactly 1", created, n)
+	}
+}
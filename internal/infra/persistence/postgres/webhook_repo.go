@@ -0,0 +1,356 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"usermanagement/internal/domain/webhook"
+	"usermanagement/internal/infra/logger"
+)
+
+// WebhookRepository implements webhook.SubscriptionRepository and
+// webhook.DeliveryRepository using PostgreSQL, and holds the delivery queue
+// of internal/infra/webhook.
+type WebhookRepository struct {
+	db     DB
+	logger *logger.Logger
+}
+
+// subscriptionColumns are selected by every subscription query, in
+// scanSubscription order.
+var subscriptionColumns = []string{"id", "owner_id", "url", "events", "secret", "created_at"}
+
+// deliveryColumns are selected by every delivery log query, in
+// scanDelivery order.
+var deliveryColumns = []string{"id", "subscription_id", "event_id", "topic", "payload::text", "status", "attempts", "last_status_code", "last_error", "created_at", "delivered_at"}
+
+// NewWebhookRepository creates a new PostgreSQL webhook repository.
+func NewWebhookRepository(db DB, logger *logger.Logger) *WebhookRepository {
+	return &WebhookRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Save persists a new subscription.
+func (r *WebhookRepository) Save(ctx context.Context, s *webhook.Subscription) error {
+	query := `
+		INSERT INTO webhook_subscriptions (id, owner_id, url, events, secret, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := conn(ctx, r.db).Exec(ctx, query,
+		s.ID(),
+		s.OwnerID(),
+		s.URL(),
+		s.Events(),
+		s.Secret(),
+		s.CreatedAt(),
+	)
+
+	if err != nil {
+		r.logger.Error("failed to save webhook", zap.Error(err))
+		return fmt.Errorf("%w: %w", webhook.ErrRepositoryInternal, err)
+	}
+
+	return nil
+}
+
+// FindByID retrieves a subscription by ID.
+func (r *WebhookRepository) FindByID(ctx context.Context, id uuid.UUID) (*webhook.Subscription, error) {
+	query, args := selectFrom("webhook_subscriptions", subscriptionColumns...).
+		Where("id = ?", id).
+		Build()
+
+	s, err := scanSubscription(conn(ctx, r.db).QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, webhook.ErrSubscriptionNotFound
+		}
+		r.logger.Error("failed to find webhook by id", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", webhook.ErrRepositoryInternal, err)
+	}
+
+	return s, nil
+}
+
+// FindByOwner retrieves paginated subscriptions of a user, newest first.
+func (r *WebhookRepository) FindByOwner(ctx context.Context, ownerID uuid.UUID, limit, offset int) ([]*webhook.Subscription, error) {
+	query, args := selectFrom("webhook_subscriptions", subscriptionColumns...).
+		Where("owner_id = ?", ownerID).
+		OrderBy("created_at DESC", "id DESC").
+		Page(limit, offset).
+		Build()
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("failed to list webhooks", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", webhook.ErrRepositoryInternal, err)
+	}
+	defer rows.Close()
+
+	var subs []*webhook.Subscription
+	for rows.Next() {
+		s, err := scanSubscription(rows)
+		if err != nil {
+			r.logger.Error("failed to scan webhook row", zap.Error(err))
+			return nil, fmt.Errorf("%w: %w", webhook.ErrRepositoryInternal, err)
+		}
+
+		subs = append(subs, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error("error iterating webhook rows", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", webhook.ErrRepositoryInternal, err)
+	}
+
+	return subs, nil
+}
+
+// CountByOwner returns the number of subscriptions of a user.
+func (r *WebhookRepository) CountByOwner(ctx context.Context, ownerID uuid.UUID) (int64, error) {
+	query, args := selectFrom("webhook_subscriptions", "count(*)").
+		Where("owner_id = ?", ownerID).
+		Build()
+
+	var total int64
+	if err := conn(ctx, r.db).QueryRow(ctx, query, args...).Scan(&total); err != nil {
+		r.logger.Error("failed to count webhooks", zap.Error(err))
+		return 0, fmt.Errorf("%w: %w", webhook.ErrRepositoryInternal, err)
+	}
+
+	return total, nil
+}
+
+// Delete removes a subscription by ID; the foreign key cascades to its
+// deliveries.
+func (r *WebhookRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM webhook_subscriptions WHERE id = $1`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, id)
+	if err != nil {
+		r.logger.Error("failed to delete webhook", zap.Error(err))
+		return fmt.Errorf("%w: %w", webhook.ErrRepositoryInternal, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return webhook.ErrSubscriptionNotFound
+	}
+
+	return nil
+}
+
+// FindBySubscription retrieves paginated deliveries of a subscription,
+// newest first.
+func (r *WebhookRepository) FindBySubscription(ctx context.Context, subscriptionID uuid.UUID, limit, offset int) ([]*webhook.Delivery, error) {
+	query, args := selectFrom("webhook_deliveries", deliveryColumns...).
+		Where("subscription_id = ?", subscriptionID).
+		OrderBy("created_at DESC", "id DESC").
+		Page(limit, offset).
+		Build()
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("failed to list webhook deliveries", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", webhook.ErrRepositoryInternal, err)
+	}
+	defer rows.Close()
+
+	var deliveries []*webhook.Delivery
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			r.logger.Error("failed to scan webhook delivery row", zap.Error(err))
+			return nil, fmt.Errorf("%w: %w", webhook.ErrRepositoryInternal, err)
+		}
+
+		deliveries = append(deliveries, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error("error iterating webhook delivery rows", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", webhook.ErrRepositoryInternal, err)
+	}
+
+	return deliveries, nil
+}
+
+// CountBySubscription returns the number of deliveries of a subscription.
+func (r *WebhookRepository) CountBySubscription(ctx context.Context, subscriptionID uuid.UUID) (int64, error) {
+	query, args := selectFrom("webhook_deliveries", "count(*)").
+		Where("subscription_id = ?", subscriptionID).
+		Build()
+
+	var total int64
+	if err := conn(ctx, r.db).QueryRow(ctx, query, args...).Scan(&total); err != nil {
+		r.logger.Error("failed to count webhook deliveries", zap.Error(err))
+		return 0, fmt.Errorf("%w: %w", webhook.ErrRepositoryInternal, err)
+	}
+
+	return total, nil
+}
+
+// PendingDelivery is a claimed delivery with what it takes to send it.
+type PendingDelivery struct {
+	ID        uuid.UUID
+	EventID   uuid.UUID
+	Topic     string
+	Payload   json.RawMessage
+	Attempts  int
+	CreatedAt time.Time
+	URL       string
+	Secret    string
+}
+
+// Enqueue queues an event for every subscription that selected its topic
+// and existed when it happened. Queueing the same event again adds nothing.
+func (r *WebhookRepository) Enqueue(ctx context.Context, eventID uuid.UUID, topic string, payload json.RawMessage, createdAt time.Time) error {
+	query := `
+		INSERT INTO webhook_deliveries (id, subscription_id, event_id, topic, payload, status, next_attempt_at, created_at)
+		SELECT md5(s.id::text || $1::text)::uuid, s.id, $1::uuid, $2::text, $3::jsonb, 'pending', now(), $4::timestamptz
+		FROM webhook_subscriptions s
+		WHERE $2::text = ANY(s.events) AND s.created_at <= $4::timestamptz
+		ON CONFLICT (id) DO NOTHING
+	`
+
+	if _, err := r.db.Exec(ctx, query, eventID, topic, string(payload), createdAt); err != nil {
+		return fmt.Errorf("enqueue webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+// Claim returns up to limit due deliveries and defers their next attempt by
+// lease, so other workers skip them meanwhile. Deliveries not settled
+// within lease are claimed again.
+func (r *WebhookRepository) Claim(ctx context.Context, limit int, lease time.Duration) ([]PendingDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = now() + $2::bigint * interval '1 millisecond'
+		FROM webhook_subscriptions s
+		WHERE s.id = d.subscription_id AND d.id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= now()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING d.id, d.event_id, d.topic, d.payload::text, d.attempts, d.created_at, s.url, s.secret
+	`
+
+	rows, err := r.db.Query(ctx, query, limit, lease.Milliseconds())
+	if err != nil {
+		return nil, fmt.Errorf("claim webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []PendingDelivery
+	for rows.Next() {
+		var (
+			d       PendingDelivery
+			payload string
+		)
+		if err := rows.Scan(&d.ID, &d.EventID, &d.Topic, &payload, &d.Attempts, &d.CreatedAt, &d.URL, &d.Secret); err != nil {
+			return nil, fmt.Errorf("scan webhook delivery: %w", err)
+		}
+		d.Payload = json.RawMessage(payload)
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("claim webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// MarkDelivered records a successful attempt.
+func (r *WebhookRepository) MarkDelivered(ctx context.Context, id uuid.UUID, statusCode int) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = 'delivered', attempts = attempts + 1, last_status_code = $2, last_error = NULL, delivered_at = now()
+		WHERE id = $1
+	`
+
+	if _, err := r.db.Exec(ctx, query, id, statusCode); err != nil {
+		return fmt.Errorf("mark webhook delivery delivered: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed records a failed attempt, answered with statusCode (zero when
+// the endpoint was not reached). The delivery is retried after backoff, or
+// given up on when final.
+func (r *WebhookRepository) MarkFailed(ctx context.Context, id uuid.UUID, statusCode int, cause error, backoff time.Duration, final bool) error {
+	status := webhook.DeliveryPending
+	if final {
+		status = webhook.DeliveryFailed
+	}
+
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = attempts + 1, last_status_code = NULLIF($3, 0), last_error = $4,
+			next_attempt_at = now() + $5::bigint * interval '1 millisecond'
+		WHERE id = $1
+	`
+
+	if _, err := r.db.Exec(ctx, query, id, string(status), statusCode, cause.Error(), backoff.Milliseconds()); err != nil {
+		return fmt.Errorf("mark webhook delivery failed: %w", err)
+	}
+	return nil
+}
+
+// DeleteSettled removes deliveries that succeeded or were given up on
+// before cutoff and reports how many were removed.
+func (r *WebhookRepository) DeleteSettled(ctx context.Context, cutoff time.Time) (int64, error) {
+	query := `DELETE FROM webhook_deliveries WHERE status <> 'pending' AND created_at < $1`
+
+	result, err := r.db.Exec(ctx, query, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("delete settled webhook deliveries: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// scanSubscription hydrates a subscription from a row selected with
+// subscriptionColumns.
+func scanSubscription(row pgx.Row) (*webhook.Subscription, error) {
+	var id, ownerID uuid.UUID
+	var url, secret string
+	var events []string
+	var createdAt time.Time
+
+	if err := row.Scan(&id, &ownerID, &url, &events, &secret, &createdAt); err != nil {
+		return nil, err
+	}
+
+	return webhook.Reconstruct(id, ownerID, url, events, secret, createdAt), nil
+}
+
+// scanDelivery hydrates a delivery from a row selected with deliveryColumns.
+func scanDelivery(row pgx.Row) (*webhook.Delivery, error) {
+	var d webhook.Delivery
+	var payload, status string
+	var lastStatusCode *int
+	var lastError *string
+
+	if err := row.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.Topic, &payload, &status, &d.Attempts, &lastStatusCode, &lastError, &d.CreatedAt, &d.DeliveredAt); err != nil {
+		return nil, err
+	}
+
+	d.Payload = json.RawMessage(payload)
+	d.Status = webhook.DeliveryStatus(status)
+	if lastStatusCode != nil {
+		d.LastStatusCode = *lastStatusCode
+	}
+	if lastError != nil {
+		d.LastError = *lastError
+	}
+	return &d, nil
+}
@@ -0,0 +1,249 @@
+// Package rediscache decorates repositories with a cache-aside layer in
+// Redis, shared by every replica.
+package rediscache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"usermanagement/internal/domain/transaction"
+	"usermanagement/internal/domain/user"
+	"usermanagement/internal/infra/deadline"
+	"usermanagement/internal/infra/logger"
+)
+
+var lookups = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "redis_cache_lookups_total",
+	Help: "Redis cache lookups by cache and result (hit, miss, error).",
+}, []string{"cache", "result"})
+
+// UserRepository caches FindByID and FindByEmail in Redis. Writes go to
+// next and then drop the cached user; a read racing with a write may still
+// store the old user, which TTL bounds.
+//
+// Cached users include their password hash, which logins read through
+// FindByEmail, so Redis must be as trusted as the database.
+//
+// Redis failures are logged and reads fall back to next: the cache never
+// makes a request fail. Each Redis call is bounded by budget, so a slow
+// Redis leaves time for the fallback.
+type UserRepository struct {
+	next   user.UserRepository
+	client *redis.Client
+	ttl    time.Duration
+	budget deadline.Budget
+	logger *logger.Logger
+}
+
+// NewUserRepository wraps next, caching users for ttl.
+func NewUserRepository(next user.UserRepository, client *redis.Client, ttl time.Duration, budget deadline.Budget, logger *logger.Logger) *UserRepository {
+	return &UserRepository{next: next, client: client, ttl: ttl, budget: budget, logger: logger}
+}
+
+// cachedUser is the JSON form of a cached user.
+type cachedUser struct {
+	ID           uuid.UUID `json:"id"`
+	Name         string    `json:"name"`
+	Email        string    `json:"email"`
+	PasswordHash string    `json:"password_hash"`
+	Role         user.Role `json:"role"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	Version      int64     `json:"version"`
+}
+
+func idKey(id uuid.UUID) string    { return "user:id:" + id.String() }
+func emailKey(email string) string { return "user:email:" + email }
+
+// Save persists a new user.
+func (r *UserRepository) Save(ctx context.Context, u *user.User) error {
+	return r.next.Save(ctx, u)
+}
+
+// SaveBatch persists new users, skipping taken emails.
+func (r *UserRepository) SaveBatch(ctx context.Context, users []*user.User) ([]uuid.UUID, error) {
+	return r.next.SaveBatch(ctx, users)
+}
+
+// FindByID retrieves a user by ID, from Redis when cached.
+func (r *UserRepository) FindByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	if transaction.Active(ctx) {
+		return r.next.FindByID(ctx, id)
+	}
+
+	if u, ok := r.get(ctx, "user_by_id", id); ok {
+		return u, nil
+	}
+
+	u, err := r.next.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	r.store(ctx, u)
+	return u, nil
+}
+
+// FindByEmail retrieves a user by email, from Redis when cached. The email
+// key only points at the ID key, so a user whose email has since changed
+// is not found under the old address.
+func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*user.User, error) {
+	if transaction.Active(ctx) {
+		return r.next.FindByEmail(ctx, email)
+	}
+
+	if u, ok := r.getByEmail(ctx, email); ok {
+		return u, nil
+	}
+
+	u, err := r.next.FindByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	r.store(ctx, u)
+	return u, nil
+}
+
+// FindAll retrieves paginated users, newest first.
+func (r *UserRepository) FindAll(ctx context.Context, limit, offset int) ([]*user.User, error) {
+	return r.next.FindAll(ctx, limit, offset)
+}
+
+// FindAfter retrieves up to limit users following after.
+func (r *UserRepository) FindAfter(ctx context.Context, after *user.Keyset, limit int) ([]*user.User, error) {
+	return r.next.FindAfter(ctx, after, limit)
+}
+
+// Search finds users by name or email.
+func (r *UserRepository) Search(ctx context.Context, query string, page user.Page) (*user.SearchResult, error) {
+	return r.next.Search(ctx, query, page)
+}
+
+// Count returns the total number of users.
+func (r *UserRepository) Count(ctx context.Context) (int64, error) {
+	return r.next.Count(ctx)
+}
+
+// Update modifies an existing user, then drops it from the cache.
+func (r *UserRepository) Update(ctx context.Context, u *user.User) error {
+	if err := r.next.Update(ctx, u); err != nil {
+		return err
+	}
+	r.invalidate(ctx, u.ID())
+	return nil
+}
+
+// Delete removes a user by ID, then drops it from the cache.
+func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID, version int64) error {
+	if err := r.next.Delete(ctx, id, version); err != nil {
+		return err
+	}
+	r.invalidate(ctx, id)
+	return nil
+}
+
+// get reads the user cached under id, counting the lookup for cache.
+func (r *UserRepository) get(ctx context.Context, cache string, id uuid.UUID) (*user.User, bool) {
+	ctx, cancel := r.budget.Bound(ctx)
+	raw, err := r.client.Get(ctx, idKey(id)).Bytes()
+	cancel()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			lookups.WithLabelValues(cache, "miss").Inc()
+		} else {
+			lookups.WithLabelValues(cache, "error").Inc()
+			r.logger.Warn("failed to read user from cache", zap.Error(err))
+		}
+		return nil, false
+	}
+
+	var c cachedUser
+	if err := json.Unmarshal(raw, &c); err != nil {
+		lookups.WithLabelValues(cache, "error").Inc()
+		r.logger.Warn("failed to decode cached user", zap.Error(err))
+		return nil, false
+	}
+	// Users cached before versions existed would hand out a version
+	// updates are refused with.
+	if c.Version == 0 {
+		lookups.WithLabelValues(cache, "miss").Inc()
+		return nil, false
+	}
+	lookups.WithLabelValues(cache, "hit").Inc()
+	return user.Reconstruct(c.ID, c.Name, c.Email, c.PasswordHash, c.Role, c.CreatedAt, c.UpdatedAt, c.Version), true
+}
+
+// getByEmail follows the email key to the user it points at.
+func (r *UserRepository) getByEmail(ctx context.Context, email string) (*user.User, bool) {
+	lookupCtx, cancel := r.budget.Bound(ctx)
+	raw, err := r.client.Get(lookupCtx, emailKey(email)).Result()
+	cancel()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			lookups.WithLabelValues("user_by_email", "miss").Inc()
+		} else {
+			lookups.WithLabelValues("user_by_email", "error").Inc()
+			r.logger.Warn("failed to read user email from cache", zap.Error(err))
+		}
+		return nil, false
+	}
+
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		lookups.WithLabelValues("user_by_email", "error").Inc()
+		r.logger.Warn("failed to decode cached user email", zap.Error(err))
+		return nil, false
+	}
+	u, ok := r.get(ctx, "user_by_email", id)
+	return u, ok && u.Email() == email
+}
+
+// store caches u under its ID and points its email at it.
+func (r *UserRepository) store(ctx context.Context, u *user.User) {
+	raw, err := json.Marshal(cachedUser{
+		ID:           u.ID(),
+		Name:         u.Name(),
+		Email:        u.Email(),
+		PasswordHash: u.PasswordHash(),
+		Role:         u.Role(),
+		CreatedAt:    u.CreatedAt(),
+		UpdatedAt:    u.UpdatedAt(),
+		Version:      u.Version(),
+	})
+	if err != nil {
+		r.logger.Warn("failed to encode user for cache", zap.Error(err))
+		return
+	}
+
+	ctx, cancel := r.budget.Bound(ctx)
+	defer cancel()
+	_, err = r.client.Pipelined(ctx, func(p redis.Pipeliner) error {
+		p.Set(ctx, idKey(u.ID()), raw, r.ttl)
+		p.Set(ctx, emailKey(u.Email()), u.ID().String(), r.ttl)
+		return nil
+	})
+	if err != nil {
+		r.logger.Warn("failed to cache user", zap.Error(err))
+	}
+}
+
+// invalidate drops the user cached under id. Email keys pointing at it are
+// left to expire: lookups through them check the email of the user they
+// reach.
+func (r *UserRepository) invalidate(ctx context.Context, id uuid.UUID) {
+	// The write already happened; finish invalidating even if the request
+	// is canceled now.
+	if err := r.client.Del(context.WithoutCancel(ctx), idKey(id)).Err(); err != nil {
+		r.logger.Error("failed to invalidate cached user; it may be served stale until it expires",
+			zap.String("user_id", id.String()),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,320 @@
+package sharded
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
+
+	"usermanagement/internal/domain/user"
+)
+
+// UserRepository implements domain.UserRepository by spreading users across
+// several underlying repositories (one per database) based on a hash of the
+// user ID. Lookups by ID hit a single shard; lookups by email and listings
+// are scattered to all shards and gathered.
+//
+// The shard order is part of the data layout: adding, removing or reordering
+// shards requires moving rows to their new home first.
+type UserRepository struct {
+	shards []user.UserRepository
+}
+
+// NewUserRepository creates a sharded repository over the given shards.
+func NewUserRepository(shards []user.UserRepository) (*UserRepository, error) {
+	if len(shards) == 0 {
+		return nil, errors.New("sharded repository needs at least one shard")
+	}
+	return &UserRepository{shards: shards}, nil
+}
+
+// shardFor returns the shard owning the given user ID.
+func (r *UserRepository) shardFor(id uuid.UUID) user.UserRepository {
+	return r.shards[r.ShardIndex(id)]
+}
+
+// ShardIndex returns the position among the shards of the one owning the
+// given user ID, for callers keeping resources of their own per shard.
+func (r *UserRepository) ShardIndex(id uuid.UUID) int {
+	h := fnv.New32a()
+	h.Write(id[:])
+	return int(h.Sum32() % uint32(len(r.shards)))
+}
+
+// Save persists a new user on its shard. Each shard's unique constraint only
+// covers its own rows, so other shards are checked for the email first; two
+// concurrent registrations landing on different shards can still both win.
+func (r *UserRepository) Save(ctx context.Context, u *user.User) error {
+	_, err := r.FindByEmail(ctx, u.Email())
+	switch {
+	case err == nil:
+		return user.ErrEmailExists
+	case !errors.Is(err, user.ErrUserNotFound):
+		return err
+	}
+	return r.shardFor(u.ID()).Save(ctx, u)
+}
+
+// emailLookups bounds the concurrent email checks of SaveBatch.
+const emailLookups = 8
+
+// SaveBatch skips users whose email another shard already holds, as Save
+// does, then saves the rest on their shards in parallel. Each shard commits
+// on its own: when one fails, users saved on the others stay saved.
+func (r *UserRepository) SaveBatch(ctx context.Context, users []*user.User) ([]uuid.UUID, error) {
+	taken := make([]bool, len(users))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(emailLookups)
+	for i, u := range users {
+		i, u := i, u
+		g.Go(func() error {
+			_, err := r.FindByEmail(gctx, u.Email())
+			switch {
+			case err == nil:
+				taken[i] = true
+			case !errors.Is(err, user.ErrUserNotFound):
+				return err
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	batches := make([][]*user.User, len(r.shards))
+	for i, u := range users {
+		if !taken[i] {
+			shard := r.ShardIndex(u.ID())
+			batches[shard] = append(batches[shard], u)
+		}
+	}
+
+	results := make([][]uuid.UUID, len(r.shards))
+	err := r.scatter(ctx, func(ctx context.Context, i int, shard user.UserRepository) error {
+		if len(batches[i]) == 0 {
+			return nil
+		}
+		saved, err := shard.SaveBatch(ctx, batches[i])
+		results[i] = saved
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var saved []uuid.UUID
+	for _, ids := range results {
+		saved = append(saved, ids...)
+	}
+	return saved, nil
+}
+
+// FindByID retrieves a user from its shard.
+func (r *UserRepository) FindByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	return r.shardFor(id).FindByID(ctx, id)
+}
+
+// FindByEmail queries every shard and returns the first match.
+func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*user.User, error) {
+	results := make([]*user.User, len(r.shards))
+	err := r.scatter(ctx, func(ctx context.Context, i int, shard user.UserRepository) error {
+		u, err := shard.FindByEmail(ctx, email)
+		if err != nil {
+			if errors.Is(err, user.ErrUserNotFound) {
+				return nil
+			}
+			return err
+		}
+		results[i] = u
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, u := range results {
+		if u != nil {
+			return u, nil
+		}
+	}
+	return nil, user.ErrUserNotFound
+}
+
+// FindAll gathers the first limit+offset users of every shard, merges them
+// by creation time (newest first) and applies the requested window.
+func (r *UserRepository) FindAll(ctx context.Context, limit, offset int) ([]*user.User, error) {
+	results := make([][]*user.User, len(r.shards))
+	err := r.scatter(ctx, func(ctx context.Context, i int, shard user.UserRepository) error {
+		users, err := shard.FindAll(ctx, limit+offset, 0)
+		if err != nil {
+			return err
+		}
+		results[i] = users
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	merged := mergeNewestFirst(results)
+	if offset >= len(merged) {
+		return nil, nil
+	}
+	merged = merged[offset:]
+	if len(merged) > limit {
+		merged = merged[:limit]
+	}
+	return merged, nil
+}
+
+// FindAfter gathers up to limit users following after from every shard and
+// keeps the first limit of them in listing order. Unlike FindAll, the cost
+// does not grow with how deep the client has paged.
+func (r *UserRepository) FindAfter(ctx context.Context, after *user.Keyset, limit int) ([]*user.User, error) {
+	results := make([][]*user.User, len(r.shards))
+	err := r.scatter(ctx, func(ctx context.Context, i int, shard user.UserRepository) error {
+		users, err := shard.FindAfter(ctx, after, limit)
+		if err != nil {
+			return err
+		}
+		results[i] = users
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	merged := mergeNewestFirst(results)
+	if len(merged) > limit {
+		merged = merged[:limit]
+	}
+	return merged, nil
+}
+
+// Search gathers the first limit+offset hits of every shard, merges them
+// by rank and applies the requested window. Ranks depend on a user's own
+// name and email only, so they compare across shards.
+func (r *UserRepository) Search(ctx context.Context, query string, page user.Page) (*user.SearchResult, error) {
+	results := make([]*user.SearchResult, len(r.shards))
+	err := r.scatter(ctx, func(ctx context.Context, i int, shard user.UserRepository) error {
+		result, err := shard.Search(ctx, query, user.Page{Limit: page.Limit + page.Offset})
+		if err != nil {
+			return err
+		}
+		results[i] = result
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	merged := &user.SearchResult{}
+	for _, result := range results {
+		merged.Hits = append(merged.Hits, result.Hits...)
+		merged.Total += result.Total
+	}
+	sort.Slice(merged.Hits, func(i, j int) bool {
+		a, b := merged.Hits[i], merged.Hits[j]
+		if a.Rank != b.Rank {
+			return a.Rank > b.Rank
+		}
+		return newerFirst(a.User, b.User)
+	})
+
+	if page.Offset >= len(merged.Hits) {
+		merged.Hits = nil
+		return merged, nil
+	}
+	merged.Hits = merged.Hits[page.Offset:]
+	if len(merged.Hits) > page.Limit {
+		merged.Hits = merged.Hits[:page.Limit]
+	}
+	return merged, nil
+}
+
+// mergeNewestFirst merges per-shard results into the order the shards list
+// in: creation time descending, then ID descending, as Postgres compares
+// UUIDs byte by byte.
+func mergeNewestFirst(results [][]*user.User) []*user.User {
+	var merged []*user.User
+	for _, users := range results {
+		merged = append(merged, users...)
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return newerFirst(merged[i], merged[j])
+	})
+	return merged
+}
+
+// newerFirst reports whether a comes before b in listing order.
+func newerFirst(a, b *user.User) bool {
+	if !a.CreatedAt().Equal(b.CreatedAt()) {
+		return a.CreatedAt().After(b.CreatedAt())
+	}
+	aID, bID := a.ID(), b.ID()
+	return bytes.Compare(aID[:], bID[:]) > 0
+}
+
+// Count sums the user counts of every shard.
+func (r *UserRepository) Count(ctx context.Context) (int64, error) {
+	counts := make([]int64, len(r.shards))
+	err := r.scatter(ctx, func(ctx context.Context, i int, shard user.UserRepository) error {
+		n, err := shard.Count(ctx)
+		counts[i] = n
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, n := range counts {
+		total += n
+	}
+	return total, nil
+}
+
+// Update modifies a user on its shard.
+func (r *UserRepository) Update(ctx context.Context, u *user.User) error {
+	return r.shardFor(u.ID()).Update(ctx, u)
+}
+
+// Delete removes a user from its shard.
+func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID, version int64) error {
+	return r.shardFor(id).Delete(ctx, id, version)
+}
+
+// scatter runs fn against every shard concurrently and returns the first error.
+// Remaining shards are cancelled as soon as one fails.
+func (r *UserRepository) scatter(ctx context.Context, fn func(ctx context.Context, i int, shard user.UserRepository) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	for i, shard := range r.shards {
+		wg.Add(1)
+		go func(i int, shard user.UserRepository) {
+			defer wg.Done()
+			if err := fn(ctx, i, shard); err != nil {
+				once.Do(func() {
+					firstErr = fmt.Errorf("shard %d: %w", i, err)
+					cancel()
+				})
+			}
+		}(i, shard)
+	}
+	wg.Wait()
+
+	return firstErr
+}
@@ -0,0 +1,262 @@
+package sharded_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"usermanagement/internal/domain/user"
+	"usermanagement/internal/infra/persistence/memory"
+	"usermanagement/internal/infra/persistence/sharded"
+)
+
+// newRepo spreads users over n memory shards.
+func newRepo(t *testing.T, n int) (*sharded.UserRepository, []*memory.UserRepository) {
+	t.Helper()
+	shards := make([]*memory.UserRepository, n)
+	repos := make([]user.UserRepository, n)
+	for i := range shards {
+		shards[i] = memory.NewUserRepository()
+		repos[i] = shards[i]
+	}
+	repo, err := sharded.NewUserRepository(repos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repo, shards
+}
+
+// seqID returns the n-th of a sequence of user IDs.
+func seqID(n int) uuid.UUID {
+	var id uuid.UUID
+	binary.BigEndian.PutUint64(id[8:], uint64(n))
+	return id
+}
+
+// idOnShard returns the first ID of the sequence from start that repo
+// places on shard.
+func idOnShard(repo *sharded.UserRepository, shard, start int) uuid.UUID {
+	for n := start; ; n++ {
+		if id := seqID(n); repo.ShardIndex(id) == shard {
+			return id
+		}
+	}
+}
+
+func newUser(id uuid.UUID, email string, createdAt time.Time) *user.User {
+	return user.Reconstruct(id, "User", email, "hash", user.RoleViewer, createdAt, createdAt, 1)
+}
+
+func TestShardIndexIsStable(t *testing.T) {
+	// Users are stored where these indexes put them; changing the hash
+	// strands every existing user on the wrong shard.
+	tests := []struct {
+		id     string
+		shards int
+		want   int
+	}{
+		{"00000000-0000-0000-0000-000000000001", 3, 1},
+		{"00000000-0000-0000-0000-000000000002", 3, 2},
+		{"00000000-0000-0000-0000-000000000003", 3, 0},
+		{"0190a6b2-7c3e-7d4f-8a1b-2c3d4e5f6a7b", 3, 0},
+		{"f47ac10b-58cc-4372-a567-0e02b2c3d479", 3, 2},
+		{"00000000-0000-0000-0000-000000000002", 2, 1},
+		{"f47ac10b-58cc-4372-a567-0e02b2c3d479", 1, 0},
+	}
+	for _, tt := range tests {
+		repo, _ := newRepo(t, tt.shards)
+		if got := repo.ShardIndex(uuid.MustParse(tt.id)); got != tt.want {
+			t.Errorf("ShardIndex(%s) over %d shards = %d, want %d", tt.id, tt.shards, got, tt.want)
+		}
+	}
+}
+
+func TestSaveRoutesToTheOwningShard(t *testing.T) {
+	repo, shards := newRepo(t, 3)
+	ctx := context.Background()
+	now := time.Now()
+
+	for n := 1; n <= 12; n++ {
+		id := seqID(n)
+		if err := repo.Save(ctx, newUser(id, id.String()+"@example.com", now)); err != nil {
+			t.Fatal(err)
+		}
+		for i, shard := range shards {
+			_, err := shard.FindByID(ctx, id)
+			if owns := i == repo.ShardIndex(id); owns != (err == nil) {
+				t.Fatalf("user %s on shard %d: %v, want it only on shard %d", id, i, err, repo.ShardIndex(id))
+			}
+		}
+		if _, err := repo.FindByID(ctx, id); err != nil {
+			t.Fatalf("FindByID(%s) = %v", id, err)
+		}
+	}
+}
+
+func TestSaveChecksEmailsAcrossShards(t *testing.T) {
+	repo, _ := newRepo(t, 3)
+	ctx := context.Background()
+	now := time.Now()
+
+	first, second := idOnShard(repo, 0, 1), idOnShard(repo, 1, 1)
+	if err := repo.Save(ctx, newUser(first, "ada@example.com", now)); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Save(ctx, newUser(second, "ada@example.com", now)); !errors.Is(err, user.ErrEmailExists) {
+		t.Fatalf("Save on another shard = %v, want ErrEmailExists", err)
+	}
+	if found, err := repo.FindByEmail(ctx, "ada@example.com"); err != nil || found.ID() != first {
+		t.Fatalf("FindByEmail = %v, %v; want %s", found, err, first)
+	}
+}
+
+// failingShard saves nothing in batches.
+type failingShard struct {
+	*memory.UserRepository
+}
+
+func (failingShard) SaveBatch(context.Context, []*user.User) ([]uuid.UUID, error) {
+	return nil, errors.New("shard down")
+}
+
+func TestSaveBatch(t *testing.T) {
+	shards := []*memory.UserRepository{memory.NewUserRepository(), memory.NewUserRepository(), memory.NewUserRepository()}
+	ctx := context.Background()
+	now := time.Now()
+
+	t.Run("skips emails taken on any shard", func(t *testing.T) {
+		repo, err := sharded.NewUserRepository([]user.UserRepository{shards[0], shards[1], shards[2]})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.Save(ctx, newUser(idOnShard(repo, 2, 1), "taken@example.com", now)); err != nil {
+			t.Fatal(err)
+		}
+
+		fresh := idOnShard(repo, 0, 100)
+		saved, err := repo.SaveBatch(ctx, []*user.User{
+			newUser(idOnShard(repo, 1, 100), "taken@example.com", now),
+			newUser(fresh, "fresh@example.com", now),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(saved) != 1 || saved[0] != fresh {
+			t.Fatalf("SaveBatch saved %v, want only %s", saved, fresh)
+		}
+	})
+
+	t.Run("keeps what other shards saved when one fails", func(t *testing.T) {
+		repo, err := sharded.NewUserRepository([]user.UserRepository{shards[0], failingShard{shards[1]}, shards[2]})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		onHealthy, onFailing := idOnShard(repo, 0, 200), idOnShard(repo, 1, 200)
+		_, err = repo.SaveBatch(ctx, []*user.User{
+			newUser(onHealthy, "healthy@example.com", now),
+			newUser(onFailing, "failing@example.com", now),
+		})
+		if err == nil {
+			t.Fatal("SaveBatch succeeded with a shard down")
+		}
+		if _, err := repo.FindByID(ctx, onHealthy); err != nil {
+			t.Fatalf("user on the healthy shard: %v, want it saved", err)
+		}
+		if _, err := repo.FindByID(ctx, onFailing); !errors.Is(err, user.ErrUserNotFound) {
+			t.Fatalf("user on the failing shard: %v, want ErrUserNotFound", err)
+		}
+	})
+}
+
+func TestListingsMergeShardsInOrder(t *testing.T) {
+	repo, shards := newRepo(t, 3)
+	ctx := context.Background()
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Some users share a creation time, so ties are broken by ID across
+	// shards too.
+	var want []*user.User
+	for n := 1; n <= 20; n++ {
+		u := newUser(seqID(n), seqID(n).String()+"@example.com", base.Add(time.Duration(n/3)*time.Minute))
+		if err := repo.Save(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+		want = append(want, u)
+	}
+	sort.Slice(want, func(i, j int) bool {
+		a, b := want[i], want[j]
+		if !a.CreatedAt().Equal(b.CreatedAt()) {
+			return a.CreatedAt().After(b.CreatedAt())
+		}
+		aID, bID := a.ID(), b.ID()
+		return bytes.Compare(aID[:], bID[:]) > 0
+	})
+	for i, shard := range shards {
+		if n, _ := shard.Count(ctx); n == 0 {
+			t.Fatalf("shard %d holds no users; the test needs every shard", i)
+		}
+	}
+
+	t.Run("FindAfter", func(t *testing.T) {
+		var got []*user.User
+		var after *user.Keyset
+		for {
+			page, err := repo.FindAfter(ctx, after, 3)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, page...)
+			if len(page) < 3 {
+				break
+			}
+			last := page[len(page)-1]
+			after = &user.Keyset{CreatedAt: last.CreatedAt(), ID: last.ID()}
+		}
+		assertOrder(t, got, want)
+	})
+
+	t.Run("FindAll", func(t *testing.T) {
+		for _, window := range []struct{ limit, offset int }{{5, 0}, {5, 5}, {7, 15}, {5, 20}} {
+			got, err := repo.FindAll(ctx, window.limit, window.offset)
+			if err != nil {
+				t.Fatal(err)
+			}
+			end := min(window.offset+window.limit, len(want))
+			assertOrder(t, got, want[min(window.offset, end):end])
+		}
+	})
+
+	t.Run("Count", func(t *testing.T) {
+		var perShard int64
+		for _, shard := range shards {
+			n, _ := shard.Count(ctx)
+			perShard += n
+		}
+		total, err := repo.Count(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 20 || total != perShard {
+			t.Fatalf("Count = %d, want 20, the sum over shards", total)
+		}
+	})
+}
+
+func assertOrder(t *testing.T, got, want []*user.User) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d users, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].ID() != want[i].ID() {
+			t.Fatalf("user %d is %s, want %s", i, got[i].ID(), want[i].ID())
+		}
+	}
+}
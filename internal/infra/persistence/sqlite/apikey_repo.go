@@ -0,0 +1,172 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"usermanagement/internal/domain/apikey"
+	"usermanagement/internal/infra/logger"
+)
+
+// APIKeyRepository implements apikey.Repository using SQLite.
+type APIKeyRepository struct {
+	db     DB
+	logger *logger.Logger
+}
+
+// apiKeyColumns are selected by every API key query, in scanAPIKey order.
+const apiKeyColumns = `id, owner_id, name, prefix, secret_hash, scopes, created_at, revoked_at`
+
+// NewAPIKeyRepository creates a new SQLite API key repository.
+func NewAPIKeyRepository(db DB, logger *logger.Logger) *APIKeyRepository {
+	return &APIKeyRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Save persists a new key.
+func (r *APIKeyRepository) Save(ctx context.Context, k *apikey.Key) error {
+	query := `
+		INSERT INTO api_keys (id, owner_id, name, prefix, secret_hash, scopes, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	scopes, err := json.Marshal(k.Scopes())
+	if err != nil {
+		return fmt.Errorf("%w: %w", apikey.ErrRepositoryInternal, err)
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query,
+		k.ID(),
+		k.OwnerID(),
+		k.Name(),
+		k.Prefix(),
+		k.Hash(),
+		string(scopes),
+		utc(k.CreatedAt()),
+	)
+
+	if err != nil {
+		r.logger.Error("failed to save API key", zap.Error(err))
+		return fmt.Errorf("%w: %w", apikey.ErrRepositoryInternal, err)
+	}
+
+	return nil
+}
+
+// FindByID retrieves a key by ID.
+func (r *APIKeyRepository) FindByID(ctx context.Context, id uuid.UUID) (*apikey.Key, error) {
+	return r.findOne(ctx, "id = ?", id)
+}
+
+// FindByPrefix retrieves a key by its public prefix.
+func (r *APIKeyRepository) FindByPrefix(ctx context.Context, prefix string) (*apikey.Key, error) {
+	return r.findOne(ctx, "prefix = ?", prefix)
+}
+
+// findOne retrieves the key matching cond.
+func (r *APIKeyRepository) findOne(ctx context.Context, cond string, arg any) (*apikey.Key, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE ` + cond
+
+	k, err := scanAPIKey(conn(ctx, r.db).QueryRowContext(ctx, query, arg))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apikey.ErrKeyNotFound
+		}
+		r.logger.Error("failed to find API key", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", apikey.ErrRepositoryInternal, err)
+	}
+
+	return k, nil
+}
+
+// FindByOwner retrieves paginated keys of a user, newest first.
+func (r *APIKeyRepository) FindByOwner(ctx context.Context, ownerID uuid.UUID, limit, offset int) ([]*apikey.Key, error) {
+	query := `
+		SELECT ` + apiKeyColumns + ` FROM api_keys
+		WHERE owner_id = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, ownerID, limit, offset)
+	if err != nil {
+		r.logger.Error("failed to list API keys", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", apikey.ErrRepositoryInternal, err)
+	}
+	defer rows.Close()
+
+	var keys []*apikey.Key
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			r.logger.Error("failed to scan API key row", zap.Error(err))
+			return nil, fmt.Errorf("%w: %w", apikey.ErrRepositoryInternal, err)
+		}
+
+		keys = append(keys, k)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error("error iterating API key rows", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", apikey.ErrRepositoryInternal, err)
+	}
+
+	return keys, nil
+}
+
+// CountByOwner returns the number of keys of a user.
+func (r *APIKeyRepository) CountByOwner(ctx context.Context, ownerID uuid.UUID) (int64, error) {
+	var total int64
+	if err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT count(*) FROM api_keys WHERE owner_id = ?`, ownerID).Scan(&total); err != nil {
+		r.logger.Error("failed to count API keys", zap.Error(err))
+		return 0, fmt.Errorf("%w: %w", apikey.ErrRepositoryInternal, err)
+	}
+
+	return total, nil
+}
+
+// Revoke stores the revocation of a key. A key revoked concurrently keeps
+// its first revocation time.
+func (r *APIKeyRepository) Revoke(ctx context.Context, k *apikey.Key) error {
+	query := `UPDATE api_keys SET revoked_at = COALESCE(revoked_at, ?) WHERE id = ?`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, utcPtr(k.RevokedAt()), k.ID())
+	if err != nil {
+		r.logger.Error("failed to revoke API key", zap.Error(err))
+		return fmt.Errorf("%w: %w", apikey.ErrRepositoryInternal, err)
+	}
+
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return apikey.ErrKeyNotFound
+	}
+
+	return nil
+}
+
+// scanAPIKey hydrates a key from a row selected with apiKeyColumns.
+func scanAPIKey(row row) (*apikey.Key, error) {
+	var id, ownerID uuid.UUID
+	var name, prefix, scopes string
+	var hash []byte
+	var createdAt time.Time
+	var revokedAt *time.Time
+
+	if err := row.Scan(&id, &ownerID, &name, &prefix, &hash, &scopes, &createdAt, &revokedAt); err != nil {
+		return nil, err
+	}
+
+	var keyScopes []apikey.Scope
+	if err := json.Unmarshal([]byte(scopes), &keyScopes); err != nil {
+		return nil, fmt.Errorf("decode scopes: %w", err)
+	}
+	return apikey.Reconstruct(id, ownerID, name, prefix, hash, keyScopes, createdAt, revokedAt), nil
+}
@@ -0,0 +1,92 @@
+// Package sqlite implements the repositories on an embedded SQLite
+// database, for single-binary deployments: local runs and small
+// self-hosted installs that have no Postgres to connect to. The driver is
+// pure Go, so the binary still builds without CGO.
+//
+// Like memory storage it has no outbox: user events are neither published
+// nor delivered to webhooks.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// DB is the subset of database/sql used by the repositories. It is
+// satisfied by *sql.DB and *sql.Tx.
+type DB interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// busyTimeout is how long a statement waits for another connection's
+// write to finish before failing with SQLITE_BUSY.
+const busyTimeout = 5 * time.Second
+
+// Open opens the database file at path, creating it if needed, and applies
+// pending migrations.
+//
+// Connections run in WAL mode, so reads proceed during writes, and enforce
+// foreign keys. Transactions take the write lock when they begin: SQLite
+// has no row locks, and upgrading a read transaction to a write one fails
+// instead of waiting when another connection writes meanwhile.
+func Open(ctx context.Context, path string) (*sql.DB, error) {
+	params := url.Values{
+		"_pragma": {
+			"foreign_keys(1)",
+			"journal_mode(WAL)",
+			fmt.Sprintf("busy_timeout(%d)", busyTimeout.Milliseconds()),
+		},
+		// Times are written as "2006-01-02 15:04:05.999999999-07:00" and
+		// always in UTC, so they also sort and compare as text.
+		"_time_format": {"sqlite"},
+		"_txlock":      {"immediate"},
+	}
+	db, err := sql.Open("sqlite", path+"?"+params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	if err := migrate(ctx, db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// utc normalizes t for storage; see Open.
+func utc(t time.Time) time.Time {
+	return t.UTC()
+}
+
+// utcPtr is utc for optional times.
+func utcPtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
+
+// isUniqueViolation reports whether err is a UNIQUE constraint failing on
+// column, written as table.column.
+func isUniqueViolation(err error, column string) bool {
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) &&
+		sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE &&
+		strings.Contains(sqliteErr.Error(), column)
+}
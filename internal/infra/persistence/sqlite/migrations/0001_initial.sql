@@ -0,0 +1,72 @@
+-- The schema of internal/infra/persistence/migrations as of its version 9,
+-- less what SQLite storage has no use for: the outbox, distributed locks,
+-- webhook deliveries, idempotency keys (kept in memory) and the search
+-- column and trigram indexes. UUIDs are stored as text; times as text in
+-- UTC, declared TIMESTAMP so the driver reads them back as times.
+CREATE TABLE IF NOT EXISTS users (
+    id            TEXT PRIMARY KEY,
+    name          TEXT NOT NULL,
+    email         TEXT NOT NULL UNIQUE,
+    password_hash TEXT NOT NULL DEFAULT '',
+    role          TEXT NOT NULL DEFAULT 'viewer',
+    created_at    TIMESTAMP NOT NULL,
+    updated_at    TIMESTAMP NOT NULL,
+    version       INTEGER NOT NULL DEFAULT 1
+);
+
+CREATE INDEX IF NOT EXISTS users_created_at_id_idx ON users (created_at DESC, id DESC);
+
+CREATE TABLE IF NOT EXISTS posts (
+    id           TEXT PRIMARY KEY,
+    author_id    TEXT NOT NULL,
+    title        TEXT NOT NULL,
+    slug         TEXT NOT NULL UNIQUE,
+    body         TEXT NOT NULL,
+    status       TEXT NOT NULL,
+    created_at   TIMESTAMP NOT NULL,
+    updated_at   TIMESTAMP NOT NULL,
+    published_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS posts_status_created_at_idx ON posts (status, created_at DESC);
+CREATE INDEX IF NOT EXISTS posts_author_id_idx ON posts (author_id);
+
+CREATE TABLE IF NOT EXISTS comments (
+    id         TEXT PRIMARY KEY,
+    post_id    TEXT NOT NULL REFERENCES posts (id) ON DELETE CASCADE,
+    author_id  TEXT NOT NULL,
+    parent_id  TEXT REFERENCES comments (id) ON DELETE CASCADE,
+    body       TEXT NOT NULL,
+    status     TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS comments_post_id_status_created_at_idx ON comments (post_id, status, created_at);
+CREATE INDEX IF NOT EXISTS comments_parent_id_idx ON comments (parent_id);
+
+-- events is a JSON array of topics.
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id         TEXT PRIMARY KEY,
+    owner_id   TEXT NOT NULL,
+    url        TEXT NOT NULL,
+    events     TEXT NOT NULL,
+    secret     TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS webhook_subscriptions_owner_idx ON webhook_subscriptions (owner_id, created_at DESC);
+
+-- scopes is a JSON array of scopes.
+CREATE TABLE IF NOT EXISTS api_keys (
+    id          TEXT PRIMARY KEY,
+    owner_id    TEXT NOT NULL,
+    name        TEXT NOT NULL,
+    prefix      TEXT NOT NULL UNIQUE,
+    secret_hash BLOB NOT NULL,
+    scopes      TEXT NOT NULL,
+    created_at  TIMESTAMP NOT NULL,
+    revoked_at  TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS api_keys_owner_idx ON api_keys (owner_id, created_at DESC);
@@ -0,0 +1,187 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"usermanagement/internal/domain/post"
+	"usermanagement/internal/infra/logger"
+)
+
+// PostRepository implements post.PostRepository using SQLite.
+type PostRepository struct {
+	db     DB
+	logger *logger.Logger
+}
+
+// postColumns are selected by every post query, in scanPost order.
+const postColumns = `id, author_id, title, slug, body, status, created_at, updated_at, published_at`
+
+// NewPostRepository creates a new SQLite post repository.
+func NewPostRepository(db DB, logger *logger.Logger) *PostRepository {
+	return &PostRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Save persists a new post.
+func (r *PostRepository) Save(ctx context.Context, p *post.Post) error {
+	query := `
+		INSERT INTO posts (id, author_id, title, slug, body, status, created_at, updated_at, published_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		p.ID(),
+		p.AuthorID(),
+		p.Title(),
+		p.Slug(),
+		p.Body(),
+		string(p.Status()),
+		utc(p.CreatedAt()),
+		utc(p.UpdatedAt()),
+		utcPtr(p.PublishedAt()),
+	)
+
+	if err != nil {
+		if isUniqueViolation(err, "posts.slug") {
+			return post.ErrSlugExists
+		}
+		r.logger.Error("failed to save post", zap.Error(err))
+		return fmt.Errorf("%w: %w", post.ErrRepositoryInternal, err)
+	}
+
+	return nil
+}
+
+// FindByID retrieves a post by ID.
+func (r *PostRepository) FindByID(ctx context.Context, id uuid.UUID) (*post.Post, error) {
+	query := `SELECT ` + postColumns + ` FROM posts WHERE id = ?`
+
+	p, err := scanPost(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, post.ErrPostNotFound
+		}
+		r.logger.Error("failed to find post by id", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", post.ErrRepositoryInternal, err)
+	}
+
+	return p, nil
+}
+
+// FindByStatus retrieves paginated posts in the given status, newest first.
+func (r *PostRepository) FindByStatus(ctx context.Context, status post.Status, limit, offset int) ([]*post.Post, error) {
+	query := `
+		SELECT ` + postColumns + ` FROM posts
+		WHERE status = ?
+		ORDER BY created_at DESC
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, string(status), limit, offset)
+	if err != nil {
+		r.logger.Error("failed to list posts", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", post.ErrRepositoryInternal, err)
+	}
+	defer rows.Close()
+
+	var posts []*post.Post
+	for rows.Next() {
+		p, err := scanPost(rows)
+		if err != nil {
+			r.logger.Error("failed to scan post row", zap.Error(err))
+			return nil, fmt.Errorf("%w: %w", post.ErrRepositoryInternal, err)
+		}
+
+		posts = append(posts, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error("error iterating post rows", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", post.ErrRepositoryInternal, err)
+	}
+
+	return posts, nil
+}
+
+// CountByStatus returns the number of posts in the given status.
+func (r *PostRepository) CountByStatus(ctx context.Context, status post.Status) (int64, error) {
+	var total int64
+	err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT count(*) FROM posts WHERE status = ?`, string(status)).Scan(&total)
+	if err != nil {
+		r.logger.Error("failed to count posts", zap.Error(err))
+		return 0, fmt.Errorf("%w: %w", post.ErrRepositoryInternal, err)
+	}
+
+	return total, nil
+}
+
+// Update modifies an existing post.
+func (r *PostRepository) Update(ctx context.Context, p *post.Post) error {
+	query := `
+		UPDATE posts
+		SET title = ?, slug = ?, body = ?, status = ?, updated_at = ?, published_at = ?
+		WHERE id = ?
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		p.Title(),
+		p.Slug(),
+		p.Body(),
+		string(p.Status()),
+		utc(p.UpdatedAt()),
+		utcPtr(p.PublishedAt()),
+		p.ID(),
+	)
+
+	if err != nil {
+		if isUniqueViolation(err, "posts.slug") {
+			return post.ErrSlugExists
+		}
+		r.logger.Error("failed to update post", zap.Error(err))
+		return fmt.Errorf("%w: %w", post.ErrRepositoryInternal, err)
+	}
+
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return post.ErrPostNotFound
+	}
+
+	return nil
+}
+
+// Delete removes a post by ID.
+func (r *PostRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM posts WHERE id = ?`, id)
+	if err != nil {
+		r.logger.Error("failed to delete post", zap.Error(err))
+		return fmt.Errorf("%w: %w", post.ErrRepositoryInternal, err)
+	}
+
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return post.ErrPostNotFound
+	}
+
+	return nil
+}
+
+// scanPost hydrates a post from a row selected with postColumns.
+func scanPost(row row) (*post.Post, error) {
+	var id, authorID uuid.UUID
+	var title, slug, body, status string
+	var createdAt, updatedAt time.Time
+	var publishedAt *time.Time
+
+	if err := row.Scan(&id, &authorID, &title, &slug, &body, &status, &createdAt, &updatedAt, &publishedAt); err != nil {
+		return nil, err
+	}
+
+	return post.Reconstruct(id, authorID, title, slug, body, post.Status(status), createdAt, updatedAt, publishedAt), nil
+}
@@ -0,0 +1,73 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"usermanagement/internal/domain/transaction"
+	"usermanagement/internal/infra/logger"
+)
+
+// TxManager implements transaction.UnitOfWork on one database. Repositories
+// built on the same *sql.DB join its transactions.
+type TxManager struct {
+	db     *sql.DB
+	logger *logger.Logger
+}
+
+// NewTxManager creates a unit of work beginning transactions on db.
+func NewTxManager(db *sql.DB, logger *logger.Logger) *TxManager {
+	return &TxManager{db: db, logger: logger}
+}
+
+// txKey is the context key of the running transaction.
+type txKey struct{}
+
+// activeTx is a transaction carried in a context.
+type activeTx struct {
+	// owner is the database the transaction runs on.
+	owner DB
+	tx    *sql.Tx
+}
+
+// Do implements transaction.UnitOfWork.
+func (m *TxManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if t, ok := ctx.Value(txKey{}).(*activeTx); ok && t.owner == DB(m.db) {
+		return fn(ctx)
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	// A no-op once committed; otherwise undoes fn's work, even when it
+	// panics.
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			m.logger.Warn("failed to roll back transaction", zap.Error(err))
+		}
+	}()
+
+	ctx = transaction.WithActive(ctx)
+	if err := fn(context.WithValue(ctx, txKey{}, &activeTx{owner: m.db, tx: tx})); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// conn returns where a repository built on db runs statements for ctx: the
+// transaction of a unit of work on db, or db itself.
+func conn(ctx context.Context, db DB) DB {
+	if t, ok := ctx.Value(txKey{}).(*activeTx); ok && t.owner == db {
+		return t.tx
+	}
+	return db
+}
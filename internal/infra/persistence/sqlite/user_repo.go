@@ -0,0 +1,389 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"usermanagement/internal/domain/user"
+	"usermanagement/internal/infra/logger"
+)
+
+// UserRepository implements domain.UserRepository using SQLite.
+type UserRepository struct {
+	db     DB
+	logger *logger.Logger
+}
+
+// userColumns are selected by every user query, in scanUser order.
+const userColumns = `id, name, email, password_hash, role, created_at, updated_at, version`
+
+// NewUserRepository creates a new SQLite user repository.
+func NewUserRepository(db DB, logger *logger.Logger) *UserRepository {
+	return &UserRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Save persists a new user.
+func (r *UserRepository) Save(ctx context.Context, u *user.User) error {
+	query := `
+		INSERT INTO users (id, name, email, password_hash, role, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		u.ID(),
+		u.Name(),
+		u.Email(),
+		u.PasswordHash(),
+		string(u.Role()),
+		utc(u.CreatedAt()),
+		utc(u.UpdatedAt()),
+	)
+
+	if err != nil {
+		if isUniqueViolation(err, "users.email") {
+			return user.ErrEmailExists
+		}
+		r.logger.Error("failed to save user", zap.Error(err))
+		return fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+
+	return nil
+}
+
+// SaveBatch persists users in one statement. Users whose email is taken
+// are skipped through ON CONFLICT instead of failing the batch.
+func (r *UserRepository) SaveBatch(ctx context.Context, users []*user.User) ([]uuid.UUID, error) {
+	if len(users) == 0 {
+		return nil, nil
+	}
+
+	rows := make([]string, len(users))
+	args := make([]any, 0, 7*len(users))
+	for i, u := range users {
+		rows[i] = "(?, ?, ?, ?, ?, ?, ?)"
+		args = append(args, u.ID(), u.Name(), u.Email(), u.PasswordHash(), string(u.Role()), utc(u.CreatedAt()), utc(u.UpdatedAt()))
+	}
+	query := `
+		INSERT INTO users (id, name, email, password_hash, role, created_at, updated_at)
+		VALUES ` + strings.Join(rows, ", ") + `
+		ON CONFLICT (email) DO NOTHING
+		RETURNING id
+	`
+
+	result, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("failed to save users", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+	defer result.Close()
+
+	saved := make([]uuid.UUID, 0, len(users))
+	for result.Next() {
+		var id uuid.UUID
+		if err := result.Scan(&id); err != nil {
+			r.logger.Error("failed to scan saved user id", zap.Error(err))
+			return nil, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+		}
+		saved = append(saved, id)
+	}
+	if err := result.Err(); err != nil {
+		r.logger.Error("failed to save users", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+
+	return saved, nil
+}
+
+// FindByID retrieves a user by ID. Inside a unit of work the transaction
+// already holds the database's write lock, so the caller's
+// read-modify-write cannot lose a concurrent update.
+func (r *UserRepository) FindByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = ?`
+
+	u, err := scanUser(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, user.ErrUserNotFound
+		}
+		r.logger.Error("failed to find user by id", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+
+	return u, nil
+}
+
+// FindByEmail retrieves a user by email.
+func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*user.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE email = ?`
+
+	u, err := scanUser(conn(ctx, r.db).QueryRowContext(ctx, query, email))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, user.ErrUserNotFound
+		}
+		r.logger.Error("failed to find user by email", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+
+	return u, nil
+}
+
+// FindAll retrieves paginated users, newest first.
+func (r *UserRepository) FindAll(ctx context.Context, limit, offset int) ([]*user.User, error) {
+	query := `
+		SELECT ` + userColumns + ` FROM users
+		ORDER BY created_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`
+
+	return r.findMany(ctx, query, limit, offset)
+}
+
+// FindAfter retrieves up to limit users following after, newest first.
+func (r *UserRepository) FindAfter(ctx context.Context, after *user.Keyset, limit int) ([]*user.User, error) {
+	if after == nil {
+		return r.FindAll(ctx, limit, 0)
+	}
+
+	query := `
+		SELECT ` + userColumns + ` FROM users
+		WHERE (created_at, id) < (?, ?)
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?
+	`
+
+	return r.findMany(ctx, query, utc(after.CreatedAt), after.ID, limit)
+}
+
+// Search finds users whose name or email contains every word of query,
+// like memory storage does: words found in the name rank twice as high as
+// those found in the email. SQLite's LIKE ignores ASCII case only.
+func (r *UserRepository) Search(ctx context.Context, query string, page user.Page) (*user.SearchResult, error) {
+	terms := strings.Fields(query)
+	if len(terms) == 0 {
+		return &user.SearchResult{}, nil
+	}
+
+	match := make([]string, len(terms))
+	rank := make([]string, len(terms))
+	var matchArgs, rankArgs []any
+	for i, term := range terms {
+		pattern := "%" + likeEscaper.Replace(term) + "%"
+		match[i] = `(name LIKE ? ESCAPE '\' OR email LIKE ? ESCAPE '\')`
+		matchArgs = append(matchArgs, pattern, pattern)
+		rank[i] = `CASE WHEN name LIKE ? ESCAPE '\' THEN 2 ELSE 1 END`
+		rankArgs = append(rankArgs, pattern)
+	}
+	where := strings.Join(match, " AND ")
+	db := conn(ctx, r.db)
+
+	args := append(append(rankArgs, matchArgs...), page.Limit, page.Offset)
+	rows, err := db.QueryContext(ctx, `
+		SELECT `+userColumns+`, CAST(`+strings.Join(rank, " + ")+` AS REAL) AS rank
+		FROM users
+		WHERE `+where+`
+		ORDER BY rank DESC, created_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`, args...)
+	if err != nil {
+		r.logger.Error("failed to search users", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+	defer rows.Close()
+
+	result := &user.SearchResult{}
+	for rows.Next() {
+		hit, err := scanSearchHit(rows)
+		if err != nil {
+			r.logger.Error("failed to scan user search row", zap.Error(err))
+			return nil, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+		}
+		result.Hits = append(result.Hits, hit)
+	}
+	if err := rows.Err(); err != nil {
+		r.logger.Error("error iterating user search rows", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+
+	if err := db.QueryRowContext(ctx, `SELECT count(*) FROM users WHERE `+where, matchArgs...).Scan(&result.Total); err != nil {
+		r.logger.Error("failed to count user search results", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+
+	return result, nil
+}
+
+// likeEscaper escapes LIKE wildcards, so user input matches literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// findMany runs a query selecting userColumns and hydrates every row.
+func (r *UserRepository) findMany(ctx context.Context, query string, args ...any) ([]*user.User, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("failed to list users", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+	defer rows.Close()
+
+	var users []*user.User
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			r.logger.Error("failed to scan user row", zap.Error(err))
+			return nil, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+		}
+
+		users = append(users, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error("error iterating user rows", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+
+	return users, nil
+}
+
+// Count returns the total number of users.
+func (r *UserRepository) Count(ctx context.Context) (int64, error) {
+	var total int64
+	if err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT count(*) FROM users`).Scan(&total); err != nil {
+		r.logger.Error("failed to count users", zap.Error(err))
+		return 0, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+
+	return total, nil
+}
+
+// Update modifies an existing user if it is still at u's version.
+func (r *UserRepository) Update(ctx context.Context, u *user.User) error {
+	query := `
+		UPDATE users
+		SET name = ?, email = ?, password_hash = ?, role = ?, updated_at = ?, version = version + 1
+		WHERE id = ? AND version = ?
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		u.Name(),
+		u.Email(),
+		u.PasswordHash(),
+		string(u.Role()),
+		utc(u.UpdatedAt()),
+		u.ID(),
+		u.Version(),
+	)
+
+	if err != nil {
+		if isUniqueViolation(err, "users.email") {
+			return user.ErrEmailExists
+		}
+		r.logger.Error("failed to update user", zap.Error(err))
+		return fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return r.updateMissed(ctx, u.ID())
+	}
+
+	u.AdvanceVersion()
+	return nil
+}
+
+// updateMissed explains an update or delete that matched no row: the user
+// is gone, or another update moved its version on.
+func (r *UserRepository) updateMissed(ctx context.Context, id uuid.UUID) error {
+	var exists bool
+	err := conn(ctx, r.db).QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE id = ?)`, id).Scan(&exists)
+	if err != nil {
+		r.logger.Error("failed to check user existence", zap.Error(err))
+		return fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+
+	if !exists {
+		return user.ErrUserNotFound
+	}
+	return user.ErrVersionConflict
+}
+
+// Delete removes a user by ID if it is still at version, or at any version
+// when version is 0.
+func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID, version int64) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM users WHERE id = ? AND (? = 0 OR version = ?)`, id, version, version)
+	if err != nil {
+		r.logger.Error("failed to delete user", zap.Error(err))
+		return fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		r.logger.Error("failed to delete user", zap.Error(err))
+		return fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+	if n == 0 {
+		return r.updateMissed(ctx, id)
+	}
+
+	return nil
+}
+
+// CountUsers returns the total number of users and how many were created at
+// or after since.
+func (r *UserRepository) CountUsers(ctx context.Context, since time.Time) (total, created int64, err error) {
+	query := `
+		SELECT count(*), count(*) FILTER (WHERE created_at >= ?)
+		FROM users
+	`
+
+	if err := conn(ctx, r.db).QueryRowContext(ctx, query, utc(since)).Scan(&total, &created); err != nil {
+		r.logger.Error("failed to count users", zap.Error(err))
+		return 0, 0, fmt.Errorf("%w: %w", user.ErrRepositoryInternal, err)
+	}
+
+	return total, created, nil
+}
+
+// row is a *sql.Row or *sql.Rows positioned on a row.
+type row interface {
+	Scan(dest ...any) error
+}
+
+// scanSearchHit hydrates a hit from a row selected with userColumns and
+// its rank.
+func scanSearchHit(row row) (user.SearchHit, error) {
+	var uid uuid.UUID
+	var name, email, passwordHash, role string
+	var createdAt, updatedAt time.Time
+	var version int64
+	var rank float64
+
+	if err := row.Scan(&uid, &name, &email, &passwordHash, &role, &createdAt, &updatedAt, &version, &rank); err != nil {
+		return user.SearchHit{}, err
+	}
+
+	u := user.Reconstruct(uid, name, email, passwordHash, user.Role(role), createdAt, updatedAt, version)
+	return user.SearchHit{User: u, Rank: rank}, nil
+}
+
+// scanUser hydrates a user from a row selected with userColumns.
+func scanUser(row row) (*user.User, error) {
+	var uid uuid.UUID
+	var name, email, passwordHash, role string
+	var createdAt, updatedAt time.Time
+	var version int64
+
+	if err := row.Scan(&uid, &name, &email, &passwordHash, &role, &createdAt, &updatedAt, &version); err != nil {
+		return nil, err
+	}
+
+	return user.Reconstruct(uid, name, email, passwordHash, user.Role(role), createdAt, updatedAt, version), nil
+}
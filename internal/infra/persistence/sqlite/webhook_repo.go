@@ -0,0 +1,172 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"usermanagement/internal/domain/webhook"
+	"usermanagement/internal/infra/logger"
+)
+
+// WebhookRepository implements webhook.SubscriptionRepository and
+// webhook.DeliveryRepository using SQLite. SQLite storage has no outbox, so
+// nothing is ever delivered and the delivery log stays empty.
+type WebhookRepository struct {
+	db     DB
+	logger *logger.Logger
+}
+
+// subscriptionColumns are selected by every subscription query, in
+// scanSubscription order.
+const subscriptionColumns = `id, owner_id, url, events, secret, created_at`
+
+// NewWebhookRepository creates a new SQLite webhook repository.
+func NewWebhookRepository(db DB, logger *logger.Logger) *WebhookRepository {
+	return &WebhookRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Save persists a new subscription.
+func (r *WebhookRepository) Save(ctx context.Context, s *webhook.Subscription) error {
+	query := `
+		INSERT INTO webhook_subscriptions (id, owner_id, url, events, secret, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	events, err := json.Marshal(s.Events())
+	if err != nil {
+		return fmt.Errorf("%w: %w", webhook.ErrRepositoryInternal, err)
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query,
+		s.ID(),
+		s.OwnerID(),
+		s.URL(),
+		string(events),
+		s.Secret(),
+		utc(s.CreatedAt()),
+	)
+
+	if err != nil {
+		r.logger.Error("failed to save webhook", zap.Error(err))
+		return fmt.Errorf("%w: %w", webhook.ErrRepositoryInternal, err)
+	}
+
+	return nil
+}
+
+// FindByID retrieves a subscription by ID.
+func (r *WebhookRepository) FindByID(ctx context.Context, id uuid.UUID) (*webhook.Subscription, error) {
+	query := `SELECT ` + subscriptionColumns + ` FROM webhook_subscriptions WHERE id = ?`
+
+	s, err := scanSubscription(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, webhook.ErrSubscriptionNotFound
+		}
+		r.logger.Error("failed to find webhook by id", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", webhook.ErrRepositoryInternal, err)
+	}
+
+	return s, nil
+}
+
+// FindByOwner retrieves paginated subscriptions of a user, newest first.
+func (r *WebhookRepository) FindByOwner(ctx context.Context, ownerID uuid.UUID, limit, offset int) ([]*webhook.Subscription, error) {
+	query := `
+		SELECT ` + subscriptionColumns + ` FROM webhook_subscriptions
+		WHERE owner_id = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, ownerID, limit, offset)
+	if err != nil {
+		r.logger.Error("failed to list webhooks", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", webhook.ErrRepositoryInternal, err)
+	}
+	defer rows.Close()
+
+	var subs []*webhook.Subscription
+	for rows.Next() {
+		s, err := scanSubscription(rows)
+		if err != nil {
+			r.logger.Error("failed to scan webhook row", zap.Error(err))
+			return nil, fmt.Errorf("%w: %w", webhook.ErrRepositoryInternal, err)
+		}
+
+		subs = append(subs, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error("error iterating webhook rows", zap.Error(err))
+		return nil, fmt.Errorf("%w: %w", webhook.ErrRepositoryInternal, err)
+	}
+
+	return subs, nil
+}
+
+// CountByOwner returns the number of subscriptions of a user.
+func (r *WebhookRepository) CountByOwner(ctx context.Context, ownerID uuid.UUID) (int64, error) {
+	query := `SELECT count(*) FROM webhook_subscriptions WHERE owner_id = ?`
+
+	var total int64
+	if err := conn(ctx, r.db).QueryRowContext(ctx, query, ownerID).Scan(&total); err != nil {
+		r.logger.Error("failed to count webhooks", zap.Error(err))
+		return 0, fmt.Errorf("%w: %w", webhook.ErrRepositoryInternal, err)
+	}
+
+	return total, nil
+}
+
+// Delete removes a subscription by ID.
+func (r *WebhookRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = ?`, id)
+	if err != nil {
+		r.logger.Error("failed to delete webhook", zap.Error(err))
+		return fmt.Errorf("%w: %w", webhook.ErrRepositoryInternal, err)
+	}
+
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return webhook.ErrSubscriptionNotFound
+	}
+
+	return nil
+}
+
+// FindBySubscription returns no deliveries; see WebhookRepository.
+func (r *WebhookRepository) FindBySubscription(ctx context.Context, subscriptionID uuid.UUID, limit, offset int) ([]*webhook.Delivery, error) {
+	return nil, nil
+}
+
+// CountBySubscription returns zero; see WebhookRepository.
+func (r *WebhookRepository) CountBySubscription(ctx context.Context, subscriptionID uuid.UUID) (int64, error) {
+	return 0, nil
+}
+
+// scanSubscription hydrates a subscription from a row selected with
+// subscriptionColumns.
+func scanSubscription(row row) (*webhook.Subscription, error) {
+	var id, ownerID uuid.UUID
+	var url, events, secret string
+	var createdAt time.Time
+
+	if err := row.Scan(&id, &ownerID, &url, &events, &secret, &createdAt); err != nil {
+		return nil, err
+	}
+
+	var topics []string
+	if err := json.Unmarshal([]byte(events), &topics); err != nil {
+		return nil, fmt.Errorf("decode events: %w", err)
+	}
+	return webhook.Reconstruct(id, ownerID, url, topics, secret, createdAt), nil
+}
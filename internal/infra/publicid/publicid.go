@@ -0,0 +1,126 @@
+// Package publicid translates the UUIDs of users and posts into the IDs
+// shown in public URLs and response bodies.
+//
+// Short IDs encrypt the UUID before encoding it. Encodings such as hashids
+// or sqids are reversible by anyone who knows the alphabet, which would
+// still reveal the creation time inside UUIDv7s; an encrypted ID reveals
+// nothing without the secret.
+package publicid
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalid is returned when decoding a string that is not a public ID.
+var ErrInvalid = errors.New("invalid public id")
+
+// Codec converts between UUIDs and public IDs.
+type Codec interface {
+	Encode(id uuid.UUID) string
+	Decode(s string) (uuid.UUID, error)
+}
+
+// minSecretLen is the minimum secret length of short IDs.
+const minSecretLen = 32
+
+// New returns the codec for mode: "uuid" shows UUIDs unchanged, "short"
+// shows 22-character IDs encrypted with a key derived from secret and kind,
+// such as "user", so every kind of entity gets unrelated IDs.
+//
+// Changing the secret changes every short ID and breaks existing links.
+func New(mode, secret, kind string) (Codec, error) {
+	switch mode {
+	case "uuid":
+		return plain{}, nil
+	case "short":
+		if len(secret) < minSecretLen {
+			return nil, fmt.Errorf("public id secret must be at least %d bytes", minSecretLen)
+		}
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte("publicid:" + kind))
+		block, err := aes.NewCipher(mac.Sum(nil)[:16])
+		if err != nil {
+			return nil, err
+		}
+		return &short{block: block}, nil
+	default:
+		return nil, fmt.Errorf("unknown public id mode %q", mode)
+	}
+}
+
+// plain shows UUIDs as they are.
+type plain struct{}
+
+func (plain) Encode(id uuid.UUID) string { return id.String() }
+
+func (plain) Decode(s string) (uuid.UUID, error) {
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return uuid.Nil, ErrInvalid
+	}
+	return id, nil
+}
+
+// alphabet is URL safe and free of punctuation, so IDs select as one word.
+const alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// shortLen is the number of base62 digits of a 128-bit value.
+const shortLen = 22
+
+var (
+	base     = big.NewInt(int64(len(alphabet)))
+	maxValue = new(big.Int).Lsh(big.NewInt(1), 128)
+)
+
+// short encrypts the 16 bytes of a UUID as one AES block, a permutation of
+// all 128-bit values, and writes the result in base62.
+type short struct {
+	block cipher.Block
+}
+
+func (c *short) Encode(id uuid.UUID) string {
+	var enc [16]byte
+	c.block.Encrypt(enc[:], id[:])
+
+	n := new(big.Int).SetBytes(enc[:])
+	digits := make([]byte, shortLen)
+	mod := new(big.Int)
+	for i := shortLen - 1; i >= 0; i-- {
+		n.DivMod(n, base, mod)
+		digits[i] = alphabet[mod.Int64()]
+	}
+	return string(digits)
+}
+
+func (c *short) Decode(s string) (uuid.UUID, error) {
+	if len(s) != shortLen {
+		return uuid.Nil, ErrInvalid
+	}
+
+	n := new(big.Int)
+	for i := 0; i < len(s); i++ {
+		d := strings.IndexByte(alphabet, s[i])
+		if d < 0 {
+			return uuid.Nil, ErrInvalid
+		}
+		n.Mul(n, base).Add(n, big.NewInt(int64(d)))
+	}
+	if n.Cmp(maxValue) >= 0 {
+		return uuid.Nil, ErrInvalid
+	}
+
+	var enc [16]byte
+	n.FillBytes(enc[:])
+	var id uuid.UUID
+	c.block.Decrypt(id[:], enc[:])
+	return id, nil
+}
@@ -0,0 +1,59 @@
+// Package ratelimit bounds how often a key may perform an action.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Window allows each key a fixed number of actions per time window. Counts
+// are kept in process memory, so each replica enforces its own limit.
+type Window struct {
+	limit  int
+	window time.Duration
+
+	mu     sync.Mutex
+	counts map[string]*count
+	// sweepAt is when expired counts are next dropped, so keys that never
+	// come back don't pile up.
+	sweepAt time.Time
+}
+
+// count is the actions of one key in its current window.
+type count struct {
+	start time.Time
+	n     int
+}
+
+// NewWindow allows limit actions per key every window.
+func NewWindow(limit int, window time.Duration) *Window {
+	return &Window{limit: limit, window: window, counts: make(map[string]*count)}
+}
+
+// Allow records an action for key and reports whether it is within the
+// limit.
+func (w *Window) Allow(key string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	if now.After(w.sweepAt) {
+		for k, c := range w.counts {
+			if now.Sub(c.start) >= w.window {
+				delete(w.counts, k)
+			}
+		}
+		w.sweepAt = now.Add(w.window)
+	}
+
+	c, ok := w.counts[key]
+	if !ok || now.Sub(c.start) >= w.window {
+		c = &count{start: now}
+		w.counts[key] = c
+	}
+	if c.n >= w.limit {
+		return false
+	}
+	c.n++
+	return true
+}
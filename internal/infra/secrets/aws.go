@@ -0,0 +1,86 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+// Schemes handled by the AWS providers.
+const (
+	SchemeSecretsManager = "secretsmanager"
+	SchemeSSM            = "ssm"
+)
+
+// SecretsManagerProvider reads secrets from AWS Secrets Manager.
+// References look like secretsmanager://prod/blog/db-password.
+type SecretsManagerProvider struct {
+	client *secretsmanager.Client
+}
+
+// Get returns the string value of the named secret.
+func (p *SecretsManagerProvider) Get(ctx context.Context, name string) (string, error) {
+	out, err := p.client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(name),
+	})
+	if err != nil {
+		return "", err
+	}
+	if out.SecretString == nil {
+		return "", fmt.Errorf("secret %q has no string value", name)
+	}
+	return *out.SecretString, nil
+}
+
+// SSMProvider reads parameters from AWS SSM Parameter Store, decrypting
+// SecureString parameters. References look like ssm:///blog/prod/db-password.
+type SSMProvider struct {
+	client *ssm.Client
+}
+
+// Get returns the value of the named parameter.
+func (p *SSMProvider) Get(ctx context.Context, name string) (string, error) {
+	out, err := p.client.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(name),
+		WithDecryption: aws.Bool(true),
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(out.Parameter.Value), nil
+}
+
+// NewAWSResolver builds a resolver for secretsmanager:// and ssm:// references
+// using the default AWS credential chain (env, shared config, IAM role).
+func NewAWSResolver(ctx context.Context) (*Resolver, error) {
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	return NewResolver(map[string]Provider{
+		SchemeSecretsManager: &SecretsManagerProvider{client: secretsmanager.NewFromConfig(awsCfg)},
+		SchemeSSM:            &SSMProvider{client: ssm.NewFromConfig(awsCfg)},
+	}), nil
+}
+
+// ResolveAWSEnv resolves AWS secret references found in the environment.
+// It returns a nil resolver, without touching AWS, when there are none.
+func ResolveAWSEnv(ctx context.Context) (*Resolver, error) {
+	if !HasReferences(SchemeSecretsManager, SchemeSSM) {
+		return nil, nil
+	}
+
+	resolver, err := NewAWSResolver(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := resolver.ResolveEnv(ctx); err != nil {
+		return nil, err
+	}
+	return resolver, nil
+}
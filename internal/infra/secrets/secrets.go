@@ -0,0 +1,122 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Provider fetches the value behind a secret reference (without its scheme).
+type Provider interface {
+	Get(ctx context.Context, name string) (string, error)
+}
+
+// Resolver replaces environment values of the form "<scheme>://<name>" with
+// the secret they point to, so credentials never have to live in env files.
+type Resolver struct {
+	providers map[string]Provider
+
+	mu   sync.RWMutex
+	refs map[string]string // env key -> reference
+}
+
+// NewResolver creates a resolver for the given scheme -> provider mapping,
+// e.g. {"secretsmanager": ..., "ssm": ...}.
+func NewResolver(providers map[string]Provider) *Resolver {
+	return &Resolver{
+		providers: providers,
+		refs:      make(map[string]string),
+	}
+}
+
+// HasReferences reports whether any environment variable uses one of the
+// given schemes. It lets callers skip building cloud clients entirely.
+func HasReferences(schemes ...string) bool {
+	for _, kv := range os.Environ() {
+		_, value, _ := strings.Cut(kv, "=")
+		for _, scheme := range schemes {
+			if strings.HasPrefix(value, scheme+"://") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ResolveEnv resolves every secret reference found in the environment and
+// overwrites the variable with the secret value.
+func (r *Resolver) ResolveEnv(ctx context.Context) error {
+	for _, kv := range os.Environ() {
+		key, value, _ := strings.Cut(kv, "=")
+		if _, _, ok := r.parse(value); !ok {
+			continue
+		}
+		r.mu.Lock()
+		r.refs[key] = value
+		r.mu.Unlock()
+	}
+
+	_, err := r.Refresh(ctx)
+	return err
+}
+
+// Refresh re-reads all known references and returns the keys whose value changed.
+func (r *Resolver) Refresh(ctx context.Context) ([]string, error) {
+	r.mu.RLock()
+	refs := make(map[string]string, len(r.refs))
+	for k, v := range r.refs {
+		refs[k] = v
+	}
+	r.mu.RUnlock()
+
+	var changed []string
+	for key, ref := range refs {
+		provider, name, _ := r.parse(ref)
+		value, err := provider.Get(ctx, name)
+		if err != nil {
+			return changed, fmt.Errorf("failed to resolve %s (%s): %w", key, ref, err)
+		}
+		if os.Getenv(key) != value {
+			os.Setenv(key, value)
+			changed = append(changed, key)
+		}
+	}
+	return changed, nil
+}
+
+// Watch refreshes the secrets every interval until ctx is done, reporting
+// changed keys and errors through the callbacks.
+func (r *Resolver) Watch(ctx context.Context, interval time.Duration, onChange func(keys []string), onError func(err error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			changed, err := r.Refresh(ctx)
+			if err != nil {
+				onError(err)
+			}
+			if len(changed) > 0 {
+				onChange(changed)
+			}
+		}
+	}
+}
+
+func (r *Resolver) parse(value string) (Provider, string, bool) {
+	scheme, name, ok := strings.Cut(value, "://")
+	if !ok {
+		return nil, "", false
+	}
+	provider, ok := r.providers[scheme]
+	if !ok {
+		return nil, "", false
+	}
+	return provider, name, true
+}
@@ -0,0 +1,319 @@
+// Package webhook delivers user events to the endpoints of webhook
+// subscriptions.
+//
+// The outbox relay hands every event to an Enqueuer, which queues one
+// delivery per subscription that selected it. A Sender then POSTs each
+// delivery, signed with the subscription's secret, retrying failures with
+// exponential backoff until MaxAttempts. Like the outbox, delivery is at
+// least once: receivers should dedupe on X-Webhook-Id.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"usermanagement/internal/infra/logger"
+	"usermanagement/internal/infra/outbox"
+	"usermanagement/internal/infra/persistence/postgres"
+)
+
+var deliveries = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "webhook_deliveries_total",
+	Help: "Webhook delivery attempts by result (delivered, retry, failed).",
+}, []string{"result"})
+
+const (
+	// lease is how long claimed deliveries are hidden from other senders.
+	lease = 5 * time.Minute
+	// firstRetry is the delay after the first failed attempt; it doubles
+	// with every further failure.
+	firstRetry = 30 * time.Second
+	// parallelism bounds concurrent requests, so one slow endpoint does not
+	// hold up the others.
+	parallelism = 8
+	// cleanupEvery is how often settled deliveries past retention are deleted.
+	cleanupEvery = time.Hour
+	// settleTimeout bounds recording an attempt's outcome.
+	settleTimeout = 5 * time.Second
+)
+
+// Signature headers. X-Webhook-Signature is "t=<unix time>,v1=<hex>",
+// where the hex string is the HMAC-SHA256, keyed with the subscription
+// secret, of "<unix time>.<body>". Receivers should recompute it and reject
+// old timestamps to stop replays.
+const (
+	IDHeader        = "X-Webhook-Id"
+	EventHeader     = "X-Webhook-Event"
+	SignatureHeader = "X-Webhook-Signature"
+)
+
+// Queue holds the pending deliveries.
+type Queue interface {
+	Enqueue(ctx context.Context, eventID uuid.UUID, topic string, payload json.RawMessage, createdAt time.Time) error
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]postgres.PendingDelivery, error)
+	MarkDelivered(ctx context.Context, id uuid.UUID, statusCode int) error
+	MarkFailed(ctx context.Context, id uuid.UUID, statusCode int, cause error, backoff time.Duration, final bool) error
+	DeleteSettled(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// Enqueuer is the outbox.Publisher that queues webhook deliveries.
+type Enqueuer struct {
+	queue Queue
+}
+
+// NewEnqueuer creates an Enqueuer adding to queue.
+func NewEnqueuer(queue Queue) *Enqueuer {
+	return &Enqueuer{queue: queue}
+}
+
+// Publish implements outbox.Publisher.
+func (e *Enqueuer) Publish(ctx context.Context, msg outbox.Message) error {
+	return e.queue.Enqueue(ctx, msg.ID, msg.Topic, msg.Payload, msg.CreatedAt)
+}
+
+// Settings tune a Sender.
+type Settings struct {
+	// Interval is how often the queue is polled when idle.
+	Interval  time.Duration
+	BatchSize int
+	// MaxAttempts is how often a delivery is tried before it is given up.
+	MaxAttempts int
+	// MaxBackoff caps the delay between attempts.
+	MaxBackoff time.Duration
+	// Timeout bounds each request.
+	Timeout time.Duration
+	// Retention is how long settled deliveries stay in the log; zero keeps
+	// them.
+	Retention time.Duration
+	// AllowPrivateNetworks lets endpoints resolve to loopback, private and
+	// link-local addresses. Keep it off in production: anyone registering a
+	// webhook could otherwise probe internal services.
+	AllowPrivateNetworks bool
+}
+
+// Sender sends queued deliveries. Several senders may share a queue.
+type Sender struct {
+	queue    Queue
+	client   *http.Client
+	settings Settings
+	logger   *logger.Logger
+}
+
+// NewSender creates a sender for queue.
+func NewSender(queue Queue, settings Settings, logger *logger.Logger) *Sender {
+	dialer := &net.Dialer{Timeout: settings.Timeout}
+	if !settings.AllowPrivateNetworks {
+		dialer.Control = denyPrivate
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	transport.Proxy = nil
+
+	return &Sender{
+		queue: queue,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   settings.Timeout,
+			// Redirects could lead to addresses the dialer would refuse
+			// only after the first hop; treat them as failures instead.
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		settings: settings,
+		logger:   logger,
+	}
+}
+
+// Run sends deliveries until ctx is done. Full batches are followed by the
+// next one right away.
+func (s *Sender) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.settings.Interval)
+	defer ticker.Stop()
+
+	lastCleanup := time.Time{}
+	for {
+		if s.settings.Retention > 0 && time.Since(lastCleanup) >= cleanupEvery {
+			s.cleanup(ctx)
+			lastCleanup = time.Now()
+		}
+
+		n := s.sendBatch(ctx)
+		if n == s.settings.BatchSize && ctx.Err() == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sendBatch sends one batch of due deliveries and reports how many it
+// claimed.
+func (s *Sender) sendBatch(ctx context.Context) int {
+	batch, err := s.queue.Claim(ctx, s.settings.BatchSize, lease)
+	if err != nil {
+		if ctx.Err() == nil {
+			s.logger.Error("failed to claim webhook deliveries", zap.Error(err))
+		}
+		return 0
+	}
+
+	var g errgroup.Group
+	g.SetLimit(parallelism)
+	for _, d := range batch {
+		d := d
+		g.Go(func() error {
+			s.deliver(ctx, d)
+			return nil
+		})
+	}
+	g.Wait()
+
+	return len(batch)
+}
+
+// deliver makes one attempt at d and records its outcome.
+func (s *Sender) deliver(ctx context.Context, d postgres.PendingDelivery) {
+	statusCode, err := s.post(ctx, d)
+
+	settleCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), settleTimeout)
+	defer cancel()
+
+	if err == nil {
+		deliveries.WithLabelValues("delivered").Inc()
+		err = s.queue.MarkDelivered(settleCtx, d.ID, statusCode)
+	} else {
+		final := d.Attempts+1 >= s.settings.MaxAttempts
+		result := "retry"
+		if final {
+			result = "failed"
+		}
+		deliveries.WithLabelValues(result).Inc()
+		s.logger.Warn("webhook delivery failed",
+			zap.String("delivery_id", d.ID.String()),
+			zap.String("event_id", d.EventID.String()),
+			zap.Int("attempts", d.Attempts+1),
+			zap.Bool("final", final),
+			zap.Error(err),
+		)
+		err = s.queue.MarkFailed(settleCtx, d.ID, statusCode, err, s.backoff(d.Attempts), final)
+	}
+	if err != nil {
+		// The delivery is claimed again once its lease expires.
+		s.logger.Error("failed to settle webhook delivery",
+			zap.String("delivery_id", d.ID.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+// post sends d and returns the endpoint's status code, zero when it was not
+// reached. Only 2xx answers count as delivered.
+func (s *Sender) post(ctx context.Context, d postgres.PendingDelivery) (int, error) {
+	body, err := json.Marshal(struct {
+		ID        uuid.UUID       `json:"id"`
+		Event     string          `json:"event"`
+		CreatedAt time.Time       `json:"created_at"`
+		Data      json.RawMessage `json:"data"`
+	}{d.EventID, d.Topic, d.CreatedAt, d.Payload})
+	if err != nil {
+		return 0, fmt.Errorf("encode webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "usermanagement-webhooks")
+	req.Header.Set(IDHeader, d.EventID.String())
+	req.Header.Set(EventHeader, d.Topic)
+	req.Header.Set(SignatureHeader, Sign(d.Secret, time.Now(), body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign returns the SignatureHeader value for body sent at t.
+func Sign(secret string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// backoff returns the delay before retrying a delivery that failed
+// attempts times before.
+func (s *Sender) backoff(attempts int) time.Duration {
+	d := firstRetry
+	for i := 0; i < attempts && d < s.settings.MaxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, s.settings.MaxBackoff)
+}
+
+// cleanup deletes settled deliveries older than Retention.
+func (s *Sender) cleanup(ctx context.Context) {
+	deleted, err := s.queue.DeleteSettled(ctx, time.Now().Add(-s.settings.Retention))
+	if err != nil {
+		if ctx.Err() == nil {
+			s.logger.Error("failed to delete settled webhook deliveries", zap.Error(err))
+		}
+		return
+	}
+	if deleted > 0 {
+		s.logger.Debug("deleted settled webhook deliveries", zap.Int64("count", deleted))
+	}
+}
+
+// errPrivateAddress refuses connections to internal addresses.
+var errPrivateAddress = errors.New("webhook endpoint resolves to a private address")
+
+// denyPrivate is a net.Dialer Control refusing loopback, private,
+// link-local and unspecified addresses. It sees the resolved address, so
+// DNS names pointing inside are refused too.
+func denyPrivate(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsUnspecified() || ip.IsMulticast() {
+		return errPrivateAddress
+	}
+	return nil
+}